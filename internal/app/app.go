@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/handlers"
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/repository"
@@ -13,12 +14,29 @@ import (
 )
 
 type App struct {
-	server      *Server
-	db          *sql.DB
-	userRepo    repository.UserRepository
-	adminRepo 	repository.AdminRepository
-	jwtService  *services.JWTService
-	userHandler *handlers.UserHandler
+	server         *Server
+	db             *sql.DB
+	userRepo       repository.UserRepository
+	adminRepo      repository.AdminRepository
+	analyticsRepo  repository.AnalyticsRepository
+	jobRepo        repository.JobRepository
+	referralRepo   repository.ReferralRepository
+	companyRepo    repository.CompanyRepository
+	notifyRepo     repository.NotificationRepository
+	eeoRepo        repository.EEORepository
+	exportRepo     repository.DataExportRepository
+	accountRepo    repository.AccountRepository
+	skillRepo      repository.SkillRepository
+	mediaScanRepo  repository.MediaScanRepository
+	jwtService     *services.JWTService
+	analyticsCache *services.BucketCache
+	jobEvents      *services.JobEventTracker
+	accountPurger  *services.AccountPurger
+	mediaScanner   *services.MediaScanWorker
+	storage        services.FileStorage
+	backgroundJobs *services.BackgroundQueue
+	urlSigner      *services.URLSigner
+	userHandler    *handlers.UserHandler
 }
 
 func NewApp() (*App, error) {
@@ -31,17 +49,64 @@ func NewApp() (*App, error) {
 	// Initialize server
 	addr := env.GetEnv("ADDR", ":8080")
 
-	app := App{}
+	app := App{db: db}
 
 	// Initialize services
-	app.jwtService = services.NewJWTService()
+	app.analyticsCache = services.NewBucketCache(time.Duration(env.GetEnvAsInt("ANALYTICS_CACHE_BUCKET_MINUTES", 15)) * time.Minute)
+	app.storage, err = newFileStorage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	app.backgroundJobs = services.NewBackgroundQueue(env.GetEnvAsInt("DATA_EXPORT_WORKERS", 2), 100)
+
+	// Signed links grant access on their own, so their key must never fall back to a default
+	signingSecret := env.GetEnv("URL_SIGNING_SECRET", "")
+	if signingSecret == "" {
+		return nil, fmt.Errorf("URL_SIGNING_SECRET must be set")
+	}
+	app.urlSigner = services.NewURLSigner(signingSecret)
 
 	// Initialize repositories
 	app.userRepo = repository.NewUserRepository(db)
 	app.adminRepo = repository.NewAdminRepository(db, app.userRepo)
+	app.analyticsRepo = repository.NewAnalyticsRepository(db)
+	app.jobRepo = repository.NewJobRepository(db)
+	app.referralRepo = repository.NewReferralRepository(db)
+	app.companyRepo = repository.NewCompanyRepository(db)
+	app.notifyRepo = repository.NewNotificationRepository(db)
+	app.eeoRepo = repository.NewEEORepository(db)
+	app.exportRepo = repository.NewDataExportRepository(db)
+	app.accountRepo = repository.NewAccountRepository(db)
+	app.skillRepo = repository.NewSkillRepository(db)
+	app.mediaScanRepo = repository.NewMediaScanRepository(db)
+
+	// Tokens of accounts awaiting deletion are refused, so the account repository checks them
+	app.jwtService = services.NewJWTService(app.accountRepo)
+
+	// Job views and apply clicks are written in batches off the request path
+	app.jobEvents = services.NewJobEventTracker(
+		app.jobRepo,
+		env.GetEnvAsInt("JOB_EVENTS_BATCH_SIZE", 100),
+		time.Duration(env.GetEnvAsInt("JOB_EVENTS_FLUSH_SECONDS", 5))*time.Second,
+	)
+
+	// Accounts are purged once their deletion grace period is over
+	app.accountPurger = services.NewAccountPurger(
+		app.accountRepo,
+		app.storage,
+		time.Duration(env.GetEnvAsInt("ACCOUNT_PURGE_INTERVAL_MINUTES", 60))*time.Minute,
+	)
+
+	// Uploaded files are scanned for malware before recruiters get to see them
+	app.mediaScanner = services.NewMediaScanWorker(
+		app.mediaScanRepo,
+		app.storage,
+		newMalwareScanner(),
+		time.Duration(env.GetEnvAsInt("MEDIA_SCAN_INTERVAL_SECONDS", 60))*time.Second,
+	)
 
 	// Initialize handlers
-	app.userHandler = handlers.NewUserHandler(app.userRepo,app.adminRepo, app.jwtService)
+	app.userHandler = handlers.NewUserHandler(app.userRepo, app.adminRepo, app.analyticsRepo, app.jobRepo, app.referralRepo, app.companyRepo, app.notifyRepo, app.eeoRepo, app.exportRepo, app.accountRepo, app.skillRepo, app.mediaScanRepo, app.jwtService, app.analyticsCache, app.jobEvents, app.storage, app.backgroundJobs, app.urlSigner, app.mediaScanner)
 
 	app.server = NewServer(addr, routes.SetupRoutes(app.userHandler, app.jwtService))
 
@@ -52,7 +117,15 @@ func (a *App) Run() error {
 	log.Printf("Starting server on %s", a.server.Addr())
 	log.Printf("Swagger UI available at: http://localhost%s/api/v1/swagger/index.html", a.server.Addr())
 
-	return a.server.Start()
+	err := a.server.Start()
+
+	// Flush tracking events still buffered when the server stopped
+	a.jobEvents.Close()
+	a.backgroundJobs.Close()
+	a.accountPurger.Close()
+	a.mediaScanner.Close()
+
+	return err
 }
 
 func (a *App) Shutdown() error {
@@ -60,6 +133,11 @@ func (a *App) Shutdown() error {
 		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
 
+	a.jobEvents.Close()
+	a.backgroundJobs.Close()
+	a.accountPurger.Close()
+	a.mediaScanner.Close()
+
 	if err := a.db.Close(); err != nil {
 		return fmt.Errorf("failed to close database: %w", err)
 	}
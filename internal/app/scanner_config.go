@@ -0,0 +1,28 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// newMalwareScanner scans uploads with the clamd at CLAMD_ADDR (host:port). Without it every
+// file passes, which is only meant for development.
+func newMalwareScanner() services.MalwareScanner {
+	addr := env.GetEnv("CLAMD_ADDR", "")
+	if addr == "" {
+		log.Printf("CLAMD_ADDR is not set, uploaded files are not scanned for malware")
+		return services.NoopScanner{}
+	}
+
+	scanner := services.NewClamdScanner(addr, time.Duration(env.GetEnvAsInt("CLAMD_TIMEOUT_SECONDS", 30))*time.Second)
+	// Files are only queued while clamd is down, so it does not have to be up yet
+	if err := scanner.Ping(); err != nil {
+		log.Printf("clamd at %s is not answering yet: %v", addr, err)
+	} else {
+		log.Printf("Scanning uploaded files for malware with clamd at %s", addr)
+	}
+	return scanner
+}
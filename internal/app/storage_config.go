@@ -0,0 +1,34 @@
+package app
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// newFileStorage picks the storage backend for uploaded files, STORAGE_BACKEND=local (default) or s3
+func newFileStorage() (services.FileStorage, error) {
+	switch backend := env.GetEnv("STORAGE_BACKEND", "local"); backend {
+	case "local":
+		return services.NewLocalStorage(env.GetEnv("MEDIA_ROOT", "./uploads")), nil
+	case "s3":
+		storage, err := services.NewS3Storage(services.S3Config{
+			Endpoint:        env.GetEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          env.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:          env.GetEnv("S3_BUCKET", ""),
+			AccessKeyID:     env.GetEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: env.GetEnv("S3_SECRET_ACCESS_KEY", ""),
+			// MinIO and most self-hosted stand-ins only serve path-style URLs
+			PathStyle: env.GetEnv("S3_PATH_STYLE", "true") == "true",
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Storing uploaded files in S3 bucket %s", env.GetEnv("S3_BUCKET", ""))
+		return storage, nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q, expected local or s3", backend)
+	}
+}
@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FunnelQuery narrows hiring analytics to a job, a company and/or an applied_at range.
+// To is exclusive.
+type FunnelQuery struct {
+	JobID     *uuid.UUID
+	CompanyID *uuid.UUID
+	From      *time.Time
+	To        *time.Time
+}
@@ -14,6 +14,30 @@ type CreateCompanyResponse struct {
 }
 
 type UpdateCompanyRequest struct {
-	Name        *string `json:"name"`
-	Description *string `json:"description"`
-}
\ No newline at end of file
+	Name         *string                 `json:"name"`
+	Description  *string                 `json:"description"`
+	Website      *string                 `json:"website" validate:"omitempty,url"`
+	Industry     *string                 `json:"industry"`
+	SizeBand     *string                 `json:"size_band" validate:"omitempty,oneof=1-10 11-50 51-200 201-500 501-1000 1001-5000 5001+"`
+	Headquarters *string                 `json:"headquarters"`
+	SocialLinks  map[string]string       `json:"social_links" validate:"omitempty,dive,keys,required,endkeys,url"`
+	Offices      *[]CompanyOfficeRequest `json:"offices" validate:"omitempty,dive"`
+}
+
+type CompanyOfficeRequest struct {
+	City    string `json:"city" validate:"required"`
+	Country string `json:"country"`
+	Address string `json:"address"`
+}
+type SetCompanyTrustedRequest struct {
+	IsTrusted *bool `json:"is_trusted" validate:"required"`
+}
+
+type CreateCompanyUpdateRequest struct {
+	Body string `json:"body" validate:"required,min=3,max=1000"`
+}
+
+type FollowerCountResponse struct {
+	CompanyID     string `json:"company_id"`
+	FollowerCount int    `json:"follower_count"`
+}
@@ -11,6 +11,12 @@ type CreateUserRequest struct {
 	FullName *string `json:"full_name" validate:"required,min=6"`
 }
 
+type ApplicantSignUpRequest struct {
+	CreateUserRequest
+	// ReferralCode is remembered so a later application to the referred job is attributed
+	ReferralCode string `json:"referral_code,omitempty"`
+}
+
 type CreateAdminRequest struct {
 	CreateUserRequest
 	AdminLevel int `json:"admin_level" validate:"required,min=1,max=5"`
@@ -18,7 +24,8 @@ type CreateAdminRequest struct {
 
 type CreateRecruiterRequest struct {
 	CreateUserRequest
-	CompanyID *uuid.UUID `json:"company_id" validate:"required"`
+	CompanyID      *uuid.UUID `json:"company_id" validate:"required"`
+	IsCompanyOwner bool       `json:"is_company_owner"`
 }
 
 
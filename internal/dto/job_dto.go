@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+type ApplyToJobRequest struct {
+	Answers []ApplicationAnswerRequest `json:"answers" validate:"dive"`
+	// ReferralCode attributes the application to a referral link for this job
+	ReferralCode string `json:"referral_code,omitempty"`
+	// ShareNameWithReferrer lets the referrer see the applicant's name before the reveal stage
+	ShareNameWithReferrer bool `json:"share_name_with_referrer,omitempty"`
+	// SelfIdentification is voluntary and never shown to recruiters
+	SelfIdentification *EEOSelfIdentificationRequest `json:"self_identification,omitempty"`
+}
+
+// EEOSelfIdentificationRequest holds voluntary equal-opportunity answers, each question may be skipped
+type EEOSelfIdentificationRequest struct {
+	Gender           *string `json:"gender" validate:"omitempty,oneof=female male non_binary other decline"`
+	Ethnicity        *string `json:"ethnicity" validate:"omitempty,oneof=hispanic_or_latino white black_or_african_american asian native_hawaiian_or_pacific_islander american_indian_or_alaska_native two_or_more decline"`
+	VeteranStatus    *string `json:"veteran_status" validate:"omitempty,oneof=protected_veteran not_a_veteran decline"`
+	DisabilityStatus *string `json:"disability_status" validate:"omitempty,oneof=yes no decline"`
+}
+
+type ApplicationAnswerRequest struct {
+	QuestionID uuid.UUID `json:"question_id" validate:"required"`
+	Answer     string    `json:"answer"`
+}
+
+type CreateReferralLinkRequest struct {
+	JobID uuid.UUID `json:"job_id" validate:"required"`
+}
+
+type CreateJobRequest struct {
+	Title       string                             `json:"title" validate:"required,min=3"`
+	Description string                             `json:"description"`
+	Location    string                             `json:"location"`
+	SalaryRange string                             `json:"salary_range"`
+	Questions   []CreateApplicationQuestionRequest `json:"questions" validate:"dive"`
+}
+
+type CreateApplicationQuestionRequest struct {
+	Question   string `json:"question" validate:"required"`
+	IsRequired bool   `json:"is_required"`
+}
+
+type UpdateJobRequest struct {
+	Title       string `json:"title" validate:"required,min=3"`
+	Description string `json:"description"`
+	Location    string `json:"location"`
+	SalaryRange string `json:"salary_range"`
+}
+
+type ModerateJobRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approve reject request_changes"`
+	Reason   string `json:"reason"`
+}
+
+type SetBlindHiringRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+	// Stage at which the applicant's identity is revealed, defaults to interview
+	RevealAtStatus string `json:"reveal_at_status" validate:"omitempty,oneof=screening interview offer accepted"`
+}
+
+type UpdateApplicationStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending screening interview offer accepted rejected"`
+}
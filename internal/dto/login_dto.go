@@ -12,4 +12,9 @@ type LoginRequest struct {
 type LoginResponse struct {
 	Token string      `json:"token"`
 	User  models.User `json:"user"`
-}
\ No newline at end of file
+}
+
+// DeleteAccountRequest confirms an account deletion with the account's password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
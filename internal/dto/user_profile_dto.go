@@ -1,6 +1,30 @@
 package dto
 
+import "github.com/google/uuid"
+
 // DTO structures for requests
+
+// UpdateProfileRequest changes the basic profile fields, fields left out stay as they are
+type UpdateProfileRequest struct {
+	FullName     *string `json:"full_name" validate:"omitempty,min=6"`
+	Location     *string `json:"location" validate:"omitempty,max=200"`
+	Title        *string `json:"title" validate:"omitempty,max=200"`
+	AboutSection *string `json:"about_section" validate:"omitempty,max=5000"`
+}
+
+// UpdateProfileVisibilityRequest changes where and to whom the profile is shown, fields left
+// out stay as they are. An empty slug removes the public link.
+type UpdateProfileVisibilityRequest struct {
+	Slug      *string           `json:"slug" validate:"omitempty,min=3,max=50"`
+	Published *bool             `json:"published"`
+	Sections  map[string]string `json:"sections" validate:"omitempty,dive,keys,oneof=phone_numbers education experience certifications projects skills profile_picture,endkeys,oneof=public recruiters private"`
+}
+
+// SetEntryVisibilityRequest overrides the visibility of a single entry, null makes it follow its section
+type SetEntryVisibilityRequest struct {
+	Visibility *string `json:"visibility" validate:"omitempty,oneof=public recruiters private"`
+}
+
 type CreatePhoneNumberRequest struct {
 	PhoneNumber string `json:"phone_number" validate:"required"`
 	PhoneType   string `json:"phone_type" validate:"required,oneof=mobile home work other"`
@@ -50,5 +74,56 @@ type CreateProjectRequest struct {
 }
 
 type AddSkillsRequest struct {
-	SkillIDs []int `json:"skill_ids" validate:"required,min=1"`
-}
\ No newline at end of file
+	SkillIDs   []int              `json:"skill_ids"`
+	SkillNames []string           `json:"skill_names" validate:"dive,required"` // names or aliases
+	Skills     []UserSkillRequest `json:"skills" validate:"dive"`               // with how the skill was used
+}
+
+// UserSkillRequest adds a skill by ID or by name or alias, not both, together with its details
+type UserSkillRequest struct {
+	SkillID *int   `json:"skill_id" validate:"required_without=Name,excluded_with=Name"`
+	Name    string `json:"name"`
+	UserSkillDetails
+}
+
+// UserSkillDetails is how a user used a skill. Experience and project entries must be the user's own.
+type UserSkillDetails struct {
+	Proficiency   *string     `json:"proficiency" validate:"omitempty,oneof=beginner intermediate advanced expert"`
+	YearsUsed     *int        `json:"years_used" validate:"omitempty,min=0,max=70"`
+	LastUsedYear  *int        `json:"last_used_year" validate:"omitempty,min=1950"`
+	ExperienceIDs []uuid.UUID `json:"experience_ids"`
+	ProjectIDs    []uuid.UUID `json:"project_ids"`
+}
+
+type SkillRequest struct {
+	Name       string   `json:"name" validate:"required,max=100"`
+	CategoryID *int     `json:"category_id"`
+	ParentID   *int     `json:"parent_id"`
+	Aliases    []string `json:"aliases" validate:"omitempty,dive,required,max=100"`
+}
+
+type SkillCategoryRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type ReviewSkillSuggestionRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// Profile import modes
+const (
+	ImportModeMerge   = "merge"   // add what is new, leave existing entries alone
+	ImportModeReplace = "replace" // make the profile match the import, updating matching entries
+)
+
+// ProfileImport is profile data from an outside source mapped onto the regular create requests.
+// Basic fields left nil are not in the source and stay as they are.
+type ProfileImport struct {
+	Basics         UpdateProfileRequest
+	PhoneNumbers   []CreatePhoneNumberRequest
+	Education      []CreateEducationRequest
+	Experience     []CreateExperienceRequest
+	Certifications []CreateCertificationRequest
+	Projects       []CreateProjectRequest
+	Skills         []string
+}
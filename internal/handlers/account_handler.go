@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// HandleRequestAccountDeletion handles a user asking for their account to be deleted
+// @Summary Request Account Deletion
+// @Description Schedule the caller's account for deletion. Signing in is refused and tokens already issued stop working from now on. Until the grace period ends the deletion can be cancelled, after it the profile is deleted and applications are kept only in anonymized form.
+// @Tags User
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.DeleteAccountRequest true "Password confirmation"
+// @Success 202 {object} models.AccountDeletion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/account/deletion [post]
+func (h *UserHandler) HandleRequestAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(claims.UserID)
+	if err != nil {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		h.writeErrorResponse(w, "Invalid password", http.StatusUnauthorized)
+		return
+	}
+
+	// Admin accounts are managed by other admins, deleting one could lock the platform out
+	if user.Role == "admin" {
+		h.writeErrorResponse(w, "Admin accounts cannot be deleted", http.StatusForbidden)
+		return
+	}
+
+	graceDays := env.GetEnvAsInt("ACCOUNT_DELETION_GRACE_DAYS", 30)
+	deletion, err := h.accountRepo.RequestAccountDeletion(user.ID, time.Now().AddDate(0, 0, graceDays))
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to request account deletion: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, deletion, http.StatusAccepted)
+}
+
+// HandleCancelAccountDeletion handles cancelling a pending account deletion
+// @Summary Cancel Account Deletion
+// @Description Cancel the deletion of an account during its grace period. Since the account can no longer sign in, the cancellation is authenticated with the account's credentials and signs the user in.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param credentials body dto.LoginRequest true "Account credentials"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/account/deletion/cancel [post]
+func (h *UserHandler) HandleCancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		h.writeErrorResponse(w, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.accountRepo.CancelAccountDeletion(user.ID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Account is not scheduled for deletion", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to cancel account deletion: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	user.DeletionScheduledFor = nil
+
+	token, err := h.jwtService.GenerateToken(*user)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.LoginResponse{
+		Token: token,
+		User:  *user,
+	}
+
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
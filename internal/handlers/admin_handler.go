@@ -176,17 +176,9 @@ func (h *UserHandler)HandleUpdateCompany(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	//Validation for at least one filed exist and should be at least 6 characters long
-	if req.Name == nil && req.Description == nil {
-		h.writeErrorResponse(w, "At least one field (name or description) must be provided for update", http.StatusBadRequest)
-		return
-	}
-	if req.Name != nil && len(*req.Name) < 6 {
-		h.writeErrorResponse(w, "Company name must be at least 6 characters long", http.StatusBadRequest)
-		return
-	}
-	if req.Description != nil && len(*req.Description) < 6 {
-		h.writeErrorResponse(w, "Company description must be at least 6 characters long", http.StatusBadRequest)
+	// At least one field must be given and name/description keep their minimum length
+	if message := validateCompanyUpdate(req); message != "" {
+		h.writeErrorResponse(w, message, http.StatusBadRequest)
 		return
 	}
 
@@ -201,7 +193,7 @@ func (h *UserHandler)HandleUpdateCompany(w http.ResponseWriter, r *http.Request)
 	}
 	response := dto.CreateCompanyResponse{
 		Message: "Company updated successfully",
-		Company: *company,
+		Company: *withLogoURL(company),
 	}
 	h.writeJSONResponse(w, response, http.StatusOK)
 
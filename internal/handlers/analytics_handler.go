@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// HandleGetRecruiterFunnel handles hiring funnel analytics for the recruiter's company
+// @Summary Get Hiring Funnel (Recruiter)
+// @Description Applications per stage, stage conversion, median time in stage, drop-off and time-to-hire for the recruiter's company
+// @Tags Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param job_id query string false "Job ID"
+// @Param from query string false "Applied on or after (YYYY-MM-DD)"
+// @Param to query string false "Applied before (YYYY-MM-DD)"
+// @Success 200 {object} models.HiringFunnel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/analytics/funnel [get]
+func (h *UserHandler) HandleGetRecruiterFunnel(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query, err := parseFunnelQuery(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil {
+		h.writeErrorResponse(w, "Recruiter is not assigned to a company", http.StatusForbidden)
+		return
+	}
+	// Recruiters only ever see their own company's pipeline
+	query.CompanyID = recruiter.CompanyID
+
+	h.writeHiringFunnel(w, query)
+}
+
+// HandleGetAdminFunnel handles hiring funnel analytics across the platform (admin-only)
+// @Summary Get Hiring Funnel (Admin)
+// @Description Applications per stage, stage conversion, median time in stage, drop-off and time-to-hire for any job or company
+// @Tags Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param job_id query string false "Job ID"
+// @Param company_id query string false "Company ID"
+// @Param from query string false "Applied on or after (YYYY-MM-DD)"
+// @Param to query string false "Applied before (YYYY-MM-DD)"
+// @Success 200 {object} models.HiringFunnel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/analytics/funnel [get]
+func (h *UserHandler) HandleGetAdminFunnel(w http.ResponseWriter, r *http.Request) {
+	query, err := parseFunnelQuery(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if companyID := r.URL.Query().Get("company_id"); companyID != "" {
+		id, err := uuid.Parse(companyID)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+			return
+		}
+		query.CompanyID = &id
+	}
+
+	h.writeHiringFunnel(w, query)
+}
+
+// HandleGetEEOReport handles the aggregated equal-opportunity report (admin-only)
+// @Summary Get EEO Report
+// @Description Voluntary self-identification answers per question with applicant and hire counts. Dates are widened to whole months. A bucket is suppressed when fewer than EEO_MIN_BUCKET_SIZE applicants of one job in one month gave that answer, so no combination of filters can single anyone out; hire counts follow the same rule for hired and not hired applicants.
+// @Tags Analytics
+// @Security BearerAuth
+// @Produce json
+// @Param job_id query string false "Job ID"
+// @Param company_id query string false "Company ID"
+// @Param from query string false "Applied on or after (YYYY-MM-DD)"
+// @Param to query string false "Applied before (YYYY-MM-DD)"
+// @Success 200 {object} models.EEOReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/analytics/eeo [get]
+func (h *UserHandler) HandleGetEEOReport(w http.ResponseWriter, r *http.Request) {
+	query, err := parseFunnelQuery(r)
+	if err != nil {
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if companyID := r.URL.Query().Get("company_id"); companyID != "" {
+		id, err := uuid.Parse(companyID)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+			return
+		}
+		query.CompanyID = &id
+	}
+
+	// Buckets of one are never safe to show, whatever the configuration says
+	minBucketSize := env.GetEnvAsInt("EEO_MIN_BUCKET_SIZE", 5)
+	if minBucketSize < 2 {
+		minBucketSize = 2
+	}
+
+	report, err := h.eeoRepo.GetEEOReport(query, minBucketSize)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to compute EEO report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, report, http.StatusOK)
+}
+
+func (h *UserHandler) writeHiringFunnel(w http.ResponseWriter, query dto.FunnelQuery) {
+	key := funnelCacheKey(query)
+	if cached, ok := h.analyticsCache.Get(key); ok {
+		h.writeJSONResponse(w, cached, http.StatusOK)
+		return
+	}
+
+	funnel, err := h.analyticsRepo.GetHiringFunnel(query)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to compute hiring funnel: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.analyticsCache.Set(key, funnel)
+
+	h.writeJSONResponse(w, funnel, http.StatusOK)
+}
+
+func parseFunnelQuery(r *http.Request) (dto.FunnelQuery, error) {
+	var query dto.FunnelQuery
+	params := r.URL.Query()
+
+	if jobID := params.Get("job_id"); jobID != "" {
+		id, err := uuid.Parse(jobID)
+		if err != nil {
+			return query, errors.New("Invalid job ID format")
+		}
+		query.JobID = &id
+	}
+
+	if from := params.Get("from"); from != "" {
+		t, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return query, errors.New("Invalid from date, expected YYYY-MM-DD")
+		}
+		query.From = &t
+	}
+
+	if to := params.Get("to"); to != "" {
+		t, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return query, errors.New("Invalid to date, expected YYYY-MM-DD")
+		}
+		query.To = &t
+	}
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return query, errors.New("from must be before to")
+	}
+
+	return query, nil
+}
+
+func funnelCacheKey(query dto.FunnelQuery) string {
+	key := "funnel"
+	for _, id := range []*uuid.UUID{query.JobID, query.CompanyID} {
+		if id != nil {
+			key += ":" + id.String()
+		} else {
+			key += ":-"
+		}
+	}
+	for _, t := range []*time.Time{query.From, query.To} {
+		if t != nil {
+			key += ":" + t.Format(time.DateOnly)
+		} else {
+			key += ":-"
+		}
+	}
+	return key
+}
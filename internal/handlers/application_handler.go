@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleSetBlindHiring handles turning blind hiring on or off for a job
+// @Summary Set Blind Hiring
+// @Description While blind hiring is on, application views hide the applicant's name, email, phone numbers, profile picture, location, title, about section and schools until the application reaches the reveal stage
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param request body dto.SetBlindHiringRequest true "Blind hiring settings"
+// @Success 200 {object} models.Job
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs/{jobID}/blind-hiring [put]
+func (h *UserHandler) HandleSetBlindHiring(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.authorizeRecruiterJob(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SetBlindHiringRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.jobRepo.SetBlindHiring(job.ID, *req.Enabled, req.RevealAtStatus)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to update job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, updated, http.StatusOK)
+}
+
+// HandleGetJobApplications handles listing the applications to a job
+// @Summary Get Job Applications
+// @Description List applications to a job at the caller's company, oldest first. Applicants of blind hiring jobs are anonymized until the reveal stage. Each applicant carries the completeness score of the profile the application shows, the one submitted with it where there is one, as recruiters see it; min_completeness leaves out applicants below it. Skills are matched against the same profile. With skills only applicants with every one of them are listed, together with their matching skills; a narrower skill counts for a broader one (React for JavaScript) and names are resolved through aliases. min_proficiency, min_years and used_since apply to each skill, years and the year last used come from the linked experience and project entries when the applicant did not enter them.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param min_completeness query int false "Lowest profile completeness score to include (0-100)"
+// @Param skills query string false "Comma-separated skill names or aliases applicants must have"
+// @Param min_proficiency query string false "Lowest proficiency for each skill" Enums(beginner, intermediate, advanced, expert)
+// @Param min_years query int false "Fewest years each skill was used"
+// @Param used_since query int false "Year each skill was last used in or after"
+// @Success 200 {array} models.ApplicationView
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs/{jobID}/applications [get]
+func (h *UserHandler) HandleGetJobApplications(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.authorizeRecruiterJob(w, r)
+	if !ok {
+		return
+	}
+
+	minCompleteness := 0
+	if value := r.URL.Query().Get("min_completeness"); value != "" {
+		var err error
+		minCompleteness, err = strconv.Atoi(value)
+		if err != nil || minCompleteness < 0 || minCompleteness > 100 {
+			h.writeErrorResponse(w, "Invalid min_completeness, expected a number from 0 to 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	criteria, ok := h.skillCriteria(w, r)
+	if !ok {
+		return
+	}
+
+	applications, err := h.jobRepo.GetApplicationsByJob(job.ID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get applications: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Applicants are scored and matched on the profile the application shows: the one submitted
+	// with it, or for applications from before snapshots the current one
+	snapshots, err := h.jobRepo.GetApplicationSnapshotsByJob(job.ID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get applicant profiles: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var unsnapshotted []uuid.UUID
+	for _, application := range applications {
+		if _, ok := snapshots[application.ID]; !ok {
+			unsnapshotted = append(unsnapshotted, application.ApplicantID)
+		}
+	}
+	current, err := h.userRepo.GetUserProfilesByIDs(unsnapshotted, models.ViewerRecruiter)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get applicant profiles: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The list only carries the applicant's basic details, completeness score and the skills
+	// that matched, the full profile is on the application itself
+	listed := []models.ApplicationView{}
+	now := time.Now()
+	for _, application := range applications {
+		var profile *models.UserProfile
+		if snapshot, ok := snapshots[application.ID]; ok {
+			profile = &snapshot.Profile
+			application.ProfileSubmittedAt = &snapshot.CreatedAt
+		} else if profile, ok = current[application.ApplicantID]; !ok {
+			continue
+		}
+		completeness := profile.ScoreCompleteness()
+		if completeness.Score < minCompleteness {
+			continue
+		}
+		var matched []models.Skill
+		if criteria != nil {
+			if matched, ok = profile.MatchSkills(*criteria, now); !ok {
+				continue
+			}
+		}
+		application.Applicant = &models.UserProfile{
+			User:         profile.User,
+			Skills:       matched,
+			Completeness: &models.ProfileCompleteness{Score: completeness.Score},
+		}
+		listed = append(listed, application)
+	}
+
+	h.writeJSONResponse(w, listed, http.StatusOK)
+}
+
+// HandleGetApplication handles one application with the applicant's full profile
+// @Summary Get Application
+// @Description Get an application to a job at the caller's company with its answers and the applicant's profile
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Param applicationID path string true "Application ID"
+// @Success 200 {object} models.ApplicationView
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/applications/{applicationID} [get]
+func (h *UserHandler) HandleGetApplication(w http.ResponseWriter, r *http.Request) {
+	application, ok := h.authorizeRecruiterApplication(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeApplicationView(w, application, http.StatusOK)
+}
+
+// HandleUpdateApplicationStatus handles moving an application through the hiring pipeline
+// @Summary Update Application Status
+// @Description Move an application to another pipeline stage or reject it
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param applicationID path string true "Application ID"
+// @Param request body dto.UpdateApplicationStatusRequest true "New status"
+// @Success 200 {object} models.ApplicationView
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/applications/{applicationID}/status [patch]
+func (h *UserHandler) HandleUpdateApplicationStatus(w http.ResponseWriter, r *http.Request) {
+	application, ok := h.authorizeRecruiterApplication(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateApplicationStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.jobRepo.UpdateApplicationStatus(application.ID, req.Status); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, "Application not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "withdrawn"):
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+		default:
+			h.writeErrorResponse(w, "Failed to update application: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Reload so the response reflects whether the applicant is now revealed
+	updated, err := h.jobRepo.GetApplicationByID(application.ID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get application: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeApplicationView(w, updated, http.StatusOK)
+}
+
+// writeApplicationView attaches the applicant's profile as submitted and writes the view,
+// which redacts the applicant's identity itself when it is anonymized
+func (h *UserHandler) writeApplicationView(w http.ResponseWriter, application *models.ApplicationView, statusCode int) {
+	profile, err := h.applicantProfile(application)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get applicant profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	profile.Completeness = &models.ProfileCompleteness{Score: profile.ScoreCompleteness().Score}
+	application.Applicant = profile
+
+	h.writeJSONResponse(w, application, statusCode)
+}
+
+// authorizeRecruiterApplication loads the application in the URL and checks that
+// it is for a job at the calling recruiter's company
+func (h *UserHandler) authorizeRecruiterApplication(w http.ResponseWriter, r *http.Request) (*models.ApplicationView, bool) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	applicationID, err := uuid.Parse(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid application ID format", http.StatusBadRequest)
+		return nil, false
+	}
+
+	application, err := h.jobRepo.GetApplicationByID(applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Application not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.writeErrorResponse(w, "Failed to get application: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	job, err := h.jobRepo.GetJobByID(application.JobID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get job: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil || job.CompanyID == nil || *recruiter.CompanyID != *job.CompanyID {
+		h.writeErrorResponse(w, "Application is not for a job at your company", http.StatusForbidden)
+		return nil, false
+	}
+
+	return application, true
+}
+
+// skillCriteria reads the skills applicants are filtered on from the query, nil when there are none
+func (h *UserHandler) skillCriteria(w http.ResponseWriter, r *http.Request) (*models.SkillCriteria, bool) {
+	query := r.URL.Query()
+	var names []string
+	for _, name := range strings.Split(query.Get("skills"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		if query.Get("min_proficiency") != "" || query.Get("min_years") != "" || query.Get("used_since") != "" {
+			h.writeErrorResponse(w, "min_proficiency, min_years and used_since need skills", http.StatusBadRequest)
+			return nil, false
+		}
+		return nil, true
+	}
+
+	criteria := models.SkillCriteria{MinProficiency: query.Get("min_proficiency")}
+	switch criteria.MinProficiency {
+	case "", models.ProficiencyBeginner, models.ProficiencyIntermediate, models.ProficiencyAdvanced, models.ProficiencyExpert:
+	default:
+		h.writeErrorResponse(w, "min_proficiency must be beginner, intermediate, advanced or expert", http.StatusBadRequest)
+		return nil, false
+	}
+	if value := query.Get("min_years"); value != "" {
+		years, err := strconv.Atoi(value)
+		if err != nil || years < 0 {
+			h.writeErrorResponse(w, "Invalid min_years, expected a whole number of years", http.StatusBadRequest)
+			return nil, false
+		}
+		criteria.MinYears = years
+	}
+	if value := query.Get("used_since"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid used_since, expected a year", http.StatusBadRequest)
+			return nil, false
+		}
+		criteria.UsedSince = year
+	}
+
+	requirements, err := h.skillRepo.GetSkillRequirements(names)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		h.writeErrorResponse(w, "Failed to look up skills: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	criteria.Skills = requirements
+	return &criteria, true
+}
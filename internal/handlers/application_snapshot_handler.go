@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleGetApplicationProfileDiff handles comparing the profile submitted with an application to the current one
+// @Summary Get Application Profile Diff
+// @Description Show what the applicant changed on their profile since applying: edited name, title, location and about section, a new or removed profile picture, and entries added, removed or edited. Only what recruiters can see is compared, and blind hiring redactions apply to both sides.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Param applicationID path string true "Application ID"
+// @Success 200 {object} models.ProfileDiff
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/applications/{applicationID}/profile-diff [get]
+func (h *UserHandler) HandleGetApplicationProfileDiff(w http.ResponseWriter, r *http.Request) {
+	application, ok := h.authorizeRecruiterApplication(w, r)
+	if !ok {
+		return
+	}
+
+	snapshot, err := h.jobRepo.GetApplicationSnapshot(application.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "No profile was saved with this application", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get submitted profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	current, err := h.userRepo.GetUserProfileByID(application.ApplicantID, models.ViewerRecruiter)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get applicant profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	submitted, now := snapshot.Profile.WithScannedMediaOnly(), current.WithScannedMediaOnly()
+	h.signSnapshotPictureURL(snapshot, &submitted)
+	if application.Anonymized {
+		alias := models.CandidateAlias(application.ID)
+		submitted, now = submitted.Anonymize(alias), now.Anonymize(alias)
+	}
+
+	diff, err := models.DiffProfiles(submitted, now, snapshot.CreatedAt)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to compare profiles: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, diff, http.StatusOK)
+}
+
+// HandleDownloadSnapshotMedia handles downloading a file submitted with an application through its signed URL
+// @Summary Download Submitted Media
+// @Description Download a copy of a file as it was when the applicant applied. The url on the file in the application's profile carries its own authorization and stops working after a few minutes.
+// @Tags Media
+// @Produce octet-stream
+// @Param applicationID path string true "Application ID"
+// @Param mediaID path string true "Media ID"
+// @Param expires query string true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /applications/{applicationID}/media/{mediaID} [get]
+func (h *UserHandler) HandleDownloadSnapshotMedia(w http.ResponseWriter, r *http.Request) {
+	applicationID, err := uuid.Parse(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid application ID format", http.StatusBadRequest)
+		return
+	}
+	mediaID, err := uuid.Parse(chi.URLParam(r, "mediaID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid media ID format", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifyMediaLink(w, r, snapshotMediaPath(applicationID, mediaID)) {
+		return
+	}
+
+	media, err := h.jobRepo.GetSnapshotMedia(applicationID, mediaID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Media not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if media.ScanStatus == models.MediaScanQuarantined {
+		h.writeErrorResponse(w, "Media has been quarantined", http.StatusForbidden)
+		return
+	}
+
+	mimeType := "application/octet-stream"
+	if media.MimeType != nil {
+		mimeType = *media.MimeType
+	}
+	h.serveMediaFile(w, r, media.FilePath, mimeType, media.FileName, media.MediaType == "document", media.UpdatedAt)
+}
+
+// HandleDownloadSnapshotPicture handles downloading the profile picture submitted with an application through its signed URL
+// @Summary Download Submitted Profile Picture
+// @Description Download a copy of the applicant's profile picture as it was when they applied. The profile_picture_url of the application's profile carries its own authorization and stops working after a few minutes.
+// @Tags Media
+// @Produce image/jpeg
+// @Produce image/png
+// @Param applicationID path string true "Application ID"
+// @Param expires query string true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /applications/{applicationID}/picture [get]
+func (h *UserHandler) HandleDownloadSnapshotPicture(w http.ResponseWriter, r *http.Request) {
+	applicationID, err := uuid.Parse(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid application ID format", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifyMediaLink(w, r, snapshotPicturePath(applicationID)) {
+		return
+	}
+
+	picture, err := h.jobRepo.GetSnapshotPicture(applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Picture not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get picture: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := "image/jpeg"
+	if strings.HasSuffix(picture.FilePath, ".png") {
+		contentType = "image/png"
+	}
+	h.serveMediaFile(w, r, picture.FilePath, contentType, "", false, picture.UpdatedAt)
+}
+
+// snapshotApplicantProfile freezes the profile recruiters see with the application, copying its
+// picture and files so later edits and deletes do not reach them. Thumbnails are not kept, and
+// quarantined files and files missing from storage are left out.
+func (h *UserHandler) snapshotApplicantProfile(applicationID, applicantID uuid.UUID) error {
+	profile, err := h.userRepo.GetUserProfileByID(applicantID, models.ViewerRecruiter)
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	var picturePath *string
+	var copies []models.UserMedia
+	cleanUp := func() {
+		h.deleteMediaFiles(copies)
+		if picturePath != nil {
+			if err := h.storage.Delete(*picturePath); err != nil {
+				log.Printf("failed to delete media file %s: %v", *picturePath, err)
+			}
+		}
+	}
+
+	if picture := profile.User.ProfilePicture; picture != nil {
+		key := "applications/" + applicationID.String() + "/picture" + path.Ext(*picture)
+		err := h.copyStoredFile(*picture, key)
+		switch {
+		case errors.Is(err, services.ErrFileNotFound):
+			log.Printf("left the picture out of the profile snapshot of application %s: file is missing from storage", applicationID)
+			profile.User.ProfilePicture, profile.User.ProfilePictureURL = nil, nil
+		case err != nil:
+			return fmt.Errorf("failed to copy profile picture: %w", err)
+		default:
+			picturePath = &key
+		}
+	}
+
+	for _, media := range profileMediaLists(profile) {
+		var kept []models.UserMedia
+		for _, m := range *media {
+			if m.ScanStatus == models.MediaScanQuarantined {
+				continue
+			}
+			key := "applications/" + applicationID.String() + "/" + m.ID.String() + path.Ext(m.FilePath)
+			if err := h.copyStoredFile(m.FilePath, key); err != nil {
+				if errors.Is(err, services.ErrFileNotFound) {
+					log.Printf("left media %s out of the profile snapshot of application %s: file is missing from storage", m.ID, applicationID)
+					continue
+				}
+				cleanUp()
+				return fmt.Errorf("failed to copy %s: %w", m.FileName, err)
+			}
+			m.FilePath, m.Thumbnails = key, nil
+			kept = append(kept, m)
+			copies = append(copies, m)
+		}
+		*media = kept
+	}
+
+	if err := h.jobRepo.SaveApplicationSnapshot(applicationID, *profile, picturePath, copies); err != nil {
+		cleanUp()
+		return err
+	}
+	return nil
+}
+
+// applicantProfile returns the applicant's profile for a recruiter reviewing the application:
+// the one submitted with it, or for applications from before snapshots the current one. Files
+// that have not passed the malware scan are left out and the rest get download URLs.
+func (h *UserHandler) applicantProfile(application *models.ApplicationView) (*models.UserProfile, error) {
+	snapshot, err := h.jobRepo.GetApplicationSnapshot(application.ID)
+	if err == nil {
+		visible := snapshot.Profile.WithScannedMediaOnly()
+		h.signSnapshotMediaURLs(application.ID, &visible)
+		h.signSnapshotPictureURL(snapshot, &visible)
+		application.ProfileSubmittedAt = &snapshot.CreatedAt
+		return &visible, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+
+	profile, err := h.userRepo.GetUserProfileByID(application.ApplicantID, models.ViewerRecruiter)
+	if err != nil {
+		return nil, err
+	}
+	visible := profile.WithScannedMediaOnly()
+	h.signProfileMediaURLs(&visible)
+	return &visible, nil
+}
+
+// copyStoredFile copies a file within storage
+func (h *UserHandler) copyStoredFile(from, to string) error {
+	file, err := h.storage.Open(from)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return h.storage.Save(to, file)
+}
+
+// signSnapshotMediaURLs gives the files of a submitted profile download URLs for their copies
+func (h *UserHandler) signSnapshotMediaURLs(applicationID uuid.UUID, profile *models.UserProfile) {
+	expiresAt := mediaURLExpiry()
+	for _, media := range profileMediaLists(profile) {
+		for i := range *media {
+			url := h.urlSigner.Sign(snapshotMediaPath(applicationID, (*media)[i].ID), expiresAt)
+			(*media)[i].URL = &url
+		}
+	}
+}
+
+// signSnapshotPictureURL points the picture of a submitted profile at a download URL for its
+// copy. Snapshots from before pictures were copied keep the URL of the current picture.
+func (h *UserHandler) signSnapshotPictureURL(snapshot *models.ApplicationSnapshot, profile *models.UserProfile) {
+	if snapshot.PicturePath == nil {
+		return
+	}
+	url := h.urlSigner.Sign(snapshotPicturePath(snapshot.ApplicationID), mediaURLExpiry())
+	profile.User.ProfilePictureURL = &url
+}
+
+func snapshotPicturePath(applicationID uuid.UUID) string {
+	return "/api/v1/applications/" + applicationID.String() + "/picture"
+}
+
+func snapshotMediaPath(applicationID, mediaID uuid.UUID) string {
+	return "/api/v1/applications/" + applicationID.String() + "/media/" + mediaID.String()
+}
@@ -3,6 +3,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 	"strings"
 
@@ -14,18 +15,70 @@ import (
 )
 
 type UserHandler struct {
-	userRepo   repository.UserRepository
-	adminRepo  repository.AdminRepository
-	jwtService *services.JWTService
-	validator  *validator.Validate
+	userRepo       repository.UserRepository
+	adminRepo      repository.AdminRepository
+	analyticsRepo  repository.AnalyticsRepository
+	jobRepo        repository.JobRepository
+	referralRepo   repository.ReferralRepository
+	companyRepo    repository.CompanyRepository
+	notifyRepo     repository.NotificationRepository
+	eeoRepo        repository.EEORepository
+	exportRepo     repository.DataExportRepository
+	accountRepo    repository.AccountRepository
+	skillRepo      repository.SkillRepository
+	mediaScanRepo  repository.MediaScanRepository
+	jwtService     *services.JWTService
+	analyticsCache *services.BucketCache
+	jobEvents      *services.JobEventTracker
+	storage        services.FileStorage
+	backgroundJobs *services.BackgroundQueue
+	urlSigner      *services.URLSigner
+	mediaScanner   *services.MediaScanWorker
+	validator      *validator.Validate
 }
 
-func NewUserHandler(userRepo repository.UserRepository, adminRepo repository.AdminRepository, jwtService *services.JWTService) *UserHandler {
+func NewUserHandler(
+	userRepo repository.UserRepository,
+	adminRepo repository.AdminRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	jobRepo repository.JobRepository,
+	referralRepo repository.ReferralRepository,
+	companyRepo repository.CompanyRepository,
+	notifyRepo repository.NotificationRepository,
+	eeoRepo repository.EEORepository,
+	exportRepo repository.DataExportRepository,
+	accountRepo repository.AccountRepository,
+	skillRepo repository.SkillRepository,
+	mediaScanRepo repository.MediaScanRepository,
+	jwtService *services.JWTService,
+	analyticsCache *services.BucketCache,
+	jobEvents *services.JobEventTracker,
+	storage services.FileStorage,
+	backgroundJobs *services.BackgroundQueue,
+	urlSigner *services.URLSigner,
+	mediaScanner *services.MediaScanWorker,
+) *UserHandler {
 	return &UserHandler{
-		userRepo:   userRepo,
-		adminRepo:  adminRepo,
-		jwtService: jwtService,
-		validator:  validator.New(),
+		userRepo:       userRepo,
+		adminRepo:      adminRepo,
+		analyticsRepo:  analyticsRepo,
+		jobRepo:        jobRepo,
+		referralRepo:   referralRepo,
+		companyRepo:    companyRepo,
+		notifyRepo:     notifyRepo,
+		eeoRepo:        eeoRepo,
+		exportRepo:     exportRepo,
+		accountRepo:    accountRepo,
+		skillRepo:      skillRepo,
+		mediaScanRepo:  mediaScanRepo,
+		jwtService:     jwtService,
+		analyticsCache: analyticsCache,
+		jobEvents:      jobEvents,
+		storage:        storage,
+		backgroundJobs: backgroundJobs,
+		urlSigner:      urlSigner,
+		mediaScanner:   mediaScanner,
+		validator:      validator.New(),
 	}
 }
 
@@ -35,14 +88,14 @@ func NewUserHandler(userRepo repository.UserRepository, adminRepo repository.Adm
 // @Tags Authentication
 // @Accept json
 // @Produce json
-// @Param user body dto.CreateUserRequest true "Applicant registration data"
+// @Param user body dto.ApplicantSignUpRequest true "Applicant registration data"
 // @Success 201 {object} dto.LoginResponse
 // @Failure 400 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /applicant/signup [post]
 func (h *UserHandler) HandleApplicantSignUp(w http.ResponseWriter, r *http.Request) {
-	var req dto.CreateUserRequest
+	var req dto.ApplicantSignUpRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -54,7 +107,7 @@ func (h *UserHandler) HandleApplicantSignUp(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	user, err := h.userRepo.CreateUser(req, "applicant")
+	user, err := h.userRepo.CreateUser(req.CreateUserRequest, "applicant")
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
 			h.writeErrorResponse(w, "Email already exists", http.StatusConflict)
@@ -64,6 +117,13 @@ func (h *UserHandler) HandleApplicantSignUp(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Keep the referral the applicant arrived with, so applying later is still attributed
+	if code := referralCode(r, req.ReferralCode); code != "" {
+		if err := h.referralRepo.SavePendingReferral(user.ID, code); err != nil {
+			log.Printf("failed to save referral %s for user %s: %v", code, user.ID, err)
+		}
+	}
+
 	token, err := h.jwtService.GenerateToken(*user)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to generate token", http.StatusInternalServerError)
@@ -88,6 +148,7 @@ func (h *UserHandler) HandleApplicantSignUp(w http.ResponseWriter, r *http.Reque
 // @Success 200 {object} dto.LoginResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /user/login [post]
 func (h *UserHandler) HandleUserLogIn(w http.ResponseWriter, r *http.Request) {
@@ -113,12 +174,23 @@ func (h *UserHandler) HandleUserLogIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Accounts awaiting deletion cannot sign in, only cancel the deletion
+	if user.DeletionScheduledFor != nil {
+		h.writeErrorResponse(w, "Account is scheduled for deletion on "+user.DeletionScheduledFor.Format("2006-01-02")+", cancel the deletion to sign in again", http.StatusForbidden)
+		return
+	}
+
 	token, err := h.jwtService.GenerateToken(*user)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to generate token", http.StatusInternalServerError)
 		return
 	}
 
+	// Login history is part of the user's data export, failing to record it should not block the login
+	if err := h.userRepo.RecordLogin(user.ID, r.RemoteAddr, r.UserAgent()); err != nil {
+		log.Printf("failed to record login for user %s: %v", user.ID, err)
+	}
+
 	response := dto.LoginResponse{
 		Token: token,
 		User:  *user,
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleGetCompanyProfile handles the public company page
+// @Summary Get Company Profile
+// @Description Public company profile with office locations, social links, follower count and open jobs
+// @Tags Companies
+// @Produce json
+// @Param companyID path string true "Company ID"
+// @Success 200 {object} models.CompanyProfile
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /companies/{companyID} [get]
+func (h *UserHandler) HandleGetCompanyProfile(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(chi.URLParam(r, "companyID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+		return
+	}
+
+	company, err := h.adminRepo.GetCompanyByID(companyID.String())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Company not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jobs, err := h.jobRepo.GetPublishedJobsByCompany(companyID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get company jobs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if jobs == nil {
+		jobs = []models.Job{}
+	}
+
+	followers, err := h.companyRepo.GetFollowerCount(companyID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to count followers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	profile := models.CompanyProfile{
+		Company:       *withLogoURL(company),
+		FollowerCount: followers,
+		OpenJobs:      jobs,
+	}
+	h.writeJSONResponse(w, profile, http.StatusOK)
+}
+
+// HandleGetCompanyLogo handles serving a company's logo
+// @Summary Get Company Logo
+// @Description Download the company's logo image
+// @Tags Companies
+// @Produce image/png,image/jpeg,image/gif,image/webp
+// @Param companyID path string true "Company ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /companies/{companyID}/logo [get]
+func (h *UserHandler) HandleGetCompanyLogo(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(chi.URLParam(r, "companyID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+		return
+	}
+
+	company, err := h.adminRepo.GetCompanyByID(companyID.String())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Company not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if company.LogoPath == nil {
+		h.writeErrorResponse(w, "Company has no logo", http.StatusNotFound)
+		return
+	}
+
+	file, err := h.storage.Open(*company.LogoPath)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			h.writeErrorResponse(w, "Company has no logo", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to read logo: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	if company.LogoMimeType != nil {
+		w.Header().Set("Content-Type", *company.LogoMimeType)
+	}
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+// HandleUploadCompanyLogo handles replacing any company's logo (admin-only)
+// @Summary Upload Company Logo
+// @Description Upload a PNG, JPEG, GIF or WebP logo of at most 10MB as the "logo" form field. Like any uploaded image it is stripped of its metadata and turned upright.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param companyID path string true "Company ID"
+// @Param logo formData file true "Logo image"
+// @Success 200 {object} dto.CreateCompanyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/company/{companyID}/logo [post]
+func (h *UserHandler) HandleUploadCompanyLogo(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(chi.URLParam(r, "companyID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+		return
+	}
+
+	h.uploadCompanyLogo(w, r, companyID)
+}
+
+// HandleUpdateMyCompany handles a company owner editing their company's profile
+// @Summary Update My Company
+// @Description Edit the profile of the company the caller owns
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param company body dto.UpdateCompanyRequest true "Company update data"
+// @Success 200 {object} dto.CreateCompanyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/company [patch]
+func (h *UserHandler) HandleUpdateMyCompany(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := h.ownedCompany(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCompanyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+	if message := validateCompanyUpdate(req); message != "" {
+		h.writeErrorResponse(w, message, http.StatusBadRequest)
+		return
+	}
+
+	company, err := h.adminRepo.UpdateCompany(companyID.String(), req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Company not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to update company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.CreateCompanyResponse{
+		Message: "Company updated successfully",
+		Company: *withLogoURL(company),
+	}
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleUploadMyCompanyLogo handles a company owner replacing their company's logo
+// @Summary Upload My Company Logo
+// @Description Upload a PNG, JPEG, GIF or WebP logo of at most 10MB as the "logo" form field. Like any uploaded image it is stripped of its metadata and turned upright.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param logo formData file true "Logo image"
+// @Success 200 {object} dto.CreateCompanyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/company/logo [post]
+func (h *UserHandler) HandleUploadMyCompanyLogo(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := h.ownedCompany(w, r)
+	if !ok {
+		return
+	}
+
+	h.uploadCompanyLogo(w, r, companyID)
+}
+
+// ownedCompany returns the company the calling recruiter owns, writing an error response if there is none
+func (h *UserHandler) ownedCompany(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil || !recruiter.IsCompanyOwner {
+		h.writeErrorResponse(w, "Only the company owner can edit the company profile", http.StatusForbidden)
+		return uuid.Nil, false
+	}
+	return *recruiter.CompanyID, true
+}
+
+func (h *UserHandler) uploadCompanyLogo(w http.ResponseWriter, r *http.Request, companyID uuid.UUID) {
+	maxSize := services.MediaPolicies["image"].MaxSize
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		h.writeErrorResponse(w, "A logo image of at most "+strconv.FormatInt(maxSize>>20, 10)+"MB is required", status)
+		return
+	}
+
+	file, header, err := r.FormFile("logo")
+	if err != nil {
+		h.writeErrorResponse(w, "No logo provided", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// Checked and cleaned like every other uploaded image
+	processed, err := services.ProcessMedia(file, header.Size, "image")
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMediaTooLarge):
+			h.writeErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, services.ErrMediaTypeNotAllowed):
+			h.writeErrorResponse(w, "Logo must be a PNG, JPEG, GIF or WebP image: "+err.Error(), http.StatusUnsupportedMediaType)
+		default:
+			h.writeErrorResponse(w, "Failed to process logo: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	key := "companies/" + companyID.String() + "/logo-" + uuid.NewString() + processed.Extension
+	if err := h.storage.Save(key, processed.Body); err != nil {
+		h.writeErrorResponse(w, "Failed to store logo: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	previous, err := h.adminRepo.SetCompanyLogo(companyID.String(), key, processed.MimeType)
+	if err != nil {
+		h.storage.Delete(key)
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Company not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to update company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if previous != nil {
+		if err := h.storage.Delete(*previous); err != nil {
+			log.Printf("failed to delete previous logo of company %s: %v", companyID, err)
+		}
+	}
+
+	company, err := h.adminRepo.GetCompanyByID(companyID.String())
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.CreateCompanyResponse{
+		Message: "Company logo updated successfully",
+		Company: *withLogoURL(company),
+	}
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// validateCompanyUpdate checks the rules the struct tags cannot express and returns a message if one is broken
+func validateCompanyUpdate(req dto.UpdateCompanyRequest) string {
+	if req.Name == nil && req.Description == nil && req.Website == nil && req.Industry == nil &&
+		req.SizeBand == nil && req.Headquarters == nil && req.SocialLinks == nil && req.Offices == nil {
+		return "At least one field must be provided for update"
+	}
+	if req.Name != nil && len(*req.Name) < 6 {
+		return "Company name must be at least 6 characters long"
+	}
+	if req.Description != nil && len(*req.Description) < 6 {
+		return "Company description must be at least 6 characters long"
+	}
+	return ""
+}
+
+// withLogoURL fills in the public logo URL when the company has a logo
+func withLogoURL(company *models.Company) *models.Company {
+	if company.LogoPath != nil {
+		url := "/api/v1/companies/" + company.ID.String() + "/logo"
+		company.LogoURL = &url
+	}
+	return company
+}
@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// HandleRequestDataExport handles a user asking for a copy of all their personal data
+// @Summary Request Data Export
+// @Description Start building a ZIP archive of everything held about the caller. Poll the export until it is ready, then follow its download link before it expires.
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Success 202 {object} models.DataExport
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /user/data-export [post]
+func (h *UserHandler) HandleRequestDataExport(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	staleAfter := time.Duration(env.GetEnvAsInt("DATA_EXPORT_TIMEOUT_MINUTES", 60)) * time.Minute
+	export, created, err := h.exportRepo.CreateDataExport(claims.UserID, staleAfter)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to request data export: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// An export already in progress is simply returned again
+	if created {
+		exportID := export.ID
+		if !h.backgroundJobs.Enqueue(func() { h.generateDataExport(exportID, claims.UserID) }) {
+			h.exportRepo.FailDataExport(exportID, "export queue is full, please try again later")
+			h.writeErrorResponse(w, "Too many exports in progress, please try again later", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	h.writeJSONResponse(w, export, http.StatusAccepted)
+}
+
+// HandleGetDataExport handles checking on a data export
+// @Summary Get Data Export
+// @Description Get the status of a data export. Ready exports include a signed download link valid until expires_at.
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param exportID path string true "Export ID"
+// @Success 200 {object} models.DataExport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/data-export/{exportID} [get]
+func (h *UserHandler) HandleGetDataExport(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	exportID, err := uuid.Parse(chi.URLParam(r, "exportID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid export ID format", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.exportRepo.GetDataExport(claims.UserID, exportID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Export not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get export: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if export.Status == models.DataExportReady && export.ExpiresAt != nil && time.Now().Before(*export.ExpiresAt) {
+		url := h.urlSigner.Sign(dataExportDownloadPath(export.ID), *export.ExpiresAt)
+		export.DownloadURL = &url
+	}
+
+	h.writeJSONResponse(w, export, http.StatusOK)
+}
+
+// HandleDownloadDataExport handles downloading a finished export through its signed link
+// @Summary Download Data Export
+// @Description Download the export archive. The link returned by Get Data Export carries its own authorization and stops working when the export expires.
+// @Tags User
+// @Produce application/zip
+// @Param exportID path string true "Export ID"
+// @Param expires query string true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /data-exports/{exportID}/download [get]
+func (h *UserHandler) HandleDownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	exportID, err := uuid.Parse(chi.URLParam(r, "exportID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid export ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.urlSigner.Verify(dataExportDownloadPath(exportID), r.URL.Query()); err != nil {
+		if errors.Is(err, services.ErrLinkExpired) {
+			h.writeErrorResponse(w, "Download link has expired", http.StatusGone)
+			return
+		}
+		h.writeErrorResponse(w, "Invalid download link", http.StatusForbidden)
+		return
+	}
+
+	export, err := h.exportRepo.GetDataExportByID(exportID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Export not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get export: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if export.Status != models.DataExportReady || export.FileKey == nil ||
+		export.ExpiresAt == nil || time.Now().After(*export.ExpiresAt) {
+		h.writeErrorResponse(w, "Export is no longer available", http.StatusGone)
+		return
+	}
+
+	file, err := h.storage.Open(*export.FileKey)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			h.writeErrorResponse(w, "Export is no longer available", http.StatusGone)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to read export: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="job-hunter-data-%s.zip"`, export.CreatedAt.Format("2006-01-02")))
+	w.Header().Set("Cache-Control", "private, no-store")
+	if export.FileSize != nil {
+		w.Header().Set("Content-Length", fmt.Sprint(*export.FileSize))
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+func dataExportDownloadPath(exportID uuid.UUID) string {
+	return "/api/v1/data-exports/" + exportID.String() + "/download"
+}
+
+// generateDataExport builds the archive in the background and records the outcome on the export
+func (h *UserHandler) generateDataExport(exportID, userID uuid.UUID) {
+	// Clear out archives whose links have lapsed while we are here
+	if keys, err := h.exportRepo.ExpireDataExports(); err != nil {
+		log.Printf("failed to expire old data exports: %v", err)
+	} else {
+		for _, key := range keys {
+			if err := h.storage.Delete(key); err != nil {
+				log.Printf("failed to delete expired data export %s: %v", key, err)
+			}
+		}
+	}
+
+	key := "exports/" + userID.String() + "/" + exportID.String() + ".zip"
+	size, err := h.writeDataExport(userID, key)
+	if err != nil {
+		log.Printf("data export %s failed: %v", exportID, err)
+		if err := h.exportRepo.FailDataExport(exportID, "the export could not be generated"); err != nil {
+			log.Printf("failed to record data export failure: %v", err)
+		}
+		return
+	}
+
+	ttl := time.Duration(env.GetEnvAsInt("DATA_EXPORT_TTL_HOURS", 24)) * time.Hour
+	if err := h.exportRepo.CompleteDataExport(exportID, key, size, time.Now().Add(ttl)); err != nil {
+		log.Printf("failed to complete data export %s: %v", exportID, err)
+		h.storage.Delete(key)
+	}
+}
+
+// writeDataExport writes the user's archive to storage and returns its size.
+// The archive holds one JSON file per kind of data plus the original uploaded files.
+func (h *UserHandler) writeDataExport(userID uuid.UUID, key string) (int64, error) {
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	archive := zip.NewWriter(tmp)
+
+	profile, err := h.userRepo.GetUserProfileByID(userID, models.ViewerOwner)
+	if err != nil {
+		return 0, err
+	}
+	applications, err := h.jobRepo.GetApplicationsByApplicant(userID)
+	if err != nil {
+		return 0, err
+	}
+	selfIdentification, err := h.eeoRepo.GetSelfIdentificationsByApplicant(userID)
+	if err != nil {
+		return 0, err
+	}
+	notifications, err := h.notifyRepo.GetNotifications(userID, false, 0)
+	if err != nil {
+		return 0, err
+	}
+	history, err := h.userRepo.GetProfileHistory(userID, "", nil, 0)
+	if err != nil {
+		return 0, err
+	}
+	logins, err := h.userRepo.GetLoginHistory(userID)
+	if err != nil {
+		return 0, err
+	}
+	following, err := h.companyRepo.GetFollowedCompanies(userID)
+	if err != nil {
+		return 0, err
+	}
+	referrals, err := h.referralRepo.GetReferralLinksByReferrer(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", profile},
+		{"profile_history.json", history},
+		{"applications.json", applications},
+		{"self_identification.json", selfIdentification},
+		// There is no direct messaging, the platform's messages to the user are notifications
+		{"messages.json", notifications},
+		{"login_history.json", logins},
+		{"followed_companies.json", following},
+		{"referral_links.json", referrals},
+	}
+	for _, file := range files {
+		if err := writeZipJSON(archive, file.name, file.data); err != nil {
+			return 0, err
+		}
+	}
+
+	media := profileMedia(profile)
+	if profile.User.ProfilePicture != nil {
+		media = append(media, models.UserMedia{
+			ID:       profile.User.ID,
+			FileName: "profile-picture" + path.Ext(*profile.User.ProfilePicture),
+			FilePath: *profile.User.ProfilePicture,
+		})
+	}
+	for _, m := range media {
+		if err := h.writeZipMedia(archive, m); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	size, err := tmp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to size archive: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to rewind archive: %w", err)
+	}
+	if err := h.storage.Save(key, tmp); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func writeZipJSON(archive *zip.Writer, name string, data any) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeZipMedia copies an uploaded file into the archive. Files missing from storage are skipped.
+func (h *UserHandler) writeZipMedia(archive *zip.Writer, media models.UserMedia) error {
+	file, err := h.storage.Open(media.FilePath)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	f, err := archive.Create("media/" + media.ID.String() + "-" + path.Base(media.FileName))
+	if err != nil {
+		return fmt.Errorf("failed to add media %s: %w", media.ID, err)
+	}
+	if _, err := io.Copy(f, file); err != nil {
+		return fmt.Errorf("failed to copy media %s: %w", media.ID, err)
+	}
+	return nil
+}
+
+// profileMedia collects the media attached to every entry of a profile
+func profileMedia(profile *models.UserProfile) []models.UserMedia {
+	var media []models.UserMedia
+	for _, e := range profile.Education {
+		media = append(media, e.Media...)
+	}
+	for _, e := range profile.Experience {
+		media = append(media, e.Media...)
+	}
+	for _, c := range profile.Certifications {
+		media = append(media, c.Media...)
+	}
+	for _, p := range profile.Projects {
+		media = append(media, p.Media...)
+	}
+	return media
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleFollowCompany handles an applicant following a company
+// @Summary Follow Company
+// @Description Get notified when the company publishes a job or posts an update. Following twice is a no-op.
+// @Tags Applicant
+// @Security BearerAuth
+// @Produce json
+// @Param companyID path string true "Company ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /applicant/companies/{companyID}/follow [post]
+func (h *UserHandler) HandleFollowCompany(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	companyID, err := uuid.Parse(chi.URLParam(r, "companyID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.companyRepo.FollowCompany(claims.UserID, companyID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Company not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to follow company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, map[string]string{"message": "Company followed successfully"}, http.StatusOK)
+}
+
+// HandleUnfollowCompany handles an applicant unfollowing a company
+// @Summary Unfollow Company
+// @Description Stop getting notifications from a company
+// @Tags Applicant
+// @Security BearerAuth
+// @Produce json
+// @Param companyID path string true "Company ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /applicant/companies/{companyID}/follow [delete]
+func (h *UserHandler) HandleUnfollowCompany(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	companyID, err := uuid.Parse(chi.URLParam(r, "companyID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.companyRepo.UnfollowCompany(claims.UserID, companyID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "You are not following this company", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to unfollow company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, map[string]string{"message": "Company unfollowed successfully"}, http.StatusOK)
+}
+
+// HandleGetFollowedCompanies handles listing the companies an applicant follows
+// @Summary Get Followed Companies
+// @Description List the companies the caller follows, most recently followed first
+// @Tags Applicant
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.FollowedCompany
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /applicant/companies/following [get]
+func (h *UserHandler) HandleGetFollowedCompanies(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	companies, err := h.companyRepo.GetFollowedCompanies(claims.UserID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get followed companies: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if companies == nil {
+		companies = []models.FollowedCompany{}
+	}
+	for i := range companies {
+		withLogoURL(&companies[i].Company)
+	}
+
+	h.writeJSONResponse(w, companies, http.StatusOK)
+}
+
+// HandleGetCompanyUpdates handles the public company updates feed
+// @Summary Get Company Updates
+// @Description Company news, newest first. Pass the created_at of the last update seen as before to page back.
+// @Tags Companies
+// @Produce json
+// @Param companyID path string true "Company ID"
+// @Param before query string false "Only updates posted before this RFC 3339 time"
+// @Param limit query int false "Maximum number of updates (default 20, max 100)"
+// @Success 200 {array} models.CompanyUpdate
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /companies/{companyID}/updates [get]
+func (h *UserHandler) HandleGetCompanyUpdates(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(chi.URLParam(r, "companyID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid company ID format", http.StatusBadRequest)
+		return
+	}
+
+	var before *time.Time
+	if value := r.URL.Query().Get("before"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid before time, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		before = &t
+	}
+
+	limit, ok := h.queryLimit(w, r, 20, 100)
+	if !ok {
+		return
+	}
+
+	updates, err := h.companyRepo.GetCompanyUpdates(companyID, before, limit)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get company updates: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if updates == nil {
+		updates = []models.CompanyUpdate{}
+	}
+
+	h.writeJSONResponse(w, updates, http.StatusOK)
+}
+
+// HandleCreateCompanyUpdate handles a recruiter posting news on their company's feed
+// @Summary Post Company Update
+// @Description Post a short update on the caller's company feed. Followers are notified.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCompanyUpdateRequest true "Update text"
+// @Success 201 {object} models.CompanyUpdate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/company/updates [post]
+func (h *UserHandler) HandleCreateCompanyUpdate(w http.ResponseWriter, r *http.Request) {
+	recruiter, ok := h.recruiterCompany(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCompanyUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	update, err := h.companyRepo.CreateCompanyUpdate(*recruiter.CompanyID, recruiter.ID, req)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to post update: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, update, http.StatusCreated)
+}
+
+// HandleDeleteCompanyUpdate handles removing an update from the recruiter's company feed
+// @Summary Delete Company Update
+// @Description Remove an update from the caller's company feed
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Param updateID path string true "Update ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/company/updates/{updateID} [delete]
+func (h *UserHandler) HandleDeleteCompanyUpdate(w http.ResponseWriter, r *http.Request) {
+	recruiter, ok := h.recruiterCompany(w, r)
+	if !ok {
+		return
+	}
+
+	updateID, err := uuid.Parse(chi.URLParam(r, "updateID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid update ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.companyRepo.DeleteCompanyUpdate(*recruiter.CompanyID, updateID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Update not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to delete update: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, map[string]string{"message": "Update deleted successfully"}, http.StatusOK)
+}
+
+// HandleGetCompanyFollowerCount handles how many users follow the recruiter's company
+// @Summary Get Company Follower Count
+// @Description Number of users following the caller's company
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.FollowerCountResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/company/followers [get]
+func (h *UserHandler) HandleGetCompanyFollowerCount(w http.ResponseWriter, r *http.Request) {
+	recruiter, ok := h.recruiterCompany(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := h.companyRepo.GetFollowerCount(*recruiter.CompanyID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to count followers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.FollowerCountResponse{
+		CompanyID:     recruiter.CompanyID.String(),
+		FollowerCount: count,
+	}
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// recruiterCompany returns the calling recruiter, writing an error response if they have no company
+func (h *UserHandler) recruiterCompany(w http.ResponseWriter, r *http.Request) (*models.Recruiter, bool) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil {
+		h.writeErrorResponse(w, "You are not a member of a company", http.StatusForbidden)
+		return nil, false
+	}
+	return recruiter, true
+}
+
+// queryLimit reads the limit query parameter, falling back to def and capped at max
+func (h *UserHandler) queryLimit(w http.ResponseWriter, r *http.Request, def, max int) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return def, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		h.writeErrorResponse(w, "Invalid limit, expected a positive number", http.StatusBadRequest)
+		return 0, false
+	}
+	if limit > max {
+		limit = max
+	}
+	return limit, true
+}
@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleGetJob handles getting a single job posting (public)
+// @Summary Get Job
+// @Description Get a job posting. Views are recorded once per viewer per day; crawlers are not counted.
+// @Tags Jobs
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param ref query string false "Referral code, remembered until the visitor applies"
+// @Success 200 {object} models.Job
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /jobs/{jobID} [get]
+func (h *UserHandler) HandleGetJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid job ID format", http.StatusBadRequest)
+		return
+	}
+
+	job, ok := h.getPublishedJob(w, jobID)
+	if !ok {
+		return
+	}
+
+	h.trackJobEvent(r, job.ID, models.JobEventView)
+	rememberReferral(w, r)
+
+	h.writeJSONResponse(w, job, http.StatusOK)
+}
+
+// HandleTrackApplyStart handles recording that a visitor opened the application form (public)
+// @Summary Track Apply Start
+// @Description Record that the application form for a job was opened
+// @Tags Jobs
+// @Param jobID path string true "Job ID"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /jobs/{jobID}/apply-start [post]
+func (h *UserHandler) HandleTrackApplyStart(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid job ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getPublishedJob(w, jobID); !ok {
+		return
+	}
+
+	h.trackJobEvent(r, jobID, models.JobEventApplyStart)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleApplyToJob handles submitting an application (applicant-only)
+// @Summary Apply to Job
+// @Description Submit an application for a job, with answers to its application questions and optional equal-opportunity self-identification
+// @Tags Applicant
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param request body dto.ApplyToJobRequest false "Answers to the job's application questions, an optional referral code and optional self-identification"
+// @Success 201 {object} models.Application
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /applicant/jobs/{jobID}/apply [post]
+func (h *UserHandler) HandleApplyToJob(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid job ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ApplyToJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.getPublishedJob(w, jobID); !ok {
+		return
+	}
+
+	req.ReferralCode = referralCode(r, req.ReferralCode)
+
+	application, err := h.jobRepo.CreateApplication(claims.UserID, jobID, req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "duplicate key"):
+			h.writeErrorResponse(w, "You have already applied to this job", http.StatusConflict)
+		case strings.Contains(err.Error(), "required"), strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.writeErrorResponse(w, "Failed to apply: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Recruiters see the profile as it was submitted, even if it is edited later. An application
+	// without it would show them a profile the applicant never submitted, so it is taken back.
+	if err := h.snapshotApplicantProfile(application.ID, claims.UserID); err != nil {
+		log.Printf("failed to save profile snapshot for application %s: %v", application.ID, err)
+		if err := h.jobRepo.DeleteApplication(application.ID); err != nil {
+			log.Printf("failed to delete application %s without its profile snapshot: %v", application.ID, err)
+		}
+		h.writeErrorResponse(w, "Failed to apply: could not save your profile with the application", http.StatusInternalServerError)
+		return
+	}
+	// The copies of the submitted files are scanned on their own
+	h.mediaScanner.Wake()
+
+	// Self-identification is voluntary, failing to store it must not fail the application
+	if req.SelfIdentification != nil {
+		if err := h.eeoRepo.SaveSelfIdentification(application.ID, *req.SelfIdentification); err != nil {
+			log.Printf("failed to save self-identification for application %s: %v", application.ID, err)
+		}
+	}
+
+	h.trackJobEvent(r, jobID, models.JobEventApplyComplete)
+
+	h.writeJSONResponse(w, application, http.StatusCreated)
+}
+
+// HandleCreateJob handles posting a new job (recruiter-only)
+// @Summary Create Job
+// @Description Post a job for the recruiter's company. It is published immediately when moderation is off or the company is trusted, otherwise it waits for admin review.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateJobRequest true "Job data"
+// @Success 201 {object} models.Job
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs [post]
+func (h *UserHandler) HandleCreateJob(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil {
+		h.writeErrorResponse(w, "Recruiter is not assigned to a company", http.StatusForbidden)
+		return
+	}
+
+	status, err := h.jobStatusAfterEdit(*recruiter.CompanyID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to check company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	job, err := h.jobRepo.CreateJob(claims.UserID, req, status)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to create job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, job, http.StatusCreated)
+}
+
+// HandleUpdateJob handles editing one of the company's jobs (recruiter-only)
+// @Summary Update Job
+// @Description Edit a job. Unless the company is trusted, the edited job goes back to admin review. Rejected jobs cannot be edited.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param request body dto.UpdateJobRequest true "Job data"
+// @Success 200 {object} models.Job
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs/{jobID} [put]
+func (h *UserHandler) HandleUpdateJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.authorizeRecruiterJob(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	if job.Status == models.JobStatusRejected {
+		h.writeErrorResponse(w, "Rejected jobs cannot be edited", http.StatusConflict)
+		return
+	}
+
+	status, err := h.jobStatusAfterEdit(*job.CompanyID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to check company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := h.jobRepo.UpdateJob(job.ID, req, status)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to update job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, updated, http.StatusOK)
+}
+
+// HandleGetCompanyJobs handles listing every job of the recruiter's company, whatever its status
+// @Summary List Company Jobs
+// @Description List the recruiter's company jobs, including jobs waiting for or sent back from review
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Job
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs [get]
+func (h *UserHandler) HandleGetCompanyJobs(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil {
+		h.writeErrorResponse(w, "Recruiter is not assigned to a company", http.StatusForbidden)
+		return
+	}
+
+	jobs, err := h.jobRepo.GetJobsByCompany(*recruiter.CompanyID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get jobs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, jobs, http.StatusOK)
+}
+
+// HandleGetJobModeration handles the moderation decisions taken on one of the company's jobs
+// @Summary Get Job Moderation History
+// @Description List the moderation decisions and reasons for a job, newest first
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Success 200 {array} models.JobModerationDecision
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs/{jobID}/moderation [get]
+func (h *UserHandler) HandleGetJobModeration(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.authorizeRecruiterJob(w, r)
+	if !ok {
+		return
+	}
+
+	decisions, err := h.jobRepo.GetModerationHistory(job.ID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get moderation history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, decisions, http.StatusOK)
+}
+
+// HandleGetJobActivity handles view and apply counters for one of the recruiter's jobs
+// @Summary Get Job Activity
+// @Description Total views, apply starts and apply completions for a job, with a daily time series
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param from query string false "First day of the series (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "Last day of the series (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.JobActivity
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/jobs/{jobID}/activity [get]
+func (h *UserHandler) HandleGetJobActivity(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.authorizeRecruiterJob(w, r)
+	if !ok {
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -29)
+	if value := r.URL.Query().Get("from"); value != "" {
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	if to.Before(from) {
+		h.writeErrorResponse(w, "from must not be after to", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		h.writeErrorResponse(w, "Date range cannot exceed one year", http.StatusBadRequest)
+		return
+	}
+
+	activity, err := h.jobRepo.GetJobActivity(job.ID, from, to)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get job activity: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, activity, http.StatusOK)
+}
+
+// getPublishedJob loads a job that applicants are allowed to see. Jobs still in
+// moderation are reported as not found. It writes the error response itself.
+func (h *UserHandler) getPublishedJob(w http.ResponseWriter, jobID uuid.UUID) (*models.Job, bool) {
+	job, err := h.jobRepo.GetJobByID(jobID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Job not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.writeErrorResponse(w, "Failed to get job: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if job.Status != models.JobStatusPublished {
+		h.writeErrorResponse(w, "Job not found", http.StatusNotFound)
+		return nil, false
+	}
+	return job, true
+}
+
+// authorizeRecruiterJob loads the job named by the jobID URL parameter and checks that it
+// belongs to the calling recruiter's company. It writes the error response itself.
+func (h *UserHandler) authorizeRecruiterJob(w http.ResponseWriter, r *http.Request) (*models.Job, bool) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid job ID format", http.StatusBadRequest)
+		return nil, false
+	}
+
+	job, err := h.jobRepo.GetJobByID(jobID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Job not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.writeErrorResponse(w, "Failed to get job: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	recruiter, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || recruiter.CompanyID == nil || job.CompanyID == nil || *recruiter.CompanyID != *job.CompanyID {
+		h.writeErrorResponse(w, "Job does not belong to your company", http.StatusForbidden)
+		return nil, false
+	}
+
+	return job, true
+}
+
+// trackJobEvent queues a tracking event for the job unless the request comes from a bot.
+// Completed applications are always counted, the application exists whoever sent it.
+func (h *UserHandler) trackJobEvent(r *http.Request, jobID uuid.UUID, eventType string) {
+	if eventType != models.JobEventApplyComplete && services.IsBotUserAgent(r.UserAgent()) {
+		return
+	}
+
+	event := models.JobEvent{
+		JobID:     jobID,
+		EventType: eventType,
+		ViewerKey: viewerKey(r),
+	}
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims); ok {
+		event.UserID = &claims.UserID
+	}
+
+	h.jobEvents.Track(event)
+}
+
+// viewerKey identifies a viewer for view de-duplication: the user ID when signed in,
+// otherwise a hash of the client address and user agent
+func viewerKey(r *http.Request) string {
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims); ok {
+		return "user:" + claims.UserID.String()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	sum := sha256.Sum256([]byte(host + "|" + r.UserAgent()))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// HandleDownloadMedia handles downloading a media file through its signed URL
+// @Summary Download Media
+// @Description Download a media file. The url on a media file carries its own authorization and stops working after a few minutes: owners get it on their profile, recruiters on applications to their jobs. Range requests are supported, so videos can stream.
+// @Tags Media
+// @Produce octet-stream
+// @Param mediaID path string true "Media ID"
+// @Param expires query string true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 416 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /media/{mediaID} [get]
+func (h *UserHandler) HandleDownloadMedia(w http.ResponseWriter, r *http.Request) {
+	media, ok := h.signedMedia(w, r, mediaDownloadPath)
+	if !ok {
+		return
+	}
+
+	mimeType := "application/octet-stream"
+	if media.MimeType != nil {
+		mimeType = *media.MimeType
+	}
+	h.serveMediaFile(w, r, media.FilePath, mimeType, media.FileName, media.MediaType == "document", media.UpdatedAt)
+}
+
+// HandleDownloadMediaThumbnail handles downloading an image thumbnail through its signed URL
+// @Summary Download Media Thumbnail
+// @Description Download a scaled down copy of an image. The urls on the thumbnails of a media file work like the url of the file itself.
+// @Tags Media
+// @Produce image/jpeg,image/png
+// @Param mediaID path string true "Media ID"
+// @Param size path string true "Thumbnail size" Enums(small, medium, large)
+// @Param expires query string true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /media/{mediaID}/thumbnails/{size} [get]
+func (h *UserHandler) HandleDownloadMediaThumbnail(w http.ResponseWriter, r *http.Request) {
+	size := chi.URLParam(r, "size")
+	media, ok := h.signedMedia(w, r, func(mediaID uuid.UUID) string {
+		return mediaThumbnailPath(mediaID, size)
+	})
+	if !ok {
+		return
+	}
+
+	for _, t := range media.Thumbnails {
+		if t.Size == size {
+			h.serveMediaFile(w, r, t.FilePath, t.MimeType, "", false, media.UpdatedAt)
+			return
+		}
+	}
+	h.writeErrorResponse(w, "Thumbnail not found", http.StatusNotFound)
+}
+
+// signedMedia checks the signature of the URL the media is requested with and loads the media,
+// writing an error response if it cannot be served. Whoever got the URL was allowed to see the
+// file when it was signed; quarantined files are refused no matter what.
+func (h *UserHandler) signedMedia(w http.ResponseWriter, r *http.Request, signedPath func(uuid.UUID) string) (*models.UserMedia, bool) {
+	mediaID, err := uuid.Parse(chi.URLParam(r, "mediaID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid media ID format", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if !h.verifyMediaLink(w, r, signedPath(mediaID)) {
+		return nil, false
+	}
+
+	media, err := h.userRepo.GetMediaByID(mediaID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Media not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.writeErrorResponse(w, "Failed to get media: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if media.ScanStatus == models.MediaScanQuarantined {
+		h.writeErrorResponse(w, "Media has been quarantined", http.StatusForbidden)
+		return nil, false
+	}
+	return media, true
+}
+
+// verifyMediaLink checks the signature the request carries for the path, writing an error
+// response if it is invalid or expired
+func (h *UserHandler) verifyMediaLink(w http.ResponseWriter, r *http.Request, signedPath string) bool {
+	if err := h.urlSigner.Verify(signedPath, r.URL.Query()); err != nil {
+		if errors.Is(err, services.ErrLinkExpired) {
+			h.writeErrorResponse(w, "Download link has expired", http.StatusGone)
+			return false
+		}
+		h.writeErrorResponse(w, "Invalid download link", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// serveMediaFile streams a stored file. Storage that can seek gets range requests answered,
+// which is what video players rely on; anything else is sent whole.
+func (h *UserHandler) serveMediaFile(w http.ResponseWriter, r *http.Request, key, mimeType, fileName string, attachment bool, modified time.Time) {
+	file, err := h.storage.Open(key)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			h.writeErrorResponse(w, "Media not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to read media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	disposition := "inline"
+	if attachment {
+		disposition = "attachment"
+	}
+	if fileName != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": fileName})
+	}
+	w.Header().Set("Content-Disposition", disposition)
+
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", modified, seeker)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+// signMediaURLs gives each file and thumbnail a download URL. Only call it for media the
+// caller may see. Expiry is rounded to the TTL, so the same file keeps the same URL for a
+// while and browsers can cache it; every URL is valid for between one and two TTLs.
+func (h *UserHandler) signMediaURLs(media []models.UserMedia) {
+	expiresAt := mediaURLExpiry()
+	for i := range media {
+		url := h.urlSigner.Sign(mediaDownloadPath(media[i].ID), expiresAt)
+		media[i].URL = &url
+		for j := range media[i].Thumbnails {
+			url := h.urlSigner.Sign(mediaThumbnailPath(media[i].ID, media[i].Thumbnails[j].Size), expiresAt)
+			media[i].Thumbnails[j].URL = &url
+		}
+	}
+}
+
+// signProfileMediaURLs signs the media of every entry of the profile
+func (h *UserHandler) signProfileMediaURLs(profile *models.UserProfile) {
+	for _, media := range profileMediaLists(profile) {
+		h.signMediaURLs(*media)
+	}
+}
+
+// profileMediaLists returns the media list of every entry of the profile, to change in place
+func profileMediaLists(profile *models.UserProfile) []*[]models.UserMedia {
+	var lists []*[]models.UserMedia
+	for i := range profile.Education {
+		lists = append(lists, &profile.Education[i].Media)
+	}
+	for i := range profile.Experience {
+		lists = append(lists, &profile.Experience[i].Media)
+	}
+	for i := range profile.Certifications {
+		lists = append(lists, &profile.Certifications[i].Media)
+	}
+	for i := range profile.Projects {
+		lists = append(lists, &profile.Projects[i].Media)
+	}
+	return lists
+}
+
+// mediaURLExpiry is when media URLs signed now expire
+func mediaURLExpiry() time.Time {
+	ttl := time.Duration(env.GetEnvAsInt("MEDIA_URL_TTL_MINUTES", 15)) * time.Minute
+	return time.Now().Truncate(ttl).Add(2 * ttl)
+}
+
+func mediaDownloadPath(mediaID uuid.UUID) string {
+	return "/api/v1/media/" + mediaID.String()
+}
+
+func mediaThumbnailPath(mediaID uuid.UUID, size string) string {
+	return "/api/v1/media/" + mediaID.String() + "/thumbnails/" + size
+}
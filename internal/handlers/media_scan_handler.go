@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// HandleGetFlaggedMedia handles listing uploaded files the malware scan did not pass (admin-only)
+// @Summary Get Flagged Media
+// @Description List quarantined files, with the malware found in scan_result, and files that could not be scanned, with the reason. Files that could not be scanned are retried on their own a few times before scan_attempts stops growing.
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Only files with this scan status" Enums(quarantined, error)
+// @Success 200 {array} models.FlaggedMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/media/flagged [get]
+func (h *UserHandler) HandleGetFlaggedMedia(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != models.MediaScanQuarantined && status != models.MediaScanError {
+		h.writeErrorResponse(w, "status must be quarantined or error", http.StatusBadRequest)
+		return
+	}
+
+	flagged, err := h.mediaScanRepo.GetFlaggedMedia(status)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get flagged media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, flagged, http.StatusOK)
+}
+
+// HandleRescanMedia handles scanning one uploaded file again (admin-only)
+// @Summary Rescan Media
+// @Description Queue a file for another malware scan. It is hidden from recruiters until the scan finds it clean.
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param mediaID path string true "Media ID"
+// @Success 202 {object} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/media/{mediaID}/rescan [post]
+func (h *UserHandler) HandleRescanMedia(w http.ResponseWriter, r *http.Request) {
+	mediaID, err := uuid.Parse(chi.URLParam(r, "mediaID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid media ID format", http.StatusBadRequest)
+		return
+	}
+
+	media, err := h.mediaScanRepo.RescanMedia(mediaID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to rescan media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.mediaScanner.Wake()
+
+	h.writeJSONResponse(w, media, http.StatusAccepted)
+}
+
+// HandleRescanAllMedia handles scanning every uploaded file with a scan status again (admin-only)
+// @Summary Rescan All Media
+// @Description Queue every file with the status for another malware scan, e.g. quarantined files after a false positive was fixed, or clean files after the scanner got new signatures. The files are hidden from recruiters until their scan finds them clean.
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param status query string true "Scan status of the files to rescan" Enums(quarantined, error, clean)
+// @Success 202 {object} models.MediaRescanResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/media/rescan [post]
+func (h *UserHandler) HandleRescanAllMedia(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != models.MediaScanQuarantined && status != models.MediaScanError && status != models.MediaScanClean {
+		h.writeErrorResponse(w, "status must be quarantined, error or clean", http.StatusBadRequest)
+		return
+	}
+
+	queued, err := h.mediaScanRepo.RescanAllMedia(status)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to rescan media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.mediaScanner.Wake()
+
+	h.writeJSONResponse(w, models.MediaRescanResult{Queued: queued}, http.StatusAccepted)
+}
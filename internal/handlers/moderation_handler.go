@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// HandleGetModerationQueue handles listing jobs waiting for review (admin-only)
+// @Summary Get Moderation Queue
+// @Description List jobs pending review, oldest first
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.Job
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/moderation [get]
+func (h *UserHandler) HandleGetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.jobRepo.GetJobsByStatus(models.JobStatusPendingReview)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get moderation queue: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, jobs, http.StatusOK)
+}
+
+// HandleModerateJob handles approving, rejecting or requesting changes to a job (admin-only)
+// @Summary Moderate Job
+// @Description Record a moderation decision on a job pending review. A reason is required unless the job is approved.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Param request body dto.ModerateJobRequest true "Moderation decision"
+// @Success 201 {object} models.JobModerationDecision
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/{jobID}/moderation [post]
+func (h *UserHandler) HandleModerateJob(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid job ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ModerateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	// The recruiter needs to know what to fix
+	if req.Decision != models.ModerationApprove && strings.TrimSpace(req.Reason) == "" {
+		h.writeErrorResponse(w, "A reason is required when rejecting or requesting changes", http.StatusBadRequest)
+		return
+	}
+
+	decision, err := h.jobRepo.ModerateJob(jobID, claims.UserID, req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, "Job not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "only jobs pending review"):
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+		default:
+			h.writeErrorResponse(w, "Failed to moderate job: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, decision, http.StatusCreated)
+}
+
+// HandleGetAdminJobModeration handles the full decision history of a job (admin-only)
+// @Summary Get Job Moderation History (Admin)
+// @Description List every moderation decision taken on a job, newest first
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param jobID path string true "Job ID"
+// @Success 200 {array} models.JobModerationDecision
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/{jobID}/moderation [get]
+func (h *UserHandler) HandleGetAdminJobModeration(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid job ID format", http.StatusBadRequest)
+		return
+	}
+
+	decisions, err := h.jobRepo.GetModerationHistory(jobID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get moderation history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, decisions, http.StatusOK)
+}
+
+// HandleSetCompanyTrusted handles marking a company as trusted so its jobs skip moderation (admin-only)
+// @Summary Set Company Trusted
+// @Description Trusted companies publish jobs without admin review
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param companyID path string true "Company ID"
+// @Param request body dto.SetCompanyTrustedRequest true "Trusted flag"
+// @Success 200 {object} dto.CreateCompanyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/company/{companyID}/trusted [patch]
+func (h *UserHandler) HandleSetCompanyTrusted(w http.ResponseWriter, r *http.Request) {
+	companyID := chi.URLParam(r, "companyID")
+	if companyID == "" {
+		h.writeErrorResponse(w, "Company ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SetCompanyTrustedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	company, err := h.adminRepo.SetCompanyTrusted(companyID, *req.IsTrusted)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Company not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to update company: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.CreateCompanyResponse{
+		Message: "Company updated successfully",
+		Company: *company,
+	}
+	h.writeJSONResponse(w, response, http.StatusOK)
+}
+
+// jobStatusAfterEdit decides whether a new or edited job goes live straight away
+// or waits in the moderation queue. Moderation is off unless JOB_MODERATION_ENABLED is "true".
+func (h *UserHandler) jobStatusAfterEdit(companyID uuid.UUID) (string, error) {
+	if env.GetEnv("JOB_MODERATION_ENABLED", "false") != "true" {
+		return models.JobStatusPublished, nil
+	}
+
+	company, err := h.adminRepo.GetCompanyByID(companyID.String())
+	if err != nil {
+		return "", err
+	}
+	if company.IsTrusted {
+		return models.JobStatusPublished, nil
+	}
+	return models.JobStatusPendingReview, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleGetNotifications handles listing the caller's notifications
+// @Summary Get Notifications
+// @Description List the caller's notifications, newest first
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Maximum number of notifications (default 50, max 200)"
+// @Success 200 {array} models.Notification
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/notifications [get]
+func (h *UserHandler) HandleGetNotifications(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, ok := h.queryLimit(w, r, 50, 200)
+	if !ok {
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	notifications, err := h.notifyRepo.GetNotifications(claims.UserID, unreadOnly, limit)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get notifications: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	h.writeJSONResponse(w, notifications, http.StatusOK)
+}
+
+// HandleMarkNotificationRead handles marking one notification as read
+// @Summary Mark Notification Read
+// @Description Mark one of the caller's notifications as read
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param notificationID path string true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/notifications/{notificationID}/read [post]
+func (h *UserHandler) HandleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid notification ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notifyRepo.MarkNotificationRead(claims.UserID, notificationID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Notification not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to update notification: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, map[string]string{"message": "Notification marked as read"}, http.StatusOK)
+}
+
+// HandleMarkAllNotificationsRead handles marking every notification as read
+// @Summary Mark All Notifications Read
+// @Description Mark all of the caller's notifications as read
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/notifications/read-all [post]
+func (h *UserHandler) HandleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.notifyRepo.MarkAllNotificationsRead(claims.UserID); err != nil {
+		h.writeErrorResponse(w, "Failed to update notifications: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, map[string]string{"message": "All notifications marked as read"}, http.StatusOK)
+}
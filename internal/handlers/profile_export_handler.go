@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleExportJSONResume handles exporting the caller's profile as a JSON Resume document
+// @Summary Export JSON Resume
+// @Description Download the caller's profile as a JSON Resume document (https://jsonresume.org/schema). Fields the schema has no place for are added as extra properties, so importing the file again gives back the same profile.
+// @Tags User Profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} services.JSONResume
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/export/json-resume [get]
+func (h *UserHandler) HandleExportJSONResume(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.exportProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(services.ProfileToJSONResume(profile)); err != nil {
+		h.writeErrorResponse(w, "Failed to export profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeProfileExport(w, profile, "application/json", ".json", buf.Bytes())
+}
+
+// HandleExportEuropass handles exporting the caller's profile as a Europass CV
+// @Summary Export Europass CV
+// @Description Download the caller's profile as a Europass CV (SkillsPassport XML, v3). Certifications and projects are written as achievements and education grades as a first "Grade:" line. Europass has no place for the about section or employment types other than volunteering, so those are left out.
+// @Tags User Profile
+// @Security BearerAuth
+// @Produce xml
+// @Success 200 {object} services.EuropassDocument
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/export/europass [get]
+func (h *UserHandler) HandleExportEuropass(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.exportProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(services.ProfileToEuropass(profile)); err != nil {
+		h.writeErrorResponse(w, "Failed to export profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeProfileExport(w, profile, "application/xml", "-europass.xml", buf.Bytes())
+}
+
+func (h *UserHandler) exportProfile(w http.ResponseWriter, r *http.Request) (*models.UserProfile, bool) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	profile, err := h.userRepo.GetUserProfileByID(claims.UserID, models.ViewerOwner)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get user profile: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return profile, true
+}
+
+func (h *UserHandler) writeProfileExport(w http.ResponseWriter, profile *models.UserProfile, contentType, suffix string, body []byte) {
+	name := strings.Trim(resumeFileNameUnsafe.ReplaceAllString(strings.ToLower(profile.User.FullName), "-"), "-")
+	if name == "" {
+		name = "profile"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s%s"`, name, suffix))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleGetProfileHistory handles listing the changes made to the current user's profile entries
+// @Summary Get Profile History
+// @Description List changes to phone numbers, education, experience, certifications, projects and skills, newest first, with each entry as it was before and after the change
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param section query string false "Only changes to this section" Enums(phone_numbers, education, experience, certifications, projects, skills)
+// @Param before query string false "Only changes made before this RFC 3339 time"
+// @Param limit query int false "Maximum number of changes (default 50, max 200)"
+// @Success 200 {array} models.ProfileChange
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/history [get]
+func (h *UserHandler) HandleGetProfileHistory(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	section := r.URL.Query().Get("section")
+	if _, ok := models.DefaultSectionVisibility[section]; section != "" && !ok {
+		h.writeErrorResponse(w, "Unknown profile section", http.StatusBadRequest)
+		return
+	}
+
+	var before *time.Time
+	if value := r.URL.Query().Get("before"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid before time, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		before = &t
+	}
+
+	limit, ok := h.queryLimit(w, r, 50, 200)
+	if !ok {
+		return
+	}
+
+	changes, err := h.userRepo.GetProfileHistory(claims.UserID, section, before, limit)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get profile history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if changes == nil {
+		changes = []models.ProfileChange{}
+	}
+
+	h.writeJSONResponse(w, changes, http.StatusOK)
+}
+
+// HandleRestoreProfileEntry handles bringing back a deleted profile entry
+// @Summary Restore Profile Entry
+// @Description Restore the entry a delete in the profile history removed, as it was right before. Experience and project entries are linked again to the skills they were linked to that are still on the profile. Media files attached to the entry were deleted with it and do not come back. Fails with 409 when the entry is already back, when it was the primary phone number and another one is primary now, or when its skill was removed from the skill list.
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param changeID path string true "ID of the delete in the profile history"
+// @Success 201 {object} models.ProfileChange
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/history/{changeID}/restore [post]
+func (h *UserHandler) HandleRestoreProfileEntry(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	changeID, err := uuid.Parse(chi.URLParam(r, "changeID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid change ID format", http.StatusBadRequest)
+		return
+	}
+
+	restored, err := h.userRepo.RestoreProfileEntry(claims.UserID, changeID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "only deleted entries"):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case strings.Contains(err.Error(), "duplicate key"):
+			h.writeErrorResponse(w, "The entry is already on the profile", http.StatusConflict)
+		case strings.Contains(err.Error(), "already primary"), strings.Contains(err.Error(), "no longer exists"):
+			h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+		default:
+			h.writeErrorResponse(w, "Failed to restore entry: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, restored, http.StatusCreated)
+}
+
+// HandleGetProfileAsOf handles reconstructing the current user's profile at a point in time
+// @Summary Get Profile As Of
+// @Description Get the profile with its phone numbers, education, experience, certifications, projects and skills as they were at the time. The name, title, about section and media are shown as they are now.
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Param at query string true "Point in time, RFC 3339"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/as-of [get]
+func (h *UserHandler) HandleGetProfileAsOf(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, r.URL.Query().Get("at"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid at time, expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.userRepo.GetProfileAsOf(claims.UserID, at)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get user profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.signProfileMediaURLs(profile)
+
+	h.writeJSONResponse(w, profile, http.StatusOK)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+const (
+	maxImportSize          = 1 << 20  // 1MB
+	maxLinkedInArchiveSize = 20 << 20 // 20MB, the export also holds connections and messages
+)
+
+// HandleImportJSONResume handles importing a JSON Resume document into the profile
+// @Summary Import JSON Resume
+// @Description Import the name, label, summary, location and phone numbers, work, volunteer, education, certificates, projects and skills from a JSON Resume document (https://jsonresume.org/schema). The email address is never imported. In merge mode new entries are added, existing ones kept and basic fields only filled in where the profile has none; in replace mode the profile is made to match the document. Entries are matched on phone number, institution/degree/start month, company/position/start month, certification name/issuer and project name. Skills that do not exist yet are filed as suggestions for an admin to review. With preview=true nothing is saved and the response shows what would change.
+// @Tags User Profile
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.JSONResume true "JSON Resume document"
+// @Param mode query string false "Import mode" Enums(merge, replace) default(merge)
+// @Param preview query bool false "Only show what would change"
+// @Success 200 {object} models.ProfileImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/import/json-resume [post]
+func (h *UserHandler) HandleImportJSONResume(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mode, preview, ok := h.importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var doc services.JSONResume
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportSize)).Decode(&doc); err != nil {
+		h.writeErrorResponse(w, "Invalid JSON Resume document", http.StatusBadRequest)
+		return
+	}
+
+	data, warnings := services.MapJSONResume(doc)
+	h.importProfile(w, claims.UserID, data, warnings, mode, preview)
+}
+
+// HandleImportEuropass handles importing a Europass CV into the profile
+// @Summary Import Europass CV
+// @Description Import the name, headline, municipality and telephones, work experience, education, skills, and certification and project achievements from a Europass CV (SkillsPassport XML, v3). The first telephone becomes the primary number. Modes, matching and preview work as for the JSON Resume import.
+// @Tags User Profile
+// @Security BearerAuth
+// @Accept xml
+// @Produce json
+// @Param request body services.EuropassDocument true "Europass CV"
+// @Param mode query string false "Import mode" Enums(merge, replace) default(merge)
+// @Param preview query bool false "Only show what would change"
+// @Success 200 {object} models.ProfileImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/import/europass [post]
+func (h *UserHandler) HandleImportEuropass(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mode, preview, ok := h.importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var doc services.EuropassDocument
+	if err := xml.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportSize)).Decode(&doc); err != nil {
+		h.writeErrorResponse(w, "Invalid Europass CV", http.StatusBadRequest)
+		return
+	}
+
+	data, warnings := services.MapEuropass(doc)
+	h.importProfile(w, claims.UserID, data, warnings, mode, preview)
+}
+
+// HandleImportLinkedIn handles importing a LinkedIn data export into the profile
+// @Summary Import LinkedIn Data Export
+// @Description Import positions, education, certifications, projects and skills from the ZIP LinkedIn sends when you download your data. Other files in the archive are ignored. Modes, matching and preview work as for the JSON Resume import; run it with preview=true first to review the changes.
+// @Tags User Profile
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param archive formData file true "LinkedIn data export ZIP"
+// @Param mode query string false "Import mode" Enums(merge, replace) default(merge)
+// @Param preview query bool false "Only show what would change"
+// @Success 200 {object} models.ProfileImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/import/linkedin [post]
+func (h *UserHandler) HandleImportLinkedIn(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mode, preview, ok := h.importOptions(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxLinkedInArchiveSize+1<<10)
+	file, _, err := r.FormFile("archive")
+	if err != nil {
+		h.writeErrorResponse(w, "A LinkedIn data export ZIP of at most 20MB is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	archive, err := io.ReadAll(io.LimitReader(file, maxLinkedInArchiveSize+1))
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to read archive", http.StatusBadRequest)
+		return
+	}
+	if len(archive) > maxLinkedInArchiveSize {
+		h.writeErrorResponse(w, "Archive must be at most 20MB", http.StatusBadRequest)
+		return
+	}
+
+	data, warnings, err := services.MapLinkedInArchive(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidLinkedInArchive) {
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to read archive: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.importProfile(w, claims.UserID, data, warnings, mode, preview)
+}
+
+// importOptions reads the mode and preview query parameters shared by all profile imports
+func (h *UserHandler) importOptions(w http.ResponseWriter, r *http.Request) (string, bool, bool) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = dto.ImportModeMerge
+	}
+	if mode != dto.ImportModeMerge && mode != dto.ImportModeReplace {
+		h.writeErrorResponse(w, "mode must be merge or replace", http.StatusBadRequest)
+		return "", false, false
+	}
+	return mode, r.URL.Query().Get("preview") == "true", true
+}
+
+func (h *UserHandler) importProfile(w http.ResponseWriter, userID uuid.UUID, data dto.ProfileImport, warnings []string, mode string, preview bool) {
+	result, err := h.userRepo.ImportProfile(userID, data, mode, preview)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to import profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result.Warnings = warnings
+
+	// Media of entries the import removed is gone from the database, drop the files too
+	for _, key := range result.RemovedFileKeys {
+		if err := h.storage.Delete(key); err != nil {
+			log.Printf("failed to delete media file %s of user %s: %v", key, userID, err)
+		}
+	}
+
+	h.writeJSONResponse(w, result, http.StatusOK)
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleUploadProfilePicture handles replacing the current user's profile picture
+// @Summary Upload Profile Picture
+// @Description Upload a JPEG, PNG or GIF image as the profile picture. It is cropped to a square, the crop_* fields pick which one (in pixels of the upright image), and scaled down to 512x512. Without them the largest square in the middle is kept.
+// @Tags User
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param picture formData file true "Profile picture"
+// @Param crop_x formData int false "Left edge of the square"
+// @Param crop_y formData int false "Top edge of the square"
+// @Param crop_size formData int false "Side of the square"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/picture [post]
+func (h *UserHandler) HandleUploadProfilePicture(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	maxSize := services.MediaPolicies["image"].MaxSize
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		h.writeErrorResponse(w, "A picture of at most "+strconv.FormatInt(maxSize>>20, 10)+"MB is required", status)
+		return
+	}
+
+	file, header, err := r.FormFile("picture")
+	if err != nil {
+		h.writeErrorResponse(w, "No picture provided", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var crop services.AvatarCrop
+	for field, value := range map[string]*int{"crop_x": &crop.X, "crop_y": &crop.Y, "crop_size": &crop.Size} {
+		if raw := r.FormValue(field); raw != "" {
+			if *value, err = strconv.Atoi(raw); err != nil {
+				h.writeErrorResponse(w, field+" must be a whole number of pixels", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	avatar, err := services.ProcessAvatar(file, header.Size, crop)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAvatarCrop):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrMediaTooLarge):
+			h.writeErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, services.ErrMediaTypeNotAllowed):
+			h.writeErrorResponse(w, err.Error(), http.StatusUnsupportedMediaType)
+		default:
+			h.writeErrorResponse(w, "Failed to process picture: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Re-encoded from its pixels, the picture needs no malware scan before others see it
+	key := "users/" + claims.UserID.String() + "/picture-" + uuid.NewString() + avatar.Extension
+	if err := h.storage.Save(key, bytes.NewReader(avatar.Data)); err != nil {
+		h.writeErrorResponse(w, "Failed to store picture: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	previous, err := h.userRepo.SetProfilePicture(claims.UserID, &key)
+	if err != nil {
+		h.storage.Delete(key)
+		h.writeErrorResponse(w, "Failed to update profile picture: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.deleteProfilePicture(claims.UserID, previous)
+
+	h.writeProfileUser(w, claims.UserID)
+}
+
+// HandleDeleteProfilePicture handles removing the current user's profile picture
+// @Summary Delete Profile Picture
+// @Description Remove the current user's profile picture
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.User
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/picture [delete]
+func (h *UserHandler) HandleDeleteProfilePicture(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	previous, err := h.userRepo.SetProfilePicture(claims.UserID, nil)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to delete profile picture: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.deleteProfilePicture(claims.UserID, previous)
+
+	h.writeProfileUser(w, claims.UserID)
+}
+
+// HandleGetProfilePicture handles serving a user's profile picture
+// @Summary Get Profile Picture
+// @Description Download the user's square profile picture. The owner always gets it, anyone else only when the profile_picture visibility allows: signed-in recruiters see pictures shown to recruiters, everyone else only public ones.
+// @Tags User
+// @Security BearerAuth
+// @Produce image/jpeg,image/png
+// @Param userID path string true "User ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{userID}/picture [get]
+func (h *UserHandler) HandleGetProfilePicture(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	viewer := models.ViewerPublic
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims); ok {
+		switch {
+		case claims.UserID == userID:
+			viewer = models.ViewerOwner
+		case claims.Role == "recruiter":
+			viewer = models.ViewerRecruiter
+		}
+	}
+
+	// Hidden pictures look like missing ones
+	key, err := h.userRepo.GetProfilePicture(userID, viewer)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "User has no profile picture", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get profile picture: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	file, err := h.storage.Open(*key)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			h.writeErrorResponse(w, "User has no profile picture", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to read profile picture: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	contentType := "image/jpeg"
+	if strings.HasSuffix(*key, ".png") {
+		contentType = "image/png"
+	}
+	w.Header().Set("Content-Type", contentType)
+	// Not kept by shared caches, the answer depends on the caller and the picture can be hidden later
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("Vary", "Authorization")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+// deleteProfilePicture removes a picture that is no longer used from storage
+func (h *UserHandler) deleteProfilePicture(userID uuid.UUID, key *string) {
+	if key == nil {
+		return
+	}
+	if err := h.storage.Delete(*key); err != nil {
+		log.Printf("failed to delete previous profile picture of user %s: %v", userID, err)
+	}
+}
+
+// writeProfileUser responds with the user as they are now
+func (h *UserHandler) writeProfileUser(w http.ResponseWriter, userID uuid.UUID) {
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get user: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, user, http.StatusOK)
+}
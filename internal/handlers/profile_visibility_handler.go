@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// profileSlugPattern keeps public profile links readable: lowercase words joined by hyphens
+var profileSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// HandleGetProfileVisibility handles getting who sees which parts of the current user's profile
+// @Summary Get Profile Visibility
+// @Description Get the public link of the profile, whether it is published, and the visibility of every section. Phone numbers are shown to recruiters and the rest of the profile publicly until set otherwise.
+// @Tags User
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ProfileVisibility
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/visibility [get]
+func (h *UserHandler) HandleGetProfileVisibility(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	visibility, err := h.userRepo.GetProfileVisibility(claims.UserID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get profile visibility: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, visibility, http.StatusOK)
+}
+
+// HandleUpdateProfileVisibility handles changing the public link and section visibility of the current user's profile
+// @Summary Update Profile Visibility
+// @Description Pick the slug of the public profile link (lowercase letters, digits and hyphens), publish or unpublish it, and set who sees each section: public, recruiters or private. The profile_picture section decides who can download the profile picture. The owner always sees everything.
+// @Tags User
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateProfileVisibilityRequest true "Visibility changes"
+// @Success 200 {object} models.ProfileVisibility
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/visibility [put]
+func (h *UserHandler) HandleUpdateProfileVisibility(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.UpdateProfileVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+	if req.Slug != nil && *req.Slug != "" && !profileSlugPattern.MatchString(*req.Slug) {
+		h.writeErrorResponse(w, "slug can only contain lowercase letters and digits separated by single hyphens", http.StatusBadRequest)
+		return
+	}
+
+	visibility, err := h.userRepo.UpdateProfileVisibility(claims.UserID, req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "duplicate key"):
+			h.writeErrorResponse(w, "This slug is already taken", http.StatusConflict)
+		case strings.Contains(err.Error(), "slug is required"):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.writeErrorResponse(w, "Failed to update profile visibility: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, visibility, http.StatusOK)
+}
+
+// HandleSetEntryVisibility handles overriding the visibility of a single profile entry
+// @Summary Set Entry Visibility
+// @Description Show one entry to a different audience than the rest of its section, e.g. keep a single job private. A null visibility makes the entry follow its section again. Skills are addressed by skill ID.
+// @Tags User
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param section path string true "Profile section" Enums(phone_numbers, education, experience, certifications, projects, skills)
+// @Param entryID path string true "Entry ID"
+// @Param request body dto.SetEntryVisibilityRequest true "Visibility of the entry"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/visibility/{section}/{entryID} [put]
+func (h *UserHandler) HandleSetEntryVisibility(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	section := chi.URLParam(r, "section")
+	if _, ok := models.DefaultSectionVisibility[section]; !ok {
+		h.writeErrorResponse(w, "Unknown profile section", http.StatusBadRequest)
+		return
+	}
+
+	entryID := chi.URLParam(r, "entryID")
+	if section == models.ProfileSectionSkills {
+		if _, err := strconv.Atoi(entryID); err != nil {
+			h.writeErrorResponse(w, "Invalid skill ID format", http.StatusBadRequest)
+			return
+		}
+	} else if _, err := uuid.Parse(entryID); err != nil {
+		h.writeErrorResponse(w, "Invalid entry ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SetEntryVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userRepo.SetEntryVisibility(claims.UserID, section, entryID, req.Visibility); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Entry not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to set entry visibility: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, map[string]string{"message": "Entry visibility updated successfully"}, http.StatusOK)
+}
+
+// HandleGetPublicProfile handles getting a published profile by its slug
+// @Summary Get Public Profile
+// @Description Get a profile someone published. Anonymous visitors see the public sections and entries; signed-in recruiters also see what is shown to recruiters.
+// @Tags Profiles
+// @Produce json
+// @Param slug path string true "Profile slug"
+// @Success 200 {object} models.PublicProfile
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /profiles/{slug} [get]
+func (h *UserHandler) HandleGetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	viewer := models.ViewerPublic
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims); ok && claims.Role == "recruiter" {
+		viewer = models.ViewerRecruiter
+	}
+
+	profile, err := h.userRepo.GetPublicProfile(slug, viewer)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Profile not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	visible := profile.WithScannedMediaOnly()
+	h.signProfileMediaURLs(&visible)
+
+	h.writeJSONResponse(w, visible.Public(slug), http.StatusOK)
+}
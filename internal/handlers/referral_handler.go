@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+	"github.com/Andrew-Ayman123/Job-Hunter/utils/env"
+)
+
+// referralCookie carries a referral code from the job page through sign-up to the application
+const referralCookie = "referral_code"
+
+// HandleCreateReferralLink handles generating a referral link for one of the user's company jobs
+// @Summary Create Referral Link
+// @Description Get a shareable referral link for a job at the caller's company. Calling it again returns the same link.
+// @Tags Referrals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateReferralLinkRequest true "Job to refer candidates to"
+// @Success 201 {object} models.ReferralLink
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/referrals [post]
+func (h *UserHandler) HandleCreateReferralLink(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreateReferralLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobRepo.GetJobByID(req.JobID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Only people who belong to the hiring company can refer candidates
+	member, err := h.userRepo.GetRecruiterByUserID(claims.UserID)
+	if err != nil || member.CompanyID == nil || job.CompanyID == nil || *member.CompanyID != *job.CompanyID {
+		h.writeErrorResponse(w, "You can only refer candidates to jobs at your company", http.StatusForbidden)
+		return
+	}
+
+	link, err := h.referralRepo.CreateReferralLink(claims.UserID, job.ID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to create referral link: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	link.URL = referralURL(*link)
+
+	h.writeJSONResponse(w, link, http.StatusCreated)
+}
+
+// HandleGetMyReferrals handles listing the caller's referral links and the applications they brought in
+// @Summary Get My Referrals
+// @Description List the caller's referral links with the current status of every referred application. Applicants are shown by an alias until they agree to share their name or the application reaches the job's reveal stage.
+// @Tags Referrals
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.ReferralLink
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/referrals [get]
+func (h *UserHandler) HandleGetMyReferrals(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	links, err := h.referralRepo.GetReferralLinksByReferrer(claims.UserID)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get referrals: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range links {
+		links[i].URL = referralURL(links[i])
+	}
+
+	h.writeJSONResponse(w, links, http.StatusOK)
+}
+
+// rememberReferral stores the ref query parameter in a cookie so it survives sign-up
+func rememberReferral(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("ref")
+	if code == "" {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     referralCookie,
+		Value:    code,
+		Path:     "/",
+		MaxAge:   30 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// referralCode returns the explicit code if given, otherwise the one remembered in the cookie
+func referralCode(r *http.Request, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if cookie, err := r.Cookie(referralCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func referralURL(link models.ReferralLink) string {
+	base := strings.TrimRight(env.GetEnv("REFERRAL_BASE_URL", "http://localhost:8080/api/v1/jobs"), "/")
+	return base + "/" + link.JobID.String() + "?ref=" + link.Code
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
+)
+
+// HandleGetMyResume handles downloading the caller's profile as a résumé
+// @Summary Download My Résumé
+// @Description Render the caller's profile as a résumé in the chosen template and format
+// @Tags User Profile
+// @Security BearerAuth
+// @Produce application/pdf,text/html,text/markdown
+// @Param format query string false "Output format" Enums(pdf, html, markdown) default(pdf)
+// @Param template query string false "Résumé template" Enums(classic, modern, compact) default(classic)
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/resume [get]
+func (h *UserHandler) HandleGetMyResume(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.userRepo.GetUserProfileByID(claims.UserID, models.ViewerOwner)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get user profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeResume(w, r, profile)
+}
+
+// HandleGetApplicantResume handles downloading the résumé of a candidate who applied to the recruiter's company
+// @Summary Download Applicant Résumé
+// @Description Render the applicant's profile as it was submitted with the application as a résumé. Applicants of blind hiring jobs are anonymized until the reveal stage.
+// @Tags Recruiter
+// @Security BearerAuth
+// @Produce application/pdf,text/html,text/markdown
+// @Param applicationID path string true "Application ID"
+// @Param format query string false "Output format" Enums(pdf, html, markdown) default(pdf)
+// @Param template query string false "Résumé template" Enums(classic, modern, compact) default(classic)
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /recruiter/applications/{applicationID}/resume [get]
+func (h *UserHandler) HandleGetApplicantResume(w http.ResponseWriter, r *http.Request) {
+	application, ok := h.authorizeRecruiterApplication(w, r)
+	if !ok {
+		return
+	}
+
+	profile, err := h.applicantProfile(application)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get applicant profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The résumé must not reveal more than the application view does
+	if application.Anonymized {
+		anonymized := profile.Anonymize(models.CandidateAlias(application.ID))
+		profile = &anonymized
+	}
+
+	h.writeResume(w, r, profile)
+}
+
+var resumeFileNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// writeResume renders the profile in the format and template asked for in the query
+// and sends it as a download
+func (h *UserHandler) writeResume(w http.ResponseWriter, r *http.Request, profile *models.UserProfile) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = services.ResumeFormatPDF
+	}
+
+	// Render fully before writing so a failure can still be reported as an error
+	var buf bytes.Buffer
+	if err := services.RenderResume(&buf, profile, r.URL.Query().Get("template"), format); err != nil {
+		if errors.Is(err, services.ErrUnknownResumeTemplate) || errors.Is(err, services.ErrUnknownResumeFormat) {
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to render resume: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	name := strings.Trim(resumeFileNameUnsafe.ReplaceAllString(strings.ToLower(profile.User.FullName), "-"), "-")
+	if name == "" {
+		name = "resume"
+	} else {
+		name += "-resume"
+	}
+
+	contentType, ext := services.ResumeContentType(format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s%s"`, name, ext))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
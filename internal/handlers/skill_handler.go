@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+)
+
+// HandleGetSkillSuggestions handles listing skills users asked for
+// @Summary List Skill Suggestions
+// @Description Pending skill names users brought in that are not in the skills list yet, grouped by name and most requested first
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.SkillSuggestion
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/suggestions [get]
+func (h *UserHandler) HandleGetSkillSuggestions(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := h.skillRepo.GetSkillSuggestions()
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get skill suggestions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, suggestions, http.StatusOK)
+}
+
+// HandleApproveSkillSuggestion handles turning a suggested name into a skill
+// @Summary Approve Skill Suggestion
+// @Description Add the suggested skill to the skills list (or reuse the existing skill of the same name, ignoring case) and add it to every user who suggested it
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.ReviewSkillSuggestionRequest true "Suggested skill name"
+// @Success 200 {object} models.Skill
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/suggestions/approve [post]
+func (h *UserHandler) HandleApproveSkillSuggestion(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReviewSkillSuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	skill, err := h.skillRepo.ApproveSkillSuggestion(strings.TrimSpace(req.Name))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Skill suggestion not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to approve skill suggestion: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, skill, http.StatusOK)
+}
+
+// HandleRejectSkillSuggestion handles dismissing a suggested skill name
+// @Summary Reject Skill Suggestion
+// @Description Dismiss every pending suggestion of this name
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Param request body dto.ReviewSkillSuggestionRequest true "Suggested skill name"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/suggestions/reject [post]
+func (h *UserHandler) HandleRejectSkillSuggestion(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReviewSkillSuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.skillRepo.RejectSkillSuggestion(strings.TrimSpace(req.Name)); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Skill suggestion not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to reject skill suggestion: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetSkillTaxonomy handles listing the skill categories with their skills
+// @Summary Get Skill Taxonomy
+// @Description Every skill category with its skills, broader skills with the narrower ones nested under them (React under JavaScript) and each skill's aliases. Skills without a category are listed last under Uncategorized.
+// @Tags Skills
+// @Produce json
+// @Success 200 {array} models.SkillCategory
+// @Failure 500 {object} map[string]string
+// @Router /skills/categories [get]
+func (h *UserHandler) HandleGetSkillTaxonomy(w http.ResponseWriter, r *http.Request) {
+	taxonomy, err := h.skillRepo.GetSkillTaxonomy()
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get skill taxonomy: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, taxonomy, http.StatusOK)
+}
+
+// HandleCreateSkill handles adding a skill to the skills list
+// @Summary Create Skill
+// @Description Add a skill, optionally in a category, under a broader parent skill and with aliases users can find and add it by. The name and aliases must not be the name or an alias of another skill, ignoring case.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.SkillRequest true "Skill"
+// @Success 201 {object} models.Skill
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills [post]
+func (h *UserHandler) HandleCreateSkill(w http.ResponseWriter, r *http.Request) {
+	var req dto.SkillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	trimSkillRequest(&req)
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	skill, err := h.skillRepo.CreateSkill(req)
+	if err != nil {
+		h.writeSkillError(w, "Failed to create skill", err)
+		return
+	}
+
+	h.writeJSONResponse(w, skill, http.StatusCreated)
+}
+
+// HandleUpdateSkill handles renaming a skill or changing its place in the taxonomy
+// @Summary Update Skill
+// @Description Replace a skill's name, category, parent and aliases. A skill cannot be placed under itself or one of its own children.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param skillID path int true "Skill ID"
+// @Param request body dto.SkillRequest true "Skill"
+// @Success 200 {object} models.Skill
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/{skillID} [put]
+func (h *UserHandler) HandleUpdateSkill(w http.ResponseWriter, r *http.Request) {
+	skillID, err := strconv.Atoi(chi.URLParam(r, "skillID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid skill ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SkillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	trimSkillRequest(&req)
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	skill, err := h.skillRepo.UpdateSkill(skillID, req)
+	if err != nil {
+		h.writeSkillError(w, "Failed to update skill", err)
+		return
+	}
+
+	h.writeJSONResponse(w, skill, http.StatusOK)
+}
+
+// HandleDeleteSkill handles removing a skill from the skills list
+// @Summary Delete Skill
+// @Description Delete a skill. The skill is taken off every profile and its narrower skills move to the top level, unless merge_into names the skill users get instead; the deleted skill's name and aliases then become aliases of that skill and its narrower skills move under it. A merge_into skill below the deleted one first takes its place in the tree.
+// @Tags Admin
+// @Security BearerAuth
+// @Param skillID path int true "Skill ID"
+// @Param merge_into query int false "Skill that replaces the deleted one"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/{skillID} [delete]
+func (h *UserHandler) HandleDeleteSkill(w http.ResponseWriter, r *http.Request) {
+	skillID, err := strconv.Atoi(chi.URLParam(r, "skillID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid skill ID format", http.StatusBadRequest)
+		return
+	}
+
+	var mergeInto *int
+	if value := r.URL.Query().Get("merge_into"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			h.writeErrorResponse(w, "Invalid merge_into skill ID format", http.StatusBadRequest)
+			return
+		}
+		mergeInto = &id
+	}
+
+	if err := h.skillRepo.DeleteSkill(skillID, mergeInto); err != nil {
+		h.writeSkillError(w, "Failed to delete skill", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleCreateSkillCategory handles adding a skill category
+// @Summary Create Skill Category
+// @Description Add a category skills can be grouped under
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.SkillCategoryRequest true "Category"
+// @Success 201 {object} models.SkillCategory
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/categories [post]
+func (h *UserHandler) HandleCreateSkillCategory(w http.ResponseWriter, r *http.Request) {
+	var req dto.SkillCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.skillRepo.CreateSkillCategory(req.Name)
+	if err != nil {
+		h.writeSkillError(w, "Failed to create skill category", err)
+		return
+	}
+
+	h.writeJSONResponse(w, category, http.StatusCreated)
+}
+
+// HandleUpdateSkillCategory handles renaming a skill category
+// @Summary Update Skill Category
+// @Description Rename a skill category
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param categoryID path int true "Category ID"
+// @Param request body dto.SkillCategoryRequest true "Category"
+// @Success 200 {object} models.SkillCategory
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/categories/{categoryID} [put]
+func (h *UserHandler) HandleUpdateSkillCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.Atoi(chi.URLParam(r, "categoryID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid category ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SkillCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.skillRepo.UpdateSkillCategory(categoryID, req.Name)
+	if err != nil {
+		h.writeSkillError(w, "Failed to update skill category", err)
+		return
+	}
+
+	h.writeJSONResponse(w, category, http.StatusOK)
+}
+
+// HandleDeleteSkillCategory handles removing a skill category
+// @Summary Delete Skill Category
+// @Description Delete a skill category, its skills are kept without a category
+// @Tags Admin
+// @Security BearerAuth
+// @Param categoryID path int true "Category ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/skills/categories/{categoryID} [delete]
+func (h *UserHandler) HandleDeleteSkillCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.Atoi(chi.URLParam(r, "categoryID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid category ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.skillRepo.DeleteSkillCategory(categoryID); err != nil {
+		h.writeSkillError(w, "Failed to delete skill category", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// trimSkillRequest trims the name and aliases, so blank ones fail validation
+func trimSkillRequest(req *dto.SkillRequest) {
+	req.Name = strings.TrimSpace(req.Name)
+	for i := range req.Aliases {
+		req.Aliases[i] = strings.TrimSpace(req.Aliases[i])
+	}
+}
+
+// writeSkillError answers a failed taxonomy change: names in use conflict, a missing skill or
+// category is not found and pointing at ones that do not exist or at a child is a bad request
+func (h *UserHandler) writeSkillError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "already taken"), strings.Contains(err.Error(), "duplicate key"):
+		h.writeErrorResponse(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "does not exist"), strings.Contains(err.Error(), "cannot be"):
+		h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.writeErrorResponse(w, message+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
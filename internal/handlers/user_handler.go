@@ -1,21 +1,29 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/middleware"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/services"
 )
 
 // HandleGetProfile handles getting user profile
 // @Summary Get User Profile
-// @Description Get the current user's profile
+// @Description Get the current user's profile with its completeness score and suggestions for what to add next, most valuable first
 // @Tags User
 // @Security BearerAuth
 // @Produce json
@@ -30,12 +38,65 @@ func (h *UserHandler) HandleGetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userRepo.GetUserProfileByID(claims.UserID)
+	user, err := h.userRepo.GetUserProfileByID(claims.UserID, models.ViewerOwner)
 	if err != nil {
 		// write error
 		h.writeErrorResponse(w, "Failed to get user profile "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.signProfileMediaURLs(user)
+	completeness := user.ScoreCompleteness()
+	user.Completeness = &completeness
+
+	h.writeJSONResponse(w, user, http.StatusOK)
+}
+
+// HandleUpdateProfile handles editing the basic profile fields
+// @Summary Update User Profile
+// @Description Change the current user's name, location, title or about section. Fields left out stay as they are, an empty location, title or about section clears it.
+// @Tags User
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateProfileRequest true "Profile fields to change"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile [patch]
+func (h *UserHandler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.FullName == nil && req.Location == nil && req.Title == nil && req.AboutSection == nil {
+		h.writeErrorResponse(w, "At least one field must be provided for update", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userRepo.UpdateUserProfile(claims.UserID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to update profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	h.writeJSONResponse(w, user, http.StatusOK)
 }
@@ -298,11 +359,15 @@ func (h *UserHandler) HandleDeleteEducation(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// The media rows go with the entry, their files have to be removed separately
+	media, _ := h.userRepo.GetMedia(claims.UserID, models.MediaEntityEducation, educationIDProcessed)
+
 	err = h.userRepo.DeleteEducation(claims.UserID, educationIDProcessed)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to delete education: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.deleteMediaFiles(media)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -431,11 +496,15 @@ func (h *UserHandler) HandleDeleteExperience(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// The media rows go with the entry, their files have to be removed separately
+	media, _ := h.userRepo.GetMedia(claims.UserID, models.MediaEntityExperience, experienceIDProcessed)
+
 	err = h.userRepo.DeleteExperience(claims.UserID, experienceIDProcessed)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to delete experience: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.deleteMediaFiles(media)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -564,11 +633,15 @@ func (h *UserHandler) HandleDeleteCertification(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// The media rows go with the entry, their files have to be removed separately
+	media, _ := h.userRepo.GetMedia(claims.UserID, models.MediaEntityCertification, certificationIDProcessed)
+
 	err = h.userRepo.DeleteCertification(claims.UserID, certificationIDProcessed)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to delete certification: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.deleteMediaFiles(media)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -697,11 +770,15 @@ func (h *UserHandler) HandleDeleteProject(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// The media rows go with the entry, their files have to be removed separately
+	media, _ := h.userRepo.GetMedia(claims.UserID, models.MediaEntityProject, projectIDProcessed)
+
 	err = h.userRepo.DeleteProject(claims.UserID, projectIDProcessed)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to delete project: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.deleteMediaFiles(media)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -709,7 +786,7 @@ func (h *UserHandler) HandleDeleteProject(w http.ResponseWriter, r *http.Request
 // SKILLS ENDPOINTS
 
 // @Summary Search Skills
-// @Description Search for skills by name
+// @Description Search for skills by name or alias, ignoring case. Skills the query is the exact name or alias of come first.
 // @Tags Skills
 // @Produce json
 // @Param q query string true "Search query"
@@ -734,7 +811,7 @@ func (h *UserHandler) HandleSearchSkills(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Add Skills to User
-// @Description Add skills to the current user's profile
+// @Description Add skills to the current user's profile by ID or by name, an entry of skills takes one of the two. Names are resolved through the skills' aliases, so "golang" adds Go. Skills in skills carry a proficiency, years used, the year last used and the experience and project entries they were used in; adding a skill the user already has that way replaces its details.
 // @Tags User Profile
 // @Security BearerAuth
 // @Accept json
@@ -764,14 +841,29 @@ func (h *UserHandler) HandleAddUserSkills(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err := h.userRepo.AddUserSkills(claims.UserID, req.SkillIDs)
+	if len(req.SkillIDs)+len(req.SkillNames)+len(req.Skills) == 0 {
+		h.writeErrorResponse(w, "At least one skill is required", http.StatusBadRequest)
+		return
+	}
+	for _, skill := range req.Skills {
+		if message := validateSkillDetails(skill.UserSkillDetails); message != "" {
+			h.writeErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := h.userRepo.AddUserSkills(claims.UserID, req)
 	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		h.writeErrorResponse(w, "Failed to add skills: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Return updated skills list
-	skills, err := h.userRepo.GetUserSkillsByID(claims.UserID)
+	skills, err := h.userRepo.GetUserSkillsByID(claims.UserID, models.ViewerOwner)
 	if err != nil {
 		h.writeErrorResponse(w, "Failed to get updated skills: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -780,6 +872,79 @@ func (h *UserHandler) HandleAddUserSkills(w http.ResponseWriter, r *http.Request
 	h.writeJSONResponse(w, skills, http.StatusOK)
 }
 
+// @Summary Update User Skill
+// @Description Replace the proficiency, years used, year last used and linked experience and project entries of a skill on the current user's profile
+// @Tags User Profile
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param skillID path int true "Skill ID"
+// @Param request body dto.UserSkillDetails true "Skill details"
+// @Success 200 {array} models.Skill
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/skills/{skillID} [put]
+func (h *UserHandler) HandleUpdateUserSkill(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	skillID, err := strconv.Atoi(chi.URLParam(r, "skillID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid Skill ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UserSkillDetails
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Basic validation
+	if validationErrors, err := h.validateStruct(req); err != nil {
+		h.writeJSONResponse(w, validationErrors, http.StatusBadRequest)
+		return
+	}
+	if message := validateSkillDetails(req); message != "" {
+		h.writeErrorResponse(w, message, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userRepo.UpdateUserSkill(claims.UserID, skillID, req); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "on the profile"):
+			h.writeErrorResponse(w, "Skill not found on the profile", http.StatusNotFound)
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.writeErrorResponse(w, "Failed to update skill: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Return updated skills list
+	skills, err := h.userRepo.GetUserSkillsByID(claims.UserID, models.ViewerOwner)
+	if err != nil {
+		h.writeErrorResponse(w, "Failed to get updated skills: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSONResponse(w, skills, http.StatusOK)
+}
+
+// validateSkillDetails checks what the validator tags cannot, the year last used is not in the future
+func validateSkillDetails(details dto.UserSkillDetails) string {
+	if details.LastUsedYear != nil && *details.LastUsedYear > time.Now().Year() {
+		return "last_used_year cannot be in the future"
+	}
+	return ""
+}
+
 // @Summary Remove Skill from User
 // @Description Remove a skill from the current user's profile
 // @Tags User Profile
@@ -818,279 +983,349 @@ func (h *UserHandler) HandleRemoveUserSkill(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// // MEDIA UPLOAD ENDPOINTS
-
-// // @Summary Upload Education Media
-// // @Description Upload media files for an education entry
-// // @Tags Media
-// // @Security BearerAuth
-// // @Accept multipart/form-data
-// // @Produce json
-// // @Param id path string true "Education ID"
-// // @Param file formData file true "Media file"
-// // @Param media_type formData string false "Media type (image, video, document)"
-// // @Param alt_text formData string false "Alt text for accessibility"
-// // @Param description formData string false "Media description"
-// // @Success 201 {object} models.UserMedia
-// // @Failure 400 {object} map[string]string
-// // @Failure 401 {object} map[string]string
-// // @Failure 500 {object} map[string]string
-// // @Router /user/profile/education/{id}/media [post]
-// func (h *UserHandler) HandleUploadEducationMedia(w http.ResponseWriter, r *http.Request) {
-// 	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
-// 	if !ok {
-// 		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
-// 		return
-// 	}
-
-// 	vars := mux.Vars(r)
-// 	educationID, err := uuid.Parse(vars["id"])
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Invalid education ID", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	// Parse multipart form (max 32MB)
-// 	err = r.ParseMultipartForm(32 << 20)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	file, header, err := r.FormFile("file")
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "No file provided", http.StatusBadRequest)
-// 		return
-// 	}
-// 	defer file.Close()
-
-// 	mediaType := r.FormValue("media_type")
-// 	if mediaType == "" {
-// 		mediaType = "document" // default
-// 	}
-
-// 	altText := r.FormValue("alt_text")
-// 	description := r.FormValue("description")
-
-// 	media, err := h.mediaService.UploadEducationMedia(claims.UserID, educationID, file, header.Filename, mediaType, altText, description)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to upload media: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	h.writeJSONResponse(w, media, http.StatusCreated)
-// }
-
-// // @Summary Upload Experience Media
-// // @Description Upload media files for an experience entry
-// // @Tags Media
-// // @Security BearerAuth
-// // @Accept multipart/form-data
-// // @Produce json
-// // @Param id path string true "Experience ID"
-// // @Param file formData file true "Media file"
-// // @Param media_type formData string false "Media type (image, video, document)"
-// // @Param alt_text formData string false "Alt text for accessibility"
-// // @Param description formData string false "Media description"
-// // @Success 201 {object} models.UserMedia
-// // @Failure 400 {object} map[string]string
-// // @Failure 401 {object} map[string]string
-// // @Failure 500 {object} map[string]string
-// // @Router /user/profile/experience/{id}/media [post]
-// func (h *UserHandler) HandleUploadExperienceMedia(w http.ResponseWriter, r *http.Request) {
-// 	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
-// 	if !ok {
-// 		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
-// 		return
-// 	}
-
-// 	vars := mux.Vars(r)
-// 	experienceID, err := uuid.Parse(vars["id"])
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Invalid experience ID", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	err = r.ParseMultipartForm(32 << 20)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	file, header, err := r.FormFile("file")
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "No file provided", http.StatusBadRequest)
-// 		return
-// 	}
-// 	defer file.Close()
-
-// 	mediaType := r.FormValue("media_type")
-// 	if mediaType == "" {
-// 		mediaType = "document"
-// 	}
-
-// 	altText := r.FormValue("alt_text")
-// 	description := r.FormValue("description")
-
-// 	media, err := h.mediaService.UploadExperienceMedia(claims.UserID, experienceID, file, header.Filename, mediaType, altText, description)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to upload media: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	h.writeJSONResponse(w, media, http.StatusCreated)
-// }
-
-// // @Summary Upload Certification Media
-// // @Description Upload media files for a certification entry
-// // @Tags Media
-// // @Security BearerAuth
-// // @Accept multipart/form-data
-// // @Produce json
-// // @Param id path string true "Certification ID"
-// // @Param file formData file true "Media file"
-// // @Param media_type formData string false "Media type (image, video, document)"
-// // @Param alt_text formData string false "Alt text for accessibility"
-// // @Param description formData string false "Media description"
-// // @Success 201 {object} models.UserMedia
-// // @Failure 400 {object} map[string]string
-// // @Failure 401 {object} map[string]string
-// // @Failure 500 {object} map[string]string
-// // @Router /user/profile/certifications/{id}/media [post]
-// func (h *UserHandler) HandleUploadCertificationMedia(w http.ResponseWriter, r *http.Request) {
-// 	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
-// 	if !ok {
-// 		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
-// 		return
-// 	}
-
-// 	vars := mux.Vars(r)
-// 	certificationID, err := uuid.Parse(vars["id"])
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Invalid certification ID", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	err = r.ParseMultipartForm(32 << 20)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	file, header, err := r.FormFile("file")
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "No file provided", http.StatusBadRequest)
-// 		return
-// 	}
-// 	defer file.Close()
-
-// 	mediaType := r.FormValue("media_type")
-// 	if mediaType == "" {
-// 		mediaType = "document"
-// 	}
-
-// 	altText := r.FormValue("alt_text")
-// 	description := r.FormValue("description")
-
-// 	media, err := h.mediaService.UploadCertificationMedia(claims.UserID, certificationID, file, header.Filename, mediaType, altText, description)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to upload media: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	h.writeJSONResponse(w, media, http.StatusCreated)
-// }
-
-// // @Summary Upload Project Media
-// // @Description Upload media files for a project entry
-// // @Tags Media
-// // @Security BearerAuth
-// // @Accept multipart/form-data
-// // @Produce json
-// // @Param id path string true "Project ID"
-// // @Param file formData file true "Media file"
-// // @Param media_type formData string false "Media type (image, video, document)"
-// // @Param alt_text formData string false "Alt text for accessibility"
-// // @Param description formData string false "Media description"
-// // @Success 201 {object} models.UserMedia
-// // @Failure 400 {object} map[string]string
-// // @Failure 401 {object} map[string]string
-// // @Failure 500 {object} map[string]string
-// // @Router /user/profile/projects/{id}/media [post]
-// func (h *UserHandler) HandleUploadProjectMedia(w http.ResponseWriter, r *http.Request) {
-// 	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
-// 	if !ok {
-// 		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
-// 		return
-// 	}
-
-// 	vars := mux.Vars(r)
-// 	projectID, err := uuid.Parse(vars["id"])
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Invalid project ID", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	err = r.ParseMultipartForm(32 << 20)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	file, header, err := r.FormFile("file")
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "No file provided", http.StatusBadRequest)
-// 		return
-// 	}
-// 	defer file.Close()
-
-// 	mediaType := r.FormValue("media_type")
-// 	if mediaType == "" {
-// 		mediaType = "document"
-// 	}
-
-// 	altText := r.FormValue("alt_text")
-// 	description := r.FormValue("description")
-
-// 	media, err := h.mediaService.UploadProjectMedia(claims.UserID, projectID, file, header.Filename, mediaType, altText, description)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to upload media: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	h.writeJSONResponse(w, media, http.StatusCreated)
-// }
-
-// // @Summary Delete Media
-// // @Description Delete a media file
-// // @Tags Media
-// // @Security BearerAuth
-// // @Param mediaId path string true "Media ID"
-// // @Success 204
-// // @Failure 400 {object} map[string]string
-// // @Failure 401 {object} map[string]string
-// // @Failure 404 {object} map[string]string
-// // @Failure 500 {object} map[string]string
-// // @Router /user/profile/media/{mediaId} [delete]
-// func (h *UserHandler) HandleDeleteMedia(w http.ResponseWriter, r *http.Request) {
-// 	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
-// 	if !ok {
-// 		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
-// 		return
-// 	}
-
-// 	vars := mux.Vars(r)
-// 	mediaID, err := uuid.Parse(vars["mediaId"])
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Invalid media ID", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	err = h.mediaService.DeleteMedia(claims.UserID, mediaID)
-// 	if err != nil {
-// 		h.writeErrorResponse(w, "Failed to delete media: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	w.WriteHeader(http.StatusNoContent)
-// }
+// MEDIA ENDPOINTS
+
+// @Summary Upload Education Media
+// @Description Upload a media file for an education entry. Recruiters see it once a malware scan found it clean, until then its scan_status is pending
+// @Tags Media
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param educationID path string true "Education ID"
+// @Param file formData file true "Media file"
+// @Param media_type formData string false "Media type (image, video, document), taken from the content when left out"
+// @Param alt_text formData string false "Alt text for accessibility"
+// @Param description formData string false "Media description"
+// @Success 201 {object} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/education/{educationID}/media [post]
+func (h *UserHandler) HandleUploadEducationMedia(w http.ResponseWriter, r *http.Request) {
+	h.uploadMedia(w, r, models.MediaEntityEducation, "educationID")
+}
+
+// @Summary List Education Media
+// @Description List the media files of an education entry
+// @Tags Media
+// @Security BearerAuth
+// @Produce json
+// @Param educationID path string true "Education ID"
+// @Success 200 {array} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/education/{educationID}/media [get]
+func (h *UserHandler) HandleGetEducationMedia(w http.ResponseWriter, r *http.Request) {
+	h.listMedia(w, r, models.MediaEntityEducation, "educationID")
+}
+
+// @Summary Upload Experience Media
+// @Description Upload a media file for an experience entry. Recruiters see it once a malware scan found it clean, until then its scan_status is pending
+// @Tags Media
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param experienceID path string true "Experience ID"
+// @Param file formData file true "Media file"
+// @Param media_type formData string false "Media type (image, video, document), taken from the content when left out"
+// @Param alt_text formData string false "Alt text for accessibility"
+// @Param description formData string false "Media description"
+// @Success 201 {object} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/experience/{experienceID}/media [post]
+func (h *UserHandler) HandleUploadExperienceMedia(w http.ResponseWriter, r *http.Request) {
+	h.uploadMedia(w, r, models.MediaEntityExperience, "experienceID")
+}
+
+// @Summary List Experience Media
+// @Description List the media files of an experience entry
+// @Tags Media
+// @Security BearerAuth
+// @Produce json
+// @Param experienceID path string true "Experience ID"
+// @Success 200 {array} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/experience/{experienceID}/media [get]
+func (h *UserHandler) HandleGetExperienceMedia(w http.ResponseWriter, r *http.Request) {
+	h.listMedia(w, r, models.MediaEntityExperience, "experienceID")
+}
+
+// @Summary Upload Certification Media
+// @Description Upload a media file for a certification entry. Recruiters see it once a malware scan found it clean, until then its scan_status is pending
+// @Tags Media
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param certificationID path string true "Certification ID"
+// @Param file formData file true "Media file"
+// @Param media_type formData string false "Media type (image, video, document), taken from the content when left out"
+// @Param alt_text formData string false "Alt text for accessibility"
+// @Param description formData string false "Media description"
+// @Success 201 {object} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/certifications/{certificationID}/media [post]
+func (h *UserHandler) HandleUploadCertificationMedia(w http.ResponseWriter, r *http.Request) {
+	h.uploadMedia(w, r, models.MediaEntityCertification, "certificationID")
+}
+
+// @Summary List Certification Media
+// @Description List the media files of a certification entry
+// @Tags Media
+// @Security BearerAuth
+// @Produce json
+// @Param certificationID path string true "Certification ID"
+// @Success 200 {array} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/certifications/{certificationID}/media [get]
+func (h *UserHandler) HandleGetCertificationMedia(w http.ResponseWriter, r *http.Request) {
+	h.listMedia(w, r, models.MediaEntityCertification, "certificationID")
+}
+
+// @Summary Upload Project Media
+// @Description Upload a media file for a project entry. Recruiters see it once a malware scan found it clean, until then its scan_status is pending
+// @Tags Media
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param file formData file true "Media file"
+// @Param media_type formData string false "Media type (image, video, document), taken from the content when left out"
+// @Param alt_text formData string false "Alt text for accessibility"
+// @Param description formData string false "Media description"
+// @Success 201 {object} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/projects/{projectID}/media [post]
+func (h *UserHandler) HandleUploadProjectMedia(w http.ResponseWriter, r *http.Request) {
+	h.uploadMedia(w, r, models.MediaEntityProject, "projectID")
+}
+
+// @Summary List Project Media
+// @Description List the media files of a project entry
+// @Tags Media
+// @Security BearerAuth
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Success 200 {array} models.UserMedia
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/projects/{projectID}/media [get]
+func (h *UserHandler) HandleGetProjectMedia(w http.ResponseWriter, r *http.Request) {
+	h.listMedia(w, r, models.MediaEntityProject, "projectID")
+}
+
+// @Summary Delete Media
+// @Description Delete a media file
+// @Tags Media
+// @Security BearerAuth
+// @Param mediaID path string true "Media ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/profile/media/{mediaID} [delete]
+func (h *UserHandler) HandleDeleteMedia(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mediaID, err := uuid.Parse(chi.URLParam(r, "mediaID"))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid media ID format", http.StatusBadRequest)
+		return
+	}
+
+	media, err := h.userRepo.DeleteMedia(claims.UserID, mediaID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, "Media not found", http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to delete media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.deleteMediaFiles([]models.UserMedia{*media})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) uploadMedia(w http.ResponseWriter, r *http.Request, entity, idParam string) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entityID, err := uuid.Parse(chi.URLParam(r, idParam))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid "+entity+" ID format", http.StatusBadRequest)
+		return
+	}
+
+	// Per media type limits are checked once the content is known, this only bounds the request
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxMediaSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		h.writeErrorResponse(w, fmt.Sprintf("A file of at most %dMB is required", services.MaxMediaSize>>20), status)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.writeErrorResponse(w, "No file provided", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	mediaType := r.FormValue("media_type")
+	if mediaType != "" && mediaType != "image" && mediaType != "video" && mediaType != "document" {
+		h.writeErrorResponse(w, "media_type must be image, video or document", http.StatusBadRequest)
+		return
+	}
+
+	// Type, size and extension all come from the content, not from the client
+	processed, err := services.ProcessMedia(file, header.Size, mediaType)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMediaTooLarge):
+			h.writeErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, services.ErrMediaTypeNotAllowed):
+			h.writeErrorResponse(w, err.Error(), http.StatusUnsupportedMediaType)
+		default:
+			h.writeErrorResponse(w, "Failed to process media: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// The key never contains the client's file name
+	base := "users/" + claims.UserID.String() + "/media/" + uuid.NewString()
+	media := models.UserMedia{
+		MediaType: processed.MediaType,
+		FileName:  filepath.Base(header.Filename),
+		FilePath:  base + processed.Extension,
+		FileSize:  &processed.Size,
+		MimeType:  &processed.MimeType,
+	}
+	if err := h.storage.Save(media.FilePath, processed.Body); err != nil {
+		h.writeErrorResponse(w, "Failed to store media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, t := range processed.Thumbnails {
+		thumbnail := models.MediaThumbnail{
+			Size:     t.Size,
+			FilePath: base + "-" + t.Size + t.Extension,
+			MimeType: t.MimeType,
+			FileSize: int64(len(t.Data)),
+			Width:    t.Width,
+			Height:   t.Height,
+		}
+		media.Thumbnails = append(media.Thumbnails, thumbnail)
+		if err := h.storage.Save(thumbnail.FilePath, bytes.NewReader(t.Data)); err != nil {
+			h.deleteMediaFiles([]models.UserMedia{media})
+			h.writeErrorResponse(w, "Failed to store thumbnail: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if altText := r.FormValue("alt_text"); altText != "" {
+		media.AltText = &altText
+	}
+	if description := r.FormValue("description"); description != "" {
+		media.Description = &description
+	}
+
+	created, err := h.userRepo.CreateMedia(claims.UserID, entity, entityID, media)
+	if err != nil {
+		h.deleteMediaFiles([]models.UserMedia{media})
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to upload media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Recruiters only see the file once the malware scan found it clean
+	h.mediaScanner.Wake()
+
+	signed := []models.UserMedia{*created}
+	h.signMediaURLs(signed)
+	h.writeJSONResponse(w, signed[0], http.StatusCreated)
+}
+
+func (h *UserHandler) listMedia(w http.ResponseWriter, r *http.Request, entity, idParam string) {
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.Claims)
+	if !ok {
+		h.writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entityID, err := uuid.Parse(chi.URLParam(r, idParam))
+	if err != nil {
+		h.writeErrorResponse(w, "Invalid "+entity+" ID format", http.StatusBadRequest)
+		return
+	}
+
+	media, err := h.userRepo.GetMedia(claims.UserID, entity, entityID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeErrorResponse(w, "Failed to get media: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.signMediaURLs(media)
+
+	h.writeJSONResponse(w, media, http.StatusOK)
+}
+
+// deleteMediaFiles removes stored files whose records are gone. A failure only leaves an
+// unreferenced file behind, so it is logged rather than reported.
+func (h *UserHandler) deleteMediaFiles(media []models.UserMedia) {
+	for _, m := range media {
+		keys := []string{m.FilePath}
+		for _, t := range m.Thumbnails {
+			keys = append(keys, t.FilePath)
+		}
+		for _, key := range keys {
+			if err := h.storage.Delete(key); err != nil {
+				log.Printf("failed to delete media file %s: %v", key, err)
+			}
+		}
+	}
+}
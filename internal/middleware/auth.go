@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -30,6 +31,10 @@ func JWTAuth(jwtService *services.JWTService) func(http.Handler) http.Handler {
 
 			claims, err := jwtService.ValidateToken(parts[1])
 			if err != nil {
+				if errors.Is(err, services.ErrAccountInactive) {
+					http.Error(w, "Account is scheduled for deletion, cancel the deletion to sign in again", http.StatusUnauthorized)
+					return
+				}
 				http.Error(w, "Invalid token", http.StatusUnauthorized)
 				return
 			}
@@ -59,3 +64,20 @@ func RequireRole(requiredRole string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// OptionalJWTAuth attaches the caller's claims when a valid bearer token is sent,
+// and lets anonymous requests through untouched. Used by public endpoints that
+// behave slightly differently for signed-in users.
+func OptionalJWTAuth(jwtService *services.JWTService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			parts := strings.Split(r.Header.Get("Authorization"), " ")
+			if len(parts) == 2 && parts[0] == "Bearer" {
+				if claims, err := jwtService.ValidateToken(parts[1]); err == nil {
+					r = r.WithContext(context.WithValue(r.Context(), UserContextKey, claims))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeletedUserName replaces the name of a purged account on the records that outlive it
+const DeletedUserName = "Deleted user"
+
+type AccountDeletion struct {
+	UserID       uuid.UUID `json:"user_id" db:"id"`
+	RequestedAt  time.Time `json:"requested_at" db:"deletion_requested_at"`
+	ScheduledFor time.Time `json:"scheduled_for" db:"deletion_scheduled_for"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HiringFunnel summarises how applications in a scope moved through the pipeline
+type HiringFunnel struct {
+	JobID             *uuid.UUID    `json:"job_id,omitempty"`
+	CompanyID         *uuid.UUID    `json:"company_id,omitempty"`
+	From              *time.Time    `json:"from,omitempty"`
+	To                *time.Time    `json:"to,omitempty"`
+	TotalApplications int           `json:"total_applications"`
+	Stages            []FunnelStage `json:"stages"`
+	TimeToHire        TimeToHire    `json:"time_to_hire"`
+	GeneratedAt       time.Time     `json:"generated_at"`
+}
+
+type FunnelStage struct {
+	Stage string `json:"stage"`
+	// Current is the number of applications sitting in this stage right now
+	Current int `json:"current"`
+	// Reached is the number of applications that got to this stage or further
+	Reached int `json:"reached"`
+	// ConversionRate is the share of applications that reached this stage and moved on to a later one
+	ConversionRate *float64 `json:"conversion_rate,omitempty"`
+	// MedianHoursInStage only counts applications that have already left the stage
+	MedianHoursInStage *float64 `json:"median_hours_in_stage,omitempty"`
+	Rejected           int      `json:"rejected"`
+	Withdrawn          int      `json:"withdrawn"`
+	// DropOffRate is the share of applications that reached this stage and left the pipeline from it
+	DropOffRate *float64 `json:"drop_off_rate,omitempty"`
+}
+
+// TimeToHire covers accepted applications. Hires from before the status history was kept have
+// no date for the acceptance, they are counted but left out of the times.
+type TimeToHire struct {
+	Hires        int      `json:"hires"`
+	MedianHours  *float64 `json:"median_hours,omitempty"`
+	AverageHours *float64 `json:"average_hours,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Application statuses, in the order an application normally moves through them
+const (
+	ApplicationStatusPending   = "pending"
+	ApplicationStatusScreening = "screening"
+	ApplicationStatusInterview = "interview"
+	ApplicationStatusOffer     = "offer"
+	ApplicationStatusAccepted  = "accepted"
+	ApplicationStatusRejected  = "rejected"
+	ApplicationStatusWithdrawn = "withdrawn"
+)
+
+// ApplicationPipeline lists the forward stages of the hiring pipeline in order.
+// Rejected and withdrawn are exits from the pipeline rather than stages of it.
+var ApplicationPipeline = []string{
+	ApplicationStatusPending,
+	ApplicationStatusScreening,
+	ApplicationStatusInterview,
+	ApplicationStatusOffer,
+	ApplicationStatusAccepted,
+}
+
+type ApplicationStatusChange struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	ApplicationID uuid.UUID  `json:"application_id" db:"application_id"`
+	FromStatus    *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus      string     `json:"to_status" db:"to_status"`
+	ChangedAt     *time.Time `json:"changed_at,omitempty" db:"changed_at"` // nil when backfilled
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationSnapshot is the applicant's profile frozen when they applied. The profile's
+// ProfilePicture is the key of the picture submitted, PicturePath that of its copy.
+type ApplicationSnapshot struct {
+	ApplicationID uuid.UUID   `json:"application_id" db:"application_id"`
+	Profile       UserProfile `json:"profile" db:"profile"`
+	PicturePath   *string     `json:"-" db:"picture_path"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+}
+
+// ProfileDiff is what changed on a profile since it was submitted with an application
+type ProfileDiff struct {
+	SubmittedAt time.Time     `json:"submitted_at"`
+	Changed     bool          `json:"changed"`
+	Fields      []FieldChange `json:"fields,omitempty"`
+	Sections    []SectionDiff `json:"sections,omitempty"`
+}
+
+// FieldChange is a field with its submitted and current value
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// SectionDiff lists the entries of a section that were added, removed or edited since submitting
+type SectionDiff struct {
+	Section string        `json:"section"`
+	Added   []any         `json:"added,omitempty"`
+	Removed []any         `json:"removed,omitempty"`
+	Changed []EntryChange `json:"changed,omitempty"`
+}
+
+// EntryChange is an entry that is on both profiles with the fields that differ
+type EntryChange struct {
+	ID     string        `json:"id"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// diffedUserFields are the fields of the user compared between profiles
+var diffedUserFields = []string{"full_name", "title", "location", "about_section"}
+
+// DiffProfiles compares the profile submitted with an application to the current one. Entries
+// are matched by ID; timestamps are ignored and media are compared by file name, so only
+// changes the applicant made show up. The profile picture is compared by its storage key,
+// every upload gets a new one, and reported with both URLs. Both profiles should be read for
+// the same viewer.
+func DiffProfiles(submitted, current UserProfile, submittedAt time.Time) (ProfileDiff, error) {
+	diff := ProfileDiff{SubmittedAt: submittedAt}
+
+	before, err := diffFields(submitted.User)
+	if err != nil {
+		return diff, err
+	}
+	after, err := diffFields(current.User)
+	if err != nil {
+		return diff, err
+	}
+	for _, field := range diffedUserFields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			diff.Fields = append(diff.Fields, FieldChange{Field: field, Before: before[field], After: after[field]})
+		}
+	}
+	// Snapshots from before pictures were copied have a URL but no key, their picture is unknown
+	if submitted.User.ProfilePicture != nil || submitted.User.ProfilePictureURL == nil {
+		if !reflect.DeepEqual(submitted.User.ProfilePicture, current.User.ProfilePicture) {
+			diff.Fields = append(diff.Fields, FieldChange{
+				Field:  ProfileSectionPicture,
+				Before: submitted.User.ProfilePictureURL,
+				After:  current.User.ProfilePictureURL,
+			})
+		}
+	}
+
+	sections := []struct {
+		name               string
+		submitted, current any
+	}{
+		{ProfileSectionPhoneNumbers, submitted.PhoneNumbers, current.PhoneNumbers},
+		{ProfileSectionEducation, submitted.Education, current.Education},
+		{ProfileSectionExperience, submitted.Experience, current.Experience},
+		{ProfileSectionCertifications, submitted.Certifications, current.Certifications},
+		{ProfileSectionProjects, submitted.Projects, current.Projects},
+		{ProfileSectionSkills, submitted.Skills, current.Skills},
+	}
+	for _, section := range sections {
+		sectionDiff, err := diffSection(section.name, section.submitted, section.current)
+		if err != nil {
+			return diff, err
+		}
+		if len(sectionDiff.Added)+len(sectionDiff.Removed)+len(sectionDiff.Changed) > 0 {
+			diff.Sections = append(diff.Sections, sectionDiff)
+		}
+	}
+
+	diff.Changed = len(diff.Fields) > 0 || len(diff.Sections) > 0
+	return diff, nil
+}
+
+func diffSection(name string, submitted, current any) (SectionDiff, error) {
+	diff := SectionDiff{Section: name}
+
+	before, order, err := diffEntries(submitted)
+	if err != nil {
+		return diff, err
+	}
+	after, currentOrder, err := diffEntries(current)
+	if err != nil {
+		return diff, err
+	}
+
+	for _, id := range order {
+		entry, ok := after[id]
+		if !ok {
+			diff.Removed = append(diff.Removed, before[id])
+			continue
+		}
+
+		var fields []string
+		for field := range before[id] {
+			fields = append(fields, field)
+		}
+		for field := range entry {
+			if _, ok := before[id][field]; !ok {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+
+		change := EntryChange{ID: id}
+		for _, field := range fields {
+			if !reflect.DeepEqual(before[id][field], entry[field]) {
+				change.Fields = append(change.Fields, FieldChange{Field: field, Before: before[id][field], After: entry[field]})
+			}
+		}
+		if len(change.Fields) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, id := range currentOrder {
+		if _, ok := before[id]; !ok {
+			diff.Added = append(diff.Added, after[id])
+		}
+	}
+	return diff, nil
+}
+
+// diffEntries turns a list of entries into their JSON fields by entry ID, leaving out the fields
+// that change without the applicant doing anything
+func diffEntries(entries any) (map[string]map[string]any, []string, error) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode entries: %w", err)
+	}
+	var list []map[string]any
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode entries: %w", err)
+	}
+
+	byID := make(map[string]map[string]any, len(list))
+	order := make([]string, 0, len(list))
+	for _, entry := range list {
+		id := fmt.Sprint(entry["id"])
+		delete(entry, "created_at")
+		delete(entry, "updated_at")
+		if media, ok := entry["media"].([]any); ok {
+			names := make([]any, len(media))
+			for i, m := range media {
+				if m, ok := m.(map[string]any); ok {
+					names[i] = m["file_name"]
+				}
+			}
+			entry["media"] = names
+		}
+		byID[id] = entry
+		order = append(order, id)
+	}
+	return byID, order, nil
+}
+
+func diffFields(user User) (map[string]any, error) {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode user: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode user: %w", err)
+	}
+	return fields, nil
+}
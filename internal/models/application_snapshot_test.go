@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	diffEducationID  = uuid.MustParse("00000000-0000-0000-0000-0000000000e1")
+	diffExperienceID = uuid.MustParse("00000000-0000-0000-0000-0000000000e2")
+	diffProjectID    = uuid.MustParse("00000000-0000-0000-0000-0000000000e3")
+)
+
+// submittedProfile builds the same profile on every call, so a test can edit one of two copies
+func submittedProfile() UserProfile {
+	submittedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return UserProfile{
+		User: User{
+			FullName:          "Jane Doe",
+			Title:             strPtr("Backend Engineer"),
+			Location:          strPtr("Cairo"),
+			ProfilePicture:    strPtr("users/1/picture-1.jpg"),
+			ProfilePictureURL: strPtr("/api/v1/users/1/picture"),
+			UpdatedAt:         submittedAt,
+		},
+		Education: []UserEducation{{ID: diffEducationID, InstitutionName: "Cairo University", Degree: "BSc", UpdatedAt: submittedAt}},
+		Experience: []UserExperience{{
+			ID:            diffExperienceID,
+			CompanyName:   "Acme",
+			PositionTitle: "Engineer",
+			Media:         []UserMedia{{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000f1"), FileName: "reference.pdf", FilePath: "users/1/reference.pdf"}},
+			UpdatedAt:     submittedAt,
+		}},
+		Skills: []Skill{{ID: 1, Name: "Go", Proficiency: strPtr("advanced")}},
+	}
+}
+
+// describeDiff lists the changes of a diff as "field <name>", "<section> added <id>",
+// "<section> removed <id>" and "<section> changed <id> <field>"
+func describeDiff(diff ProfileDiff) []string {
+	var changes []string
+	for _, field := range diff.Fields {
+		changes = append(changes, "field "+field.Field)
+	}
+	for _, section := range diff.Sections {
+		for _, entry := range section.Added {
+			changes = append(changes, fmt.Sprintf("%s added %v", section.Section, entry.(map[string]any)["id"]))
+		}
+		for _, entry := range section.Removed {
+			changes = append(changes, fmt.Sprintf("%s removed %v", section.Section, entry.(map[string]any)["id"]))
+		}
+		for _, entry := range section.Changed {
+			for _, field := range entry.Fields {
+				changes = append(changes, fmt.Sprintf("%s changed %s %s", section.Section, entry.ID, field.Field))
+			}
+		}
+	}
+	return changes
+}
+
+func TestDiffProfiles(t *testing.T) {
+	tests := []struct {
+		name            string
+		changeSubmitted func(p *UserProfile)
+		change          func(p *UserProfile)
+		want            []string
+	}{
+		{
+			name:   "unchanged",
+			change: func(p *UserProfile) {},
+		},
+		{
+			name: "timestamps only",
+			change: func(p *UserProfile) {
+				p.User.UpdatedAt = time.Now()
+				p.Experience[0].UpdatedAt = time.Now()
+			},
+		},
+		{
+			name: "file uploaded again under the same name",
+			change: func(p *UserProfile) {
+				p.Experience[0].Media[0].ID = uuid.New()
+				p.Experience[0].Media[0].FilePath = "users/1/reference-2.pdf"
+			},
+		},
+		{
+			name: "user fields",
+			change: func(p *UserProfile) {
+				p.User.Title = strPtr("Staff Engineer")
+				p.User.AboutSection = strPtr("Builds APIs.")
+			},
+			want: []string{"field title", "field about_section"},
+		},
+		{
+			name:   "same picture under another URL",
+			change: func(p *UserProfile) { p.User.ProfilePictureURL = strPtr("/api/v1/applications/1/picture") },
+		},
+		{
+			name: "picture replaced",
+			change: func(p *UserProfile) {
+				p.User.ProfilePicture = strPtr("users/1/picture-2.jpg")
+			},
+			want: []string{"field profile_picture"},
+		},
+		{
+			name:   "picture removed",
+			change: func(p *UserProfile) { p.User.ProfilePicture, p.User.ProfilePictureURL = nil, nil },
+			want:   []string{"field profile_picture"},
+		},
+		{
+			name:            "picture added",
+			changeSubmitted: func(p *UserProfile) { p.User.ProfilePicture, p.User.ProfilePictureURL = nil, nil },
+			change:          func(p *UserProfile) {},
+			want:            []string{"field profile_picture"},
+		},
+		{
+			name:            "snapshot without the picture's key",
+			changeSubmitted: func(p *UserProfile) { p.User.ProfilePicture = nil },
+			change:          func(p *UserProfile) { p.User.ProfilePicture = strPtr("users/1/picture-2.jpg") },
+		},
+		{
+			name: "edited entry",
+			change: func(p *UserProfile) {
+				p.Experience[0].PositionTitle = "Senior Engineer"
+				p.Experience[0].Media[0].FileName = "reference-letter.pdf"
+			},
+			want: []string{
+				"experience changed " + diffExperienceID.String() + " media",
+				"experience changed " + diffExperienceID.String() + " position_title",
+			},
+		},
+		{
+			name: "removed and added entries",
+			change: func(p *UserProfile) {
+				p.Education = nil
+				p.Projects = []UserProject{{ID: diffProjectID, ProjectName: "Compiler"}}
+			},
+			want: []string{
+				"education removed " + diffEducationID.String(),
+				"projects added " + diffProjectID.String(),
+			},
+		},
+		{
+			name: "skills by skill ID",
+			change: func(p *UserProfile) {
+				p.Skills[0].Proficiency = strPtr("expert")
+				p.Skills = append(p.Skills, Skill{ID: 2, Name: "SQL"})
+			},
+			want: []string{"skills added 2", "skills changed 1 proficiency"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted, current := submittedProfile(), submittedProfile()
+			if tt.changeSubmitted != nil {
+				tt.changeSubmitted(&submitted)
+			}
+			tt.change(&current)
+			submittedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			diff, err := DiffProfiles(submitted, current, submittedAt)
+			if err != nil {
+				t.Fatalf("DiffProfiles() error = %v", err)
+			}
+			if got := describeDiff(diff); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffProfiles() changes = %q, want %q", got, tt.want)
+			}
+			if diff.Changed != (len(tt.want) > 0) {
+				t.Errorf("Changed = %v, want %v", diff.Changed, len(tt.want) > 0)
+			}
+			if !diff.SubmittedAt.Equal(submittedAt) {
+				t.Errorf("SubmittedAt = %v, want %v", diff.SubmittedAt, submittedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyUpdate is a short news post on a company's feed
+type CompanyUpdate struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	CompanyID uuid.UUID  `json:"company_id" db:"company_id"`
+	AuthorID  *uuid.UUID `json:"author_id,omitempty" db:"author_id"`
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// FollowedCompany is a company the user follows
+type FollowedCompany struct {
+	Company
+	FollowedAt time.Time `json:"followed_at" db:"followed_at"`
+}
+
+// Notification types
+const (
+	NotificationJobPublished  = "job_published"
+	NotificationCompanyUpdate = "company_update"
+)
+
+type Notification struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Type            string     `json:"type" db:"type"` // 'job_published', 'company_update'
+	CompanyID       *uuid.UUID `json:"company_id,omitempty" db:"company_id"`
+	JobID           *uuid.UUID `json:"job_id,omitempty" db:"job_id"`
+	CompanyUpdateID *uuid.UUID `json:"company_update_id,omitempty" db:"company_update_id"`
+	Message         string     `json:"message" db:"message"`
+	ReadAt          *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EEOReport breaks voluntary self-identification answers down per question.
+// A count is suppressed when the applicants of one job in one month behind it number below
+// MinBucketSize, so no individual can be singled out by comparing reports.
+type EEOReport struct {
+	JobID         *uuid.UUID `json:"job_id,omitempty"`
+	CompanyID     *uuid.UUID `json:"company_id,omitempty"`
+	From          *time.Time `json:"from,omitempty"`
+	To            *time.Time `json:"to,omitempty"`
+	MinBucketSize int        `json:"min_bucket_size"`
+	// Respondents is omitted, along with every question, when fewer than MinBucketSize applicants answered
+	Respondents *int                `json:"respondents,omitempty"`
+	Questions   []EEOQuestionReport `json:"questions"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+type EEOQuestionReport struct {
+	Question string      `json:"question"` // 'gender', 'ethnicity', 'veteran_status', 'disability_status'
+	Buckets  []EEOBucket `json:"buckets"`
+}
+
+type EEOBucket struct {
+	Answer     string `json:"answer"`
+	Applicants *int   `json:"applicants,omitempty"`
+	// Hired counts applicants in the bucket whose application was accepted
+	Hired      *int `json:"hired,omitempty"`
+	Suppressed bool `json:"suppressed"`
+}
+
+// EEOSelfIdentification is one applicant's answers. It is only ever returned to that applicant.
+type EEOSelfIdentification struct {
+	ApplicationID    uuid.UUID `json:"application_id" db:"application_id"`
+	Gender           *string   `json:"gender,omitempty" db:"gender"`
+	Ethnicity        *string   `json:"ethnicity,omitempty" db:"ethnicity"`
+	VeteranStatus    *string   `json:"veteran_status,omitempty" db:"veteran_status"`
+	DisabilityStatus *string   `json:"disability_status,omitempty" db:"disability_status"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
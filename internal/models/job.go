@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job statuses
+const (
+	JobStatusPendingReview    = "pending_review"
+	JobStatusChangesRequested = "changes_requested"
+	JobStatusRejected         = "rejected"
+	JobStatusPublished        = "published"
+)
+
+type Job struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	RecruiterID uuid.UUID  `json:"recruiter_id" db:"recruiter_id"`
+	CompanyID   *uuid.UUID `json:"company_id,omitempty" db:"company_id"`
+	CompanyName *string    `json:"company_name,omitempty" db:"company_name"`
+	Title       string     `json:"title" db:"title"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	Location    *string    `json:"location,omitempty" db:"location"`
+	SalaryRange *string    `json:"salary_range,omitempty" db:"salary_range"`
+	Status      string     `json:"status" db:"status"` // 'pending_review', 'changes_requested', 'rejected', 'published'
+	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Blind hiring hides applicant identity until applications reach BlindUntilStatus
+	BlindHiring      bool   `json:"blind_hiring" db:"blind_hiring"`
+	BlindUntilStatus string `json:"blind_until_status" db:"blind_until_status"` // 'screening', 'interview', 'offer', 'accepted'
+}
+
+// Moderation decisions
+const (
+	ModerationApprove        = "approve"
+	ModerationReject         = "reject"
+	ModerationRequestChanges = "request_changes"
+)
+
+type JobModerationDecision struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	JobID     uuid.UUID  `json:"job_id" db:"job_id"`
+	AdminID   *uuid.UUID `json:"admin_id,omitempty" db:"admin_id"`
+	Decision  string     `json:"decision" db:"decision"` // 'approve', 'reject', 'request_changes'
+	Reason    *string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type ApplicationQuestion struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	JobID         uuid.UUID `json:"job_id" db:"job_id"`
+	Question      string    `json:"question" db:"question"`
+	IsRequired    bool      `json:"is_required" db:"is_required"`
+	QuestionOrder int       `json:"question_order" db:"question_order"`
+}
+
+type Application struct {
+	ID             uuid.UUID           `json:"id" db:"id"`
+	ApplicantID    uuid.UUID           `json:"applicant_id" db:"applicant_id"`
+	JobID          uuid.UUID           `json:"job_id" db:"job_id"`
+	Status         string              `json:"status" db:"status"`
+	ReferralLinkID *uuid.UUID          `json:"referral_link_id,omitempty" db:"referral_link_id"`
+	AppliedAt      time.Time           `json:"applied_at" db:"applied_at"`
+	Answers        []ApplicationAnswer `json:"answers,omitempty"`
+}
+
+type ApplicationAnswer struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	ApplicationID uuid.UUID `json:"application_id" db:"application_id"`
+	QuestionID    uuid.UUID `json:"question_id" db:"question_id"`
+	Answer        *string   `json:"answer,omitempty" db:"answer"`
+}
+
+// Job tracking event types
+const (
+	JobEventView          = "view"
+	JobEventApplyStart    = "apply_start"
+	JobEventApplyComplete = "apply_complete"
+)
+
+// JobEvent is a single tracked interaction with a job posting
+type JobEvent struct {
+	JobID      uuid.UUID
+	EventType  string
+	ViewerKey  string
+	UserID     *uuid.UUID
+	OccurredAt time.Time
+}
+
+type JobActivity struct {
+	JobID          uuid.UUID          `json:"job_id"`
+	Views          int                `json:"views"`
+	ApplyStarts    int                `json:"apply_starts"`
+	ApplyCompletes int                `json:"apply_completes"`
+	Daily          []JobActivityDaily `json:"daily"`
+}
+
+type JobActivityDaily struct {
+	Date           string `json:"date"`
+	Views          int    `json:"views"`
+	ApplyStarts    int    `json:"apply_starts"`
+	ApplyCompletes int    `json:"apply_completes"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "github.com/google/uuid"
+
+// Malware scan states of an uploaded file. Only clean files are shown to anyone but the owner.
+const (
+	MediaScanPending     = "pending"
+	MediaScanClean       = "clean"
+	MediaScanQuarantined = "quarantined" // the scanner found malware
+	MediaScanError       = "error"       // the file could not be scanned, it is retried a few times
+)
+
+// FlaggedMedia is a quarantined file or one that could not be scanned, as admins review it
+type FlaggedMedia struct {
+	UserMedia
+	OwnerEmail   string `json:"owner_email" db:"owner_email"`
+	OwnerName    string `json:"owner_name" db:"owner_name"`
+	ScanAttempts int    `json:"scan_attempts" db:"scan_attempts"`
+}
+
+// MediaScanJob is a file waiting for a malware scan. Generation tells the outcome of a scan
+// apart from that of a rescan requested while it was running.
+type MediaScanJob struct {
+	MediaID       uuid.UUID
+	ApplicationID *uuid.UUID // set for the copy of the file submitted with an application
+	UserID        uuid.UUID
+	FilePath      string
+	Generation    int
+}
+
+// MediaRescanResult reports how many files were queued for another scan
+type MediaRescanResult struct {
+	Queued int64 `json:"queued"`
+}
+
+// WithScannedMediaOnly returns a copy of the profile without the media that has not been
+// found clean, for anyone other than the owner. The original profile is left untouched.
+func (p UserProfile) WithScannedMediaOnly() UserProfile {
+	education := make([]UserEducation, len(p.Education))
+	for i, e := range p.Education {
+		e.Media = cleanMedia(e.Media)
+		education[i] = e
+	}
+	p.Education = education
+
+	experience := make([]UserExperience, len(p.Experience))
+	for i, e := range p.Experience {
+		e.Media = cleanMedia(e.Media)
+		experience[i] = e
+	}
+	p.Experience = experience
+
+	certifications := make([]UserCertification, len(p.Certifications))
+	for i, c := range p.Certifications {
+		c.Media = cleanMedia(c.Media)
+		certifications[i] = c
+	}
+	p.Certifications = certifications
+
+	projects := make([]UserProject, len(p.Projects))
+	for i, pr := range p.Projects {
+		pr.Media = cleanMedia(pr.Media)
+		projects[i] = pr
+	}
+	p.Projects = projects
+
+	return p
+}
+
+func cleanMedia(media []UserMedia) []UserMedia {
+	var clean []UserMedia
+	for _, m := range media {
+		if m.ScanStatus == MediaScanClean {
+			clean = append(clean, m)
+		}
+	}
+	return clean
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// recommendedSkills is how many skills a profile needs for the full skills score
+const recommendedSkills = 5
+
+// ProfileCompleteness scores how much of a profile is filled in, from 0 to 100
+type ProfileCompleteness struct {
+	Score       int                      `json:"score"`
+	Suggestions []CompletenessSuggestion `json:"suggestions,omitempty"` // most valuable first
+}
+
+// CompletenessSuggestion is something missing from a profile and the points adding it is worth
+type CompletenessSuggestion struct {
+	Item    string `json:"item"`
+	Message string `json:"message"`
+	Points  int    `json:"points"`
+}
+
+// completenessItem is one part of the score. earned returns how many of the points the
+// profile has, so items like skills can be partly complete.
+type completenessItem struct {
+	item    string
+	points  int
+	message string
+	earned  func(p UserProfile) int
+}
+
+// completenessItems add up to 100 points. Work history, skills and a summary weigh the most,
+// they are what recruiters read first.
+var completenessItems = []completenessItem{
+	{"experience", 20, "Add at least one work experience entry", func(p UserProfile) int {
+		return pointsIf(len(p.Experience) > 0, 20)
+	}},
+	{"skills", 15, "Add at least 5 skills so recruiters can find you", func(p UserProfile) int {
+		return min(len(p.Skills), recommendedSkills) * 15 / recommendedSkills
+	}},
+	{"about_section", 15, "Write an about section summarizing who you are and what you are looking for", func(p UserProfile) int {
+		return pointsIf(filled(p.User.AboutSection), 15)
+	}},
+	{"primary_phone", 10, "Add a phone number and mark it as primary", func(p UserProfile) int {
+		for _, phone := range p.PhoneNumbers {
+			if phone.IsPrimary {
+				return 10
+			}
+		}
+		return 0
+	}},
+	{"education", 10, "Add your education", func(p UserProfile) int {
+		return pointsIf(len(p.Education) > 0, 10)
+	}},
+	{"title", 10, "Add a headline title, e.g. your current role", func(p UserProfile) int {
+		return pointsIf(filled(p.User.Title), 10)
+	}},
+	{"location", 5, "Add your location", func(p UserProfile) int {
+		return pointsIf(filled(p.User.Location), 5)
+	}},
+	{"profile_picture", 5, "Upload a profile picture", func(p UserProfile) int {
+		// The URL is what a viewer gets, and what a profile snapshot keeps
+		return pointsIf(p.User.ProfilePictureURL != nil, 5)
+	}},
+	{"certifications", 5, "Add a certification you hold", func(p UserProfile) int {
+		return pointsIf(len(p.Certifications) > 0, 5)
+	}},
+	{"projects", 5, "Add a project you worked on", func(p UserProfile) int {
+		return pointsIf(len(p.Projects) > 0, 5)
+	}},
+}
+
+// ScoreCompleteness scores the profile as it was read, so a profile read for a recruiter
+// only gets credit for what the recruiter can see
+func (p UserProfile) ScoreCompleteness() ProfileCompleteness {
+	var completeness ProfileCompleteness
+	for _, item := range completenessItems {
+		earned := item.earned(p)
+		completeness.Score += earned
+		if earned < item.points {
+			completeness.Suggestions = append(completeness.Suggestions, CompletenessSuggestion{
+				Item:    item.item,
+				Message: item.message,
+				Points:  item.points - earned,
+			})
+		}
+	}
+
+	sort.SliceStable(completeness.Suggestions, func(i, j int) bool {
+		return completeness.Suggestions[i].Points > completeness.Suggestions[j].Points
+	})
+	return completeness
+}
+
+func pointsIf(ok bool, points int) int {
+	if ok {
+		return points
+	}
+	return 0
+}
+
+func filled(s *string) bool {
+	return s != nil && strings.TrimSpace(*s) != ""
+}
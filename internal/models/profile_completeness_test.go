@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func completeProfile() UserProfile {
+	return UserProfile{
+		User: User{
+			FullName:          "Jane Doe",
+			Title:             strPtr("Backend Engineer"),
+			Location:          strPtr("Cairo"),
+			AboutSection:      strPtr("Builds APIs."),
+			ProfilePictureURL: strPtr("/api/v1/users/1/picture"),
+		},
+		PhoneNumbers:   []UserPhoneNumber{{PhoneNumber: "+20 100 000 0000"}, {PhoneNumber: "+20 111 111 1111", IsPrimary: true}},
+		Education:      []UserEducation{{ID: uuid.New()}},
+		Experience:     []UserExperience{{ID: uuid.New()}},
+		Certifications: []UserCertification{{ID: uuid.New()}},
+		Projects:       []UserProject{{ID: uuid.New()}},
+		Skills:         []Skill{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}},
+	}
+}
+
+func TestScoreCompleteness(t *testing.T) {
+	tests := []struct {
+		name      string
+		change    func(p *UserProfile)
+		wantScore int
+		wantItems []string // suggestions, most valuable first
+	}{
+		{
+			name:      "complete",
+			change:    func(p *UserProfile) {},
+			wantScore: 100,
+		},
+		{
+			name:      "empty",
+			change:    func(p *UserProfile) { *p = UserProfile{} },
+			wantScore: 0,
+			wantItems: []string{"experience", "skills", "about_section", "primary_phone", "education", "title", "location", "profile_picture", "certifications", "projects"},
+		},
+		{
+			name:      "skills count partly",
+			change:    func(p *UserProfile) { p.Skills = p.Skills[:2] },
+			wantScore: 91,
+			wantItems: []string{"skills"},
+		},
+		{
+			name:      "more skills than recommended",
+			change:    func(p *UserProfile) { p.Skills = append(p.Skills, Skill{ID: 6}, Skill{ID: 7}) },
+			wantScore: 100,
+		},
+		{
+			name:      "phone numbers without a primary one",
+			change:    func(p *UserProfile) { p.PhoneNumbers = p.PhoneNumbers[:1] },
+			wantScore: 90,
+			wantItems: []string{"primary_phone"},
+		},
+		{
+			name: "blank text does not count",
+			change: func(p *UserProfile) {
+				p.User.AboutSection = strPtr("   ")
+				p.User.Title = strPtr("")
+			},
+			wantScore: 75,
+			wantItems: []string{"about_section", "title"},
+		},
+		{
+			name:      "picture hidden from the viewer",
+			change:    func(p *UserProfile) { p.User.ProfilePictureURL = nil },
+			wantScore: 95,
+			wantItems: []string{"profile_picture"},
+		},
+		{
+			name: "missing sections",
+			change: func(p *UserProfile) {
+				p.Experience, p.Projects = nil, nil
+			},
+			wantScore: 75,
+			wantItems: []string{"experience", "projects"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := completeProfile()
+			tt.change(&profile)
+			got := profile.ScoreCompleteness()
+
+			if got.Score != tt.wantScore {
+				t.Errorf("score = %d, want %d", got.Score, tt.wantScore)
+			}
+			if len(got.Suggestions) != len(tt.wantItems) {
+				t.Fatalf("suggestions = %+v, want %v", got.Suggestions, tt.wantItems)
+			}
+			missing := 0
+			for i, suggestion := range got.Suggestions {
+				if suggestion.Item != tt.wantItems[i] {
+					t.Errorf("suggestion %d = %s, want %s", i, suggestion.Item, tt.wantItems[i])
+				}
+				missing += suggestion.Points
+			}
+			if got.Score+missing != 100 {
+				t.Errorf("score %d and suggested points %d do not add up to 100", got.Score, missing)
+			}
+		})
+	}
+}
+
+// Application lists score the profile saved as JSON with the application, it must score the same
+func TestScoreCompletenessOfSnapshot(t *testing.T) {
+	profile := completeProfile()
+	data, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var snapshot UserProfile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got, want := snapshot.ScoreCompleteness().Score, profile.ScoreCompleteness().Score; got != want {
+		t.Errorf("snapshot score = %d, want %d like the profile it was saved from", got, want)
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Profile change actions
+const (
+	ProfileChangeCreate = "create"
+	ProfileChangeUpdate = "update"
+	ProfileChangeDelete = "delete"
+)
+
+// ProfileChange is one change to a profile entry, with the entry's row before and after it.
+// Creates have no before and deletes no after.
+type ProfileChange struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	Section   string          `json:"section" db:"section"`
+	EntryID   string          `json:"entry_id" db:"entry_id"` // skill ID for skills
+	Action    string          `json:"action" db:"action"`
+	Before    json.RawMessage `json:"before,omitempty" db:"before"`
+	After     json.RawMessage `json:"after,omitempty" db:"after"`
+	ChangedAt time.Time       `json:"changed_at" db:"changed_at"`
+}
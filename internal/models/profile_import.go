@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ProfileImportResult describes what an import changes, or would change when it is a preview
+type ProfileImportResult struct {
+	Mode           string            `json:"mode"`
+	Preview        bool              `json:"preview"`
+	Basics         []string          `json:"basics"` // basic profile fields the import sets, e.g. "title"
+	PhoneNumbers   ImportSectionDiff `json:"phone_numbers"`
+	Education      ImportSectionDiff `json:"education"`
+	Experience     ImportSectionDiff `json:"experience"`
+	Certifications ImportSectionDiff `json:"certifications"`
+	Projects       ImportSectionDiff `json:"projects"`
+	Skills         SkillImportDiff   `json:"skills"`
+	Warnings       []string          `json:"warnings,omitempty"`
+	// Files of media attached to removed entries, to be deleted from storage after the import
+	RemovedFileKeys []string `json:"-"`
+}
+
+// ImportSectionDiff lists the entries of one profile section by a short description
+type ImportSectionDiff struct {
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+	Removed   []string `json:"removed"`
+}
+
+type SkillImportDiff struct {
+	Added     []string `json:"added"`
+	Unchanged []string `json:"unchanged"`
+	Removed   []string `json:"removed"`
+	Suggested []string `json:"suggested"` // not in the skills table yet, waiting for an admin
+}
+
+// SkillSuggestion is a skill name users asked for, grouped across users
+type SkillSuggestion struct {
+	Name             string    `json:"name"`
+	Requesters       int       `json:"requesters"`
+	FirstSuggestedAt time.Time `json:"first_suggested_at"`
+}
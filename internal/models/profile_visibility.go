@@ -0,0 +1,149 @@
+package models
+
+import "github.com/google/uuid"
+
+// Who an entry or section of a profile is shown to. The owner always sees everything.
+const (
+	VisibilityPublic     = "public"
+	VisibilityRecruiters = "recruiters"
+	VisibilityPrivate    = "private"
+)
+
+// Sections of a profile whose visibility can be set
+const (
+	ProfileSectionPhoneNumbers   = "phone_numbers"
+	ProfileSectionEducation      = "education"
+	ProfileSectionExperience     = "experience"
+	ProfileSectionCertifications = "certifications"
+	ProfileSectionProjects       = "projects"
+	ProfileSectionSkills         = "skills"
+	ProfileSectionPicture        = "profile_picture" // has no entries, only the section visibility applies
+)
+
+// DefaultSectionVisibility applies to sections the user has not set: contact details
+// are for recruiters, the rest of the profile is public
+var DefaultSectionVisibility = map[string]string{
+	ProfileSectionPhoneNumbers:   VisibilityRecruiters,
+	ProfileSectionEducation:      VisibilityPublic,
+	ProfileSectionExperience:     VisibilityPublic,
+	ProfileSectionCertifications: VisibilityPublic,
+	ProfileSectionProjects:       VisibilityPublic,
+	ProfileSectionSkills:         VisibilityPublic,
+	ProfileSectionPicture:        VisibilityPublic,
+}
+
+// ProfileViewer is who a profile is read for
+type ProfileViewer int
+
+const (
+	ViewerPublic    ProfileViewer = iota // anyone with the public link
+	ViewerRecruiter                      // recruiters, e.g. reviewing an application
+	ViewerOwner                          // the user themselves
+)
+
+// Visibilities lists the visibilities of the entries the viewer may see
+func (v ProfileViewer) Visibilities() []string {
+	switch v {
+	case ViewerOwner:
+		return []string{VisibilityPublic, VisibilityRecruiters, VisibilityPrivate}
+	case ViewerRecruiter:
+		return []string{VisibilityPublic, VisibilityRecruiters}
+	default:
+		return []string{VisibilityPublic}
+	}
+}
+
+// ProfileVisibility is where and to whom a user's profile is shown
+type ProfileVisibility struct {
+	Slug      *string           `json:"slug,omitempty" db:"profile_slug"`
+	Published bool              `json:"published" db:"profile_published"`
+	PublicURL *string           `json:"public_url,omitempty"`
+	Sections  map[string]string `json:"sections"` // every section with the visibility in effect
+}
+
+// PublicProfile is a profile as it is shown at its public link, without the account details.
+// Entries and media leave out the owner's ID, the slug is the only way to the person.
+type PublicProfile struct {
+	Slug              string                `json:"slug"`
+	FullName          string                `json:"full_name"`
+	Title             *string               `json:"title,omitempty"`
+	Location          *string               `json:"location,omitempty"`
+	AboutSection      *string               `json:"about_section,omitempty"`
+	ProfilePictureURL *string               `json:"profile_picture_url,omitempty"`
+	PhoneNumbers      []PublicPhoneNumber   `json:"phone_numbers,omitempty"`
+	Education         []PublicEducation     `json:"education,omitempty"`
+	Experience        []PublicExperience    `json:"experience,omitempty"`
+	Certifications    []PublicCertification `json:"certifications,omitempty"`
+	Projects          []PublicProject       `json:"projects,omitempty"`
+	Skills            []Skill               `json:"skills,omitempty"`
+}
+
+// The public entry types shadow the embedded user_id with a field that is never set, so it is left out
+type (
+	PublicPhoneNumber struct {
+		UserPhoneNumber
+		UserID *uuid.UUID `json:"user_id,omitempty"`
+	}
+	PublicEducation struct {
+		UserEducation
+		UserID *uuid.UUID    `json:"user_id,omitempty"`
+		Media  []PublicMedia `json:"media,omitempty"`
+	}
+	PublicExperience struct {
+		UserExperience
+		UserID *uuid.UUID    `json:"user_id,omitempty"`
+		Media  []PublicMedia `json:"media,omitempty"`
+	}
+	PublicCertification struct {
+		UserCertification
+		UserID *uuid.UUID    `json:"user_id,omitempty"`
+		Media  []PublicMedia `json:"media,omitempty"`
+	}
+	PublicProject struct {
+		UserProject
+		UserID *uuid.UUID    `json:"user_id,omitempty"`
+		Media  []PublicMedia `json:"media,omitempty"`
+	}
+	PublicMedia struct {
+		UserMedia
+		UserID *uuid.UUID `json:"user_id,omitempty"`
+	}
+)
+
+// Public returns the profile as shown at its public link. The profile must already be read for
+// the viewer, this only leaves out the account details and the owner's ID.
+func (p UserProfile) Public(slug string) PublicProfile {
+	public := PublicProfile{
+		Slug:              slug,
+		FullName:          p.User.FullName,
+		Title:             p.User.Title,
+		Location:          p.User.Location,
+		AboutSection:      p.User.AboutSection,
+		ProfilePictureURL: p.User.ProfilePictureURL,
+		Skills:            p.Skills,
+	}
+	for _, phone := range p.PhoneNumbers {
+		public.PhoneNumbers = append(public.PhoneNumbers, PublicPhoneNumber{UserPhoneNumber: phone})
+	}
+	for _, e := range p.Education {
+		public.Education = append(public.Education, PublicEducation{UserEducation: e, Media: publicMedia(e.Media)})
+	}
+	for _, e := range p.Experience {
+		public.Experience = append(public.Experience, PublicExperience{UserExperience: e, Media: publicMedia(e.Media)})
+	}
+	for _, c := range p.Certifications {
+		public.Certifications = append(public.Certifications, PublicCertification{UserCertification: c, Media: publicMedia(c.Media)})
+	}
+	for _, pr := range p.Projects {
+		public.Projects = append(public.Projects, PublicProject{UserProject: pr, Media: publicMedia(pr.Media)})
+	}
+	return public
+}
+
+func publicMedia(media []UserMedia) []PublicMedia {
+	var public []PublicMedia
+	for _, m := range media {
+		public = append(public, PublicMedia{UserMedia: m})
+	}
+	return public
+}
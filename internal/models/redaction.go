@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RedactedValue replaces identifying text hidden from blind reviewers
+const RedactedValue = "[redacted]"
+
+// ApplicationView is an application as a reviewer sees it, with the applicant's profile.
+// When Anonymized is set the applicant's identity is redacted as the view is serialized,
+// so no handler can leak it by forgetting to redact.
+type ApplicationView struct {
+	Application
+	JobTitle           string       `json:"job_title" db:"job_title"`
+	Applicant          *UserProfile `json:"applicant,omitempty"`
+	ProfileSubmittedAt *time.Time   `json:"profile_submitted_at,omitempty"` // set when Applicant is the profile as submitted
+	Anonymized         bool         `json:"anonymized"`
+}
+
+func (v ApplicationView) MarshalJSON() ([]byte, error) {
+	// plain has the same fields without this method, so encoding it does not recurse
+	type plain ApplicationView
+	if !v.Anonymized {
+		return json.Marshal(plain(v))
+	}
+
+	if v.Applicant != nil {
+		anonymized := v.Applicant.Anonymize(CandidateAlias(v.ID))
+		v.Applicant = &anonymized
+	}
+	return json.Marshal(struct {
+		plain
+		// Shadows the embedded applicant_id so it is left out
+		ApplicantID *uuid.UUID `json:"applicant_id,omitempty"`
+	}{plain: plain(v)})
+}
+
+// CandidateAlias is the stable name a blind reviewer sees instead of the applicant's
+func CandidateAlias(applicationID uuid.UUID) string {
+	return "Candidate " + applicationID.String()[:8]
+}
+
+// Anonymize returns a copy of the profile without the details that identify the person:
+// name, email, phone numbers, profile picture, location, title, about section and the
+// institutions they studied at. The original profile is left untouched.
+func (p UserProfile) Anonymize(alias string) UserProfile {
+	p.User.ID = uuid.Nil
+	p.User.FullName = alias
+	p.User.Email = ""
+	p.User.ProfilePicture = nil
+	p.User.ProfilePictureURL = nil
+	// Free text the person wrote about themselves easily gives their name or employer away
+	p.User.Location = nil
+	p.User.Title = nil
+	p.User.AboutSection = nil
+	p.PhoneNumbers = nil
+
+	education := make([]UserEducation, len(p.Education))
+	for i, e := range p.Education {
+		e.UserID = uuid.Nil
+		e.InstitutionName = RedactedValue
+		e.Media = anonymizeMedia(e.Media)
+		education[i] = e
+	}
+	p.Education = education
+
+	// Entries carry the owner's ID, which would let a reviewer look the person up
+	experience := make([]UserExperience, len(p.Experience))
+	for i, e := range p.Experience {
+		e.UserID = uuid.Nil
+		e.Media = anonymizeMedia(e.Media)
+		experience[i] = e
+	}
+	p.Experience = experience
+
+	certifications := make([]UserCertification, len(p.Certifications))
+	for i, c := range p.Certifications {
+		c.UserID = uuid.Nil
+		c.Media = anonymizeMedia(c.Media)
+		certifications[i] = c
+	}
+	p.Certifications = certifications
+
+	projects := make([]UserProject, len(p.Projects))
+	for i, pr := range p.Projects {
+		pr.UserID = uuid.Nil
+		pr.Media = anonymizeMedia(pr.Media)
+		projects[i] = pr
+	}
+	p.Projects = projects
+
+	return p
+}
+
+// anonymizeMedia returns a copy of the media items without the owner's ID
+func anonymizeMedia(media []UserMedia) []UserMedia {
+	if media == nil {
+		return nil
+	}
+	anonymized := make([]UserMedia, len(media))
+	for i, m := range media {
+		m.UserID = uuid.Nil
+		anonymized[i] = m
+	}
+	return anonymized
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func strPtr(s string) *string { return &s }
+
+func identifiableProfile() UserProfile {
+	userID := uuid.New()
+	media := func() []UserMedia {
+		return []UserMedia{{ID: uuid.New(), UserID: userID, FileName: "scan.pdf"}}
+	}
+	return UserProfile{
+		User: User{
+			ID:                userID,
+			Email:             "jane@example.com",
+			FullName:          "Jane Doe",
+			Location:          strPtr("Cairo"),
+			Title:             strPtr("Head of Platform"),
+			AboutSection:      strPtr("I'm Jane, reach me on jane.dev"),
+			ProfilePicture:    strPtr("users/1/picture.jpg"),
+			ProfilePictureURL: strPtr("https://example.com/picture.jpg"),
+		},
+		PhoneNumbers:   []UserPhoneNumber{{ID: uuid.New(), UserID: userID, PhoneNumber: "+20 100 000 0000"}},
+		Education:      []UserEducation{{ID: uuid.New(), UserID: userID, InstitutionName: "Cairo University", Degree: "BSc", Media: media()}},
+		Experience:     []UserExperience{{ID: uuid.New(), UserID: userID, CompanyName: "Acme", Media: media()}},
+		Certifications: []UserCertification{{ID: uuid.New(), UserID: userID, CertificationName: "CKA", Media: media()}},
+		Projects:       []UserProject{{ID: uuid.New(), UserID: userID, ProjectName: "Compiler", Media: media()}},
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	const alias = "Candidate 12345678"
+	original := identifiableProfile()
+	userID := original.User.ID
+	anonymized := original.Anonymize(alias)
+
+	var mediaOwners []uuid.UUID
+	for _, e := range anonymized.Education {
+		for _, m := range e.Media {
+			mediaOwners = append(mediaOwners, m.UserID)
+		}
+	}
+	for _, e := range anonymized.Experience {
+		for _, m := range e.Media {
+			mediaOwners = append(mediaOwners, m.UserID)
+		}
+	}
+	for _, c := range anonymized.Certifications {
+		for _, m := range c.Media {
+			mediaOwners = append(mediaOwners, m.UserID)
+		}
+	}
+	for _, p := range anonymized.Projects {
+		for _, m := range p.Media {
+			mediaOwners = append(mediaOwners, m.UserID)
+		}
+	}
+
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"user ID", anonymized.User.ID == uuid.Nil},
+		{"full name", anonymized.User.FullName == alias},
+		{"email", anonymized.User.Email == ""},
+		{"location", anonymized.User.Location == nil},
+		{"title", anonymized.User.Title == nil},
+		{"about section", anonymized.User.AboutSection == nil},
+		{"profile picture", anonymized.User.ProfilePicture == nil && anonymized.User.ProfilePictureURL == nil},
+		{"phone numbers", anonymized.PhoneNumbers == nil},
+		{"institution", anonymized.Education[0].InstitutionName == RedactedValue},
+		{"education owner", anonymized.Education[0].UserID == uuid.Nil},
+		{"experience owner", anonymized.Experience[0].UserID == uuid.Nil},
+		{"certification owner", anonymized.Certifications[0].UserID == uuid.Nil},
+		{"project owner", anonymized.Projects[0].UserID == uuid.Nil},
+		{"media of every section", len(mediaOwners) == 4},
+		{"degree kept", anonymized.Education[0].Degree == "BSc"},
+		{"company kept", anonymized.Experience[0].CompanyName == "Acme"},
+	}
+	for _, tt := range tests {
+		if !tt.ok {
+			t.Errorf("%s is not anonymized as expected", tt.name)
+		}
+	}
+	for _, owner := range mediaOwners {
+		if owner != uuid.Nil {
+			t.Errorf("media owner = %s, want it cleared", owner)
+		}
+	}
+
+	// The profile the copy was made from still identifies the person
+	if original.User.FullName != "Jane Doe" || original.User.Location == nil || original.User.AboutSection == nil ||
+		original.Education[0].InstitutionName != "Cairo University" || original.Experience[0].Media[0].UserID != userID {
+		t.Errorf("Anonymize() changed the original profile: %+v", original)
+	}
+}
+
+func TestApplicationViewMarshalJSON(t *testing.T) {
+	profile := identifiableProfile()
+	view := ApplicationView{
+		Application: Application{ID: uuid.New(), ApplicantID: profile.User.ID, JobID: uuid.New()},
+		Applicant:   &profile,
+	}
+
+	tests := []struct {
+		name       string
+		anonymized bool
+		leaks      bool
+	}{
+		{"revealed", false, true},
+		{"anonymized", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view.Anonymized = tt.anonymized
+			encoded, err := json.Marshal(view)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			for _, identifying := range []string{
+				profile.User.ID.String(), "Jane", "jane@example.com", "Cairo", "Head of Platform", "jane.dev", "+20 100", "picture.jpg",
+			} {
+				if strings.Contains(string(encoded), identifying) != tt.leaks {
+					t.Errorf("%q in %s, want present %v", identifying, encoded, tt.leaks)
+				}
+			}
+			if tt.anonymized && !strings.Contains(string(encoded), CandidateAlias(view.ID)) {
+				t.Errorf("alias missing from %s", encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReferralLink struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Code       string     `json:"code" db:"code"`
+	JobID      uuid.UUID  `json:"job_id" db:"job_id"`
+	JobTitle   string     `json:"job_title" db:"job_title"`
+	ReferrerID uuid.UUID  `json:"referrer_id" db:"referrer_id"`
+	URL        string     `json:"url"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Referrals  []Referral `json:"referrals,omitempty"`
+}
+
+// Referral is an application attributed to a referral link, as seen by the referrer.
+// ApplicantName is the candidate alias until NameRevealed is set.
+type Referral struct {
+	ApplicationID uuid.UUID `json:"application_id" db:"application_id"`
+	ApplicantName string    `json:"applicant_name" db:"applicant_name"`
+	NameRevealed  bool      `json:"name_revealed"`
+	Status        string    `json:"status" db:"status"`
+	AppliedAt     time.Time `json:"applied_at" db:"applied_at"`
+}
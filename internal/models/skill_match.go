@@ -0,0 +1,128 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Skill proficiency levels, lowest first
+const (
+	ProficiencyBeginner     = "beginner"
+	ProficiencyIntermediate = "intermediate"
+	ProficiencyAdvanced     = "advanced"
+	ProficiencyExpert       = "expert"
+)
+
+var proficiencyRanks = map[string]int{
+	ProficiencyBeginner:     1,
+	ProficiencyIntermediate: 2,
+	ProficiencyAdvanced:     3,
+	ProficiencyExpert:       4,
+}
+
+// SkillRequirement is a skill looked for with the IDs of the skills that count for it: the
+// skill itself and every narrower skill under it, someone who knows React knows JavaScript
+type SkillRequirement struct {
+	Skill  Skill
+	Covers []int
+}
+
+// SkillCriteria is what applicants need to match: every one of the skills, each used at least
+// at the proficiency, for the years and since the year given. Zero values are not checked.
+type SkillCriteria struct {
+	Skills         []SkillRequirement
+	MinProficiency string
+	MinYears       int
+	UsedSince      int
+}
+
+// MatchSkills returns the profile's skills that meet the criteria, one or more for each skill
+// looked for, or false when a skill is missing or does not meet them
+func (p UserProfile) MatchSkills(criteria SkillCriteria, now time.Time) ([]Skill, bool) {
+	var matched []Skill
+	seen := map[int]bool{}
+	for _, requirement := range criteria.Skills {
+		covers := map[int]bool{}
+		for _, id := range requirement.Covers {
+			covers[id] = true
+		}
+
+		found := false
+		for _, skill := range p.Skills {
+			if !covers[skill.ID] || !p.skillMeets(skill, criteria, now) {
+				continue
+			}
+			found = true
+			if !seen[skill.ID] {
+				seen[skill.ID] = true
+				matched = append(matched, skill)
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return matched, true
+}
+
+func (p UserProfile) skillMeets(skill Skill, criteria SkillCriteria, now time.Time) bool {
+	if criteria.MinProficiency != "" {
+		if skill.Proficiency == nil || proficiencyRanks[*skill.Proficiency] < proficiencyRanks[criteria.MinProficiency] {
+			return false
+		}
+	}
+
+	years, lastUsed := p.SkillUsage(skill, now)
+	if years < criteria.MinYears {
+		return false
+	}
+	return criteria.UsedSince == 0 || lastUsed >= criteria.UsedSince
+}
+
+// SkillUsage is how many years the skill was used and the year it was last used. What the
+// user entered wins; otherwise both come from the dates of the experience and project entries
+// the skill is linked to, ongoing ones count up to now. Zero when unknown.
+func (p UserProfile) SkillUsage(skill Skill, now time.Time) (years, lastUsed int) {
+	var first, last time.Time
+	span := func(start, end *time.Time, current bool) {
+		if start == nil {
+			return
+		}
+		until := *start
+		switch {
+		case current:
+			until = now
+		case end != nil:
+			until = *end
+		}
+		if first.IsZero() || start.Before(first) {
+			first = *start
+		}
+		if until.After(last) {
+			last = until
+		}
+	}
+
+	for _, e := range p.Experience {
+		if slices.Contains(skill.ExperienceIDs, e.ID) {
+			span(e.StartDate, e.EndDate, e.IsCurrent)
+		}
+	}
+	for _, project := range p.Projects {
+		if slices.Contains(skill.ProjectIDs, project.ID) {
+			span(project.StartDate, project.EndDate, project.IsOngoing)
+		}
+	}
+
+	if !first.IsZero() {
+		years = int(last.Sub(first).Hours() / 24 / 365.25)
+		lastUsed = last.Year()
+	}
+	if skill.YearsUsed != nil {
+		years = *skill.YearsUsed
+	}
+	if skill.LastUsedYear != nil {
+		lastUsed = *skill.LastUsedYear
+	}
+	return years, lastUsed
+}
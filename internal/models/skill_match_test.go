@@ -0,0 +1,186 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func datePtr(year int, month time.Month) *time.Time {
+	t := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+var (
+	matchExperienceID = uuid.MustParse("00000000-0000-0000-0000-0000000000a1")
+	matchCurrentJobID = uuid.MustParse("00000000-0000-0000-0000-0000000000a2")
+	matchProjectID    = uuid.MustParse("00000000-0000-0000-0000-0000000000a3")
+)
+
+// skilledProfile has an experience entry from 2015 to 2019, a current job since 2022 and a
+// project done in 2020
+func skilledProfile(skills ...Skill) UserProfile {
+	return UserProfile{
+		Experience: []UserExperience{
+			{ID: matchExperienceID, StartDate: datePtr(2015, time.January), EndDate: datePtr(2019, time.January)},
+			{ID: matchCurrentJobID, StartDate: datePtr(2022, time.January), IsCurrent: true},
+		},
+		Projects: []UserProject{
+			{ID: matchProjectID, StartDate: datePtr(2020, time.March), EndDate: datePtr(2020, time.September)},
+		},
+		Skills: skills,
+	}
+}
+
+func TestSkillUsage(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		skill        Skill
+		wantYears    int
+		wantLastUsed int
+	}{
+		{name: "nothing known", skill: Skill{ID: 1}},
+		{
+			name:         "entered by the user",
+			skill:        Skill{ID: 1, YearsUsed: intPtr(3), LastUsedYear: intPtr(2021)},
+			wantYears:    3,
+			wantLastUsed: 2021,
+		},
+		{
+			name:         "from a past experience entry",
+			skill:        Skill{ID: 1, ExperienceIDs: []uuid.UUID{matchExperienceID}},
+			wantYears:    4,
+			wantLastUsed: 2019,
+		},
+		{
+			name:         "current job counts up to now",
+			skill:        Skill{ID: 1, ExperienceIDs: []uuid.UUID{matchCurrentJobID}},
+			wantYears:    4,
+			wantLastUsed: 2026,
+		},
+		{
+			name:         "spans every linked entry",
+			skill:        Skill{ID: 1, ExperienceIDs: []uuid.UUID{matchExperienceID}, ProjectIDs: []uuid.UUID{matchProjectID}},
+			wantYears:    5,
+			wantLastUsed: 2020,
+		},
+		{
+			name:         "entered values win over the entries",
+			skill:        Skill{ID: 1, YearsUsed: intPtr(10), ExperienceIDs: []uuid.UUID{matchCurrentJobID}},
+			wantYears:    10,
+			wantLastUsed: 2026,
+		},
+		{
+			name:  "entry of someone else",
+			skill: Skill{ID: 1, ExperienceIDs: []uuid.UUID{uuid.New()}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			years, lastUsed := skilledProfile(tt.skill).SkillUsage(tt.skill, now)
+			if years != tt.wantYears || lastUsed != tt.wantLastUsed {
+				t.Errorf("SkillUsage() = %d years, last used %d; want %d years, last used %d", years, lastUsed, tt.wantYears, tt.wantLastUsed)
+			}
+		})
+	}
+}
+
+func TestMatchSkills(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// JavaScript (1) covers React (2), Go is 3
+	javaScript := SkillRequirement{Skill: Skill{ID: 1, Name: "JavaScript"}, Covers: []int{1, 2}}
+	golang := SkillRequirement{Skill: Skill{ID: 3, Name: "Go"}, Covers: []int{3}}
+
+	react := Skill{ID: 2, Name: "React", Proficiency: strPtr(ProficiencyAdvanced), ExperienceIDs: []uuid.UUID{matchCurrentJobID}}
+	js := Skill{ID: 1, Name: "JavaScript", Proficiency: strPtr(ProficiencyBeginner), YearsUsed: intPtr(1), LastUsedYear: intPtr(2016)}
+	goSkill := Skill{ID: 3, Name: "Go", ExperienceIDs: []uuid.UUID{matchExperienceID}}
+
+	tests := []struct {
+		name     string
+		skills   []Skill
+		criteria SkillCriteria
+		want     []int // IDs of the matched skills
+		wantOK   bool
+	}{
+		{
+			name:     "no skills looked for",
+			skills:   []Skill{react},
+			criteria: SkillCriteria{},
+			wantOK:   true,
+		},
+		{
+			name:     "narrower skill counts",
+			skills:   []Skill{react},
+			criteria: SkillCriteria{Skills: []SkillRequirement{javaScript}},
+			want:     []int{2},
+			wantOK:   true,
+		},
+		{
+			name:     "every skill covering a requirement is listed once",
+			skills:   []Skill{js, react},
+			criteria: SkillCriteria{Skills: []SkillRequirement{javaScript, javaScript}},
+			want:     []int{1, 2},
+			wantOK:   true,
+		},
+		{
+			name:     "one skill missing",
+			skills:   []Skill{react},
+			criteria: SkillCriteria{Skills: []SkillRequirement{javaScript, golang}},
+		},
+		{
+			name:     "proficiency",
+			skills:   []Skill{js, react},
+			criteria: SkillCriteria{Skills: []SkillRequirement{javaScript}, MinProficiency: ProficiencyIntermediate},
+			want:     []int{2},
+			wantOK:   true,
+		},
+		{
+			name:     "no proficiency given",
+			skills:   []Skill{goSkill},
+			criteria: SkillCriteria{Skills: []SkillRequirement{golang}, MinProficiency: ProficiencyBeginner},
+		},
+		{
+			name:     "years from the linked entries",
+			skills:   []Skill{goSkill},
+			criteria: SkillCriteria{Skills: []SkillRequirement{golang}, MinYears: 4},
+			want:     []int{3},
+			wantOK:   true,
+		},
+		{
+			name:     "too few years",
+			skills:   []Skill{goSkill},
+			criteria: SkillCriteria{Skills: []SkillRequirement{golang}, MinYears: 5},
+		},
+		{
+			name:     "used since",
+			skills:   []Skill{js, react, goSkill},
+			criteria: SkillCriteria{Skills: []SkillRequirement{javaScript}, UsedSince: 2020},
+			want:     []int{2},
+			wantOK:   true,
+		},
+		{
+			name:     "not used recently enough",
+			skills:   []Skill{goSkill},
+			criteria: SkillCriteria{Skills: []SkillRequirement{golang}, UsedSince: 2020},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, ok := skilledProfile(tt.skills...).MatchSkills(tt.criteria, now)
+			if ok != tt.wantOK {
+				t.Fatalf("MatchSkills() ok = %v, want %v", ok, tt.wantOK)
+			}
+			var got []int
+			for _, skill := range matched {
+				got = append(got, skill.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchSkills() matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
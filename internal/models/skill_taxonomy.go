@@ -0,0 +1,59 @@
+package models
+
+// SkillCategory groups related skills, with the skills as a tree when listing the taxonomy
+type SkillCategory struct {
+	ID     int         `json:"id" db:"id"`
+	Name   string      `json:"name" db:"name"`
+	Skills []SkillNode `json:"skills,omitempty"`
+}
+
+// SkillNode is a skill with the narrower skills under it
+type SkillNode struct {
+	Skill
+	Children []SkillNode `json:"children,omitempty"`
+}
+
+// UncategorizedSkills is the name of the group skills without a category are listed under
+const UncategorizedSkills = "Uncategorized"
+
+// BuildSkillTaxonomy arranges skills under their categories, each nested under its parent.
+// A child is listed under its parent even if the two are in different categories. Top level
+// skills without a category end up in a last group with ID 0.
+func BuildSkillTaxonomy(categories []SkillCategory, skills []Skill) []SkillCategory {
+	known := make(map[int]bool, len(skills))
+	for _, skill := range skills {
+		known[skill.ID] = true
+	}
+
+	children := map[int][]Skill{}
+	byCategory := map[int][]Skill{}
+	for _, skill := range skills {
+		switch {
+		case skill.ParentID != nil && known[*skill.ParentID]:
+			children[*skill.ParentID] = append(children[*skill.ParentID], skill)
+		case skill.CategoryID != nil:
+			byCategory[*skill.CategoryID] = append(byCategory[*skill.CategoryID], skill)
+		default:
+			byCategory[0] = append(byCategory[0], skill)
+		}
+	}
+
+	var nest func(skills []Skill) []SkillNode
+	nest = func(skills []Skill) []SkillNode {
+		nodes := make([]SkillNode, len(skills))
+		for i, skill := range skills {
+			nodes[i] = SkillNode{Skill: skill, Children: nest(children[skill.ID])}
+		}
+		return nodes
+	}
+
+	taxonomy := make([]SkillCategory, 0, len(categories)+1)
+	for _, category := range categories {
+		category.Skills = nest(byCategory[category.ID])
+		taxonomy = append(taxonomy, category)
+	}
+	if len(byCategory[0]) > 0 {
+		taxonomy = append(taxonomy, SkillCategory{Name: UncategorizedSkills, Skills: nest(byCategory[0])})
+	}
+	return taxonomy
+}
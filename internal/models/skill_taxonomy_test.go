@@ -0,0 +1,109 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPtr(i int) *int { return &i }
+
+// describeTaxonomy lists each category as "<name>: <skills>", with children in brackets after
+// their parent
+func describeTaxonomy(taxonomy []SkillCategory) []string {
+	var describe func(nodes []SkillNode) string
+	describe = func(nodes []SkillNode) string {
+		s := ""
+		for i, node := range nodes {
+			if i > 0 {
+				s += " "
+			}
+			s += node.Name
+			if len(node.Children) > 0 {
+				s += "[" + describe(node.Children) + "]"
+			}
+		}
+		return s
+	}
+
+	var lines []string
+	for _, category := range taxonomy {
+		lines = append(lines, category.Name+": "+describe(category.Skills))
+	}
+	return lines
+}
+
+func TestBuildSkillTaxonomy(t *testing.T) {
+	categories := []SkillCategory{{ID: 1, Name: "Languages"}, {ID: 2, Name: "Tools"}}
+
+	tests := []struct {
+		name       string
+		categories []SkillCategory
+		skills     []Skill
+		want       []string
+	}{
+		{
+			name:       "empty categories are listed",
+			categories: categories,
+			want:       []string{"Languages: ", "Tools: "},
+		},
+		{
+			name:       "skills under their categories in the order given",
+			categories: categories,
+			skills: []Skill{
+				{ID: 1, Name: "Docker", CategoryID: intPtr(2)},
+				{ID: 2, Name: "Go", CategoryID: intPtr(1)},
+				{ID: 3, Name: "Python", CategoryID: intPtr(1)},
+			},
+			want: []string{"Languages: Go Python", "Tools: Docker"},
+		},
+		{
+			name:       "children nested under their parents",
+			categories: categories,
+			skills: []Skill{
+				{ID: 1, Name: "JavaScript", CategoryID: intPtr(1)},
+				{ID: 2, Name: "React", CategoryID: intPtr(1), ParentID: intPtr(1)},
+				{ID: 3, Name: "Next.js", CategoryID: intPtr(1), ParentID: intPtr(2)},
+				{ID: 4, Name: "Vue", CategoryID: intPtr(1), ParentID: intPtr(1)},
+			},
+			want: []string{"Languages: JavaScript[React[Next.js] Vue]", "Tools: "},
+		},
+		{
+			name:       "child in another category than its parent",
+			categories: categories,
+			skills: []Skill{
+				{ID: 1, Name: "Go", CategoryID: intPtr(1)},
+				{ID: 2, Name: "gofmt", CategoryID: intPtr(2), ParentID: intPtr(1)},
+			},
+			want: []string{"Languages: Go[gofmt]", "Tools: "},
+		},
+		{
+			name:       "uncategorized skills last",
+			categories: categories,
+			skills: []Skill{
+				{ID: 1, Name: "Communication"},
+				{ID: 2, Name: "Go", CategoryID: intPtr(1)},
+				{ID: 3, Name: "Public speaking", ParentID: intPtr(1)},
+			},
+			want: []string{"Languages: Go", "Tools: ", UncategorizedSkills + ": Communication[Public speaking]"},
+		},
+		{
+			name:       "parent left out is top level",
+			categories: categories,
+			skills:     []Skill{{ID: 2, Name: "React", CategoryID: intPtr(1), ParentID: intPtr(99)}},
+			want:       []string{"Languages: React", "Tools: "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describeTaxonomy(BuildSkillTaxonomy(tt.categories, tt.skills))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildSkillTaxonomy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	uncategorized := BuildSkillTaxonomy(nil, []Skill{{ID: 1, Name: "Go"}})
+	if len(uncategorized) != 1 || uncategorized[0].ID != 0 {
+		t.Errorf("uncategorized group = %+v, want one with ID 0", uncategorized)
+	}
+}
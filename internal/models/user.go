@@ -6,30 +6,58 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	FullName     string    `json:"full_name" db:"full_name"`
-	Location     *string   `json:"location,omitempty" db:"location"`
-	Role         string    `json:"role" db:"role"` // 'applicant', 'recruiter', or 'admin'
-	Title        *string   `json:"title,omitempty" db:"title"`
-	AboutSection *string   `json:"about_section,omitempty" db:"about_section"`
-	ProfileURL   *string   `json:"profile_url,omitempty" db:"profile_url"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	Email                string     `json:"email" db:"email"`
+	PasswordHash         string     `json:"-" db:"password_hash"`
+	FullName             string     `json:"full_name" db:"full_name"`
+	Location             *string    `json:"location,omitempty" db:"location"`
+	Role                 string     `json:"role" db:"role"` // 'applicant', 'recruiter', or 'admin'
+	Title                *string    `json:"title,omitempty" db:"title"`
+	AboutSection         *string    `json:"about_section,omitempty" db:"about_section"`
+	ProfilePicture       *string    `json:"-" db:"profile_picture"` // storage key of the square avatar
+	ProfilePictureURL    *string    `json:"profile_picture_url,omitempty"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty" db:"deletion_scheduled_for"`
 }
 
 type Company struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description,omitempty" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID           uuid.UUID         `json:"id" db:"id"`
+	Name         string            `json:"name" db:"name"`
+	Description  *string           `json:"description,omitempty" db:"description"`
+	Website      *string           `json:"website,omitempty" db:"website"`
+	LogoPath     *string           `json:"-" db:"logo_path"`
+	LogoMimeType *string           `json:"-" db:"logo_mime_type"`
+	LogoURL      *string           `json:"logo_url,omitempty"`
+	Industry     *string           `json:"industry,omitempty" db:"industry"`
+	SizeBand     *string           `json:"size_band,omitempty" db:"size_band"` // '1-10', '11-50', '51-200', '201-500', '501-1000', '1001-5000', '5001+'
+	Headquarters *string           `json:"headquarters,omitempty" db:"headquarters"`
+	SocialLinks  map[string]string `json:"social_links,omitempty" db:"social_links"`
+	Offices      []CompanyOffice   `json:"offices,omitempty"`
+	IsTrusted    bool              `json:"is_trusted" db:"is_trusted"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+type CompanyOffice struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	CompanyID uuid.UUID `json:"company_id" db:"company_id"`
+	City      string    `json:"city" db:"city"`
+	Country   *string   `json:"country,omitempty" db:"country"`
+	Address   *string   `json:"address,omitempty" db:"address"`
+}
+
+// CompanyProfile is the public view of a company with its open jobs
+type CompanyProfile struct {
+	Company
+	FollowerCount int   `json:"follower_count"`
+	OpenJobs      []Job `json:"open_jobs"`
 }
 
 type Recruiter struct {
 	User
-	CompanyID *uuid.UUID `json:"company_id,omitempty" db:"company_id"`
+	CompanyID      *uuid.UUID `json:"company_id,omitempty" db:"company_id"`
+	IsCompanyOwner bool       `json:"is_company_owner" db:"is_company_owner"`
 }
 
 type Admin struct {
@@ -43,6 +71,7 @@ type UserPhoneNumber struct {
 	PhoneNumber string    `json:"phone_number" db:"phone_number"`
 	PhoneType   string    `json:"phone_type" db:"phone_type"` // 'mobile', 'home', 'work', 'other'
 	IsPrimary   bool      `json:"is_primary" db:"is_primary"`
+	Visibility  *string   `json:"visibility,omitempty" db:"visibility"` // 'public', 'recruiters', 'private', nil follows the section
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -59,6 +88,7 @@ type UserEducation struct {
 	GradeGPA        *string     `json:"grade_gpa,omitempty" db:"grade_gpa"`
 	Description     *string     `json:"description,omitempty" db:"description"`
 	Media           []UserMedia `json:"media,omitempty"`
+	Visibility      *string     `json:"visibility,omitempty" db:"visibility"`
 	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
 }
@@ -75,6 +105,7 @@ type UserExperience struct {
 	Location       *string     `json:"location,omitempty" db:"location"`
 	Description    *string     `json:"description,omitempty" db:"description"`
 	Media          []UserMedia `json:"media,omitempty"`
+	Visibility     *string     `json:"visibility,omitempty" db:"visibility"`
 	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time   `json:"updated_at" db:"updated_at"`
 }
@@ -90,54 +121,93 @@ type UserCertification struct {
 	CredentialURL       *string     `json:"credential_url,omitempty" db:"credential_url"`
 	Description         *string     `json:"description,omitempty" db:"description"`
 	Media               []UserMedia `json:"media,omitempty"`
+	Visibility          *string     `json:"visibility,omitempty" db:"visibility"`
 	CreatedAt           time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time   `json:"updated_at" db:"updated_at"`
 }
 
 type UserProject struct {
-	ID               uuid.UUID      `json:"id" db:"id"`
-	UserID           uuid.UUID      `json:"user_id" db:"user_id"`
-	ProjectName      string         `json:"project_name" db:"project_name"`
-	Description      *string        `json:"description,omitempty" db:"description"`
-	StartDate        *time.Time     `json:"start_date,omitempty" db:"start_date"`
-	EndDate          *time.Time     `json:"end_date,omitempty" db:"end_date"`
-	IsOngoing        bool           `json:"is_ongoing" db:"is_ongoing"`
-	ProjectURL       *string        `json:"project_url,omitempty" db:"project_url"`
-	Media            []UserMedia    `json:"media,omitempty"`
-	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+	ID          uuid.UUID   `json:"id" db:"id"`
+	UserID      uuid.UUID   `json:"user_id" db:"user_id"`
+	ProjectName string      `json:"project_name" db:"project_name"`
+	Description *string     `json:"description,omitempty" db:"description"`
+	StartDate   *time.Time  `json:"start_date,omitempty" db:"start_date"`
+	EndDate     *time.Time  `json:"end_date,omitempty" db:"end_date"`
+	IsOngoing   bool        `json:"is_ongoing" db:"is_ongoing"`
+	ProjectURL  *string     `json:"project_url,omitempty" db:"project_url"`
+	Media       []UserMedia `json:"media,omitempty"`
+	Visibility  *string     `json:"visibility,omitempty" db:"visibility"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
 }
 
+// Profile entries media can be attached to
+const (
+	MediaEntityEducation     = "education"
+	MediaEntityExperience    = "experience"
+	MediaEntityCertification = "certification"
+	MediaEntityProject       = "project"
+)
+
+
 type UserMedia struct {
-	ID              uuid.UUID   `json:"id" db:"id"`
-	UserID          uuid.UUID   `json:"user_id" db:"user_id"`
-	MediaType       string      `json:"media_type" db:"media_type"` // 'image', 'video', 'document'
-	FileName        string      `json:"file_name" db:"file_name"`
-	FilePath        string      `json:"file_path" db:"file_path"`
-	FileSize        *int64      `json:"file_size,omitempty" db:"file_size"`
-	MimeType        *string     `json:"mime_type,omitempty" db:"mime_type"`
-	AltText         *string     `json:"alt_text,omitempty" db:"alt_text"`
-	Description     *string     `json:"description,omitempty" db:"description"`
-	EducationID     *uuid.UUID  `json:"education_id,omitempty" db:"education_id"`
-	ExperienceID    *uuid.UUID  `json:"experience_id,omitempty" db:"experience_id"`
-	CertificationID *uuid.UUID  `json:"certification_id,omitempty" db:"certification_id"`
-	ProjectID       *uuid.UUID  `json:"project_id,omitempty" db:"project_id"`
-	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID        `json:"id" db:"id"`
+	UserID          uuid.UUID        `json:"user_id" db:"user_id"`
+	MediaType       string           `json:"media_type" db:"media_type"` // 'image', 'video', 'document'
+	FileName        string           `json:"file_name" db:"file_name"`
+	FilePath        string           `json:"-" db:"file_path"` // storage key, files are only served through signed URLs
+	URL             *string          `json:"url,omitempty"`    // signed download URL, valid for a limited time
+	FileSize        *int64           `json:"file_size,omitempty" db:"file_size"`
+	MimeType        *string          `json:"mime_type,omitempty" db:"mime_type"`
+	AltText         *string          `json:"alt_text,omitempty" db:"alt_text"`
+	Description     *string          `json:"description,omitempty" db:"description"`
+	EducationID     *uuid.UUID       `json:"education_id,omitempty" db:"education_id"`
+	ExperienceID    *uuid.UUID       `json:"experience_id,omitempty" db:"experience_id"`
+	CertificationID *uuid.UUID       `json:"certification_id,omitempty" db:"certification_id"`
+	ProjectID       *uuid.UUID       `json:"project_id,omitempty" db:"project_id"`
+	Thumbnails      []MediaThumbnail `json:"thumbnails,omitempty"`
+	ScanStatus      string           `json:"scan_status" db:"scan_status"`           // 'pending', 'clean', 'quarantined', 'error'
+	ScanResult      *string          `json:"scan_result,omitempty" db:"scan_result"` // the malware found, or why the scan failed
+	ScannedAt       *time.Time       `json:"scanned_at,omitempty" db:"scanned_at"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// MediaThumbnail is a scaled down copy of an image, sizes are 'small', 'medium' and 'large'
+type MediaThumbnail struct {
+	Size     string  `json:"size" db:"size"`
+	FilePath string  `json:"-" db:"file_path"`
+	URL      *string `json:"url,omitempty"`
+	MimeType string  `json:"mime_type" db:"mime_type"`
+	FileSize int64   `json:"file_size" db:"file_size"`
+	Width    int     `json:"width" db:"width"`
+	Height   int     `json:"height" db:"height"`
 }
 
 type Skill struct {
-	ID        int    `json:"id" db:"id"`
-	Name      string `json:"name" db:"name"`
+	ID         int      `json:"id" db:"id"`
+	Name       string   `json:"name" db:"name"`
+	CategoryID *int     `json:"category_id,omitempty" db:"category_id"`
+	ParentID   *int     `json:"parent_id,omitempty" db:"parent_id"`
+	Aliases    []string `json:"aliases,omitempty"`
+
+	// Only set on skills of a profile
+	Proficiency   *string     `json:"proficiency,omitempty" db:"proficiency"` // 'beginner', 'intermediate', 'advanced', 'expert'
+	YearsUsed     *int        `json:"years_used,omitempty" db:"years_used"`
+	LastUsedYear  *int        `json:"last_used_year,omitempty" db:"last_used_year"`
+	ExperienceIDs []uuid.UUID `json:"experience_ids,omitempty" db:"experience_ids"`
+	ProjectIDs    []uuid.UUID `json:"project_ids,omitempty" db:"project_ids"`
+	Visibility    *string     `json:"visibility,omitempty" db:"visibility"`
 }
 
 // Composite structs for API responses with related data
 type UserProfile struct {
-	User           User                `json:"user"`
-	PhoneNumbers   []UserPhoneNumber   `json:"phone_numbers,omitempty"`
-	Education      []UserEducation     `json:"education,omitempty"`
-	Experience     []UserExperience    `json:"experience,omitempty"`
-	Certifications []UserCertification `json:"certifications,omitempty"`
-	Projects       []UserProject       `json:"projects,omitempty"`
-	Skills         []Skill             `json:"skills,omitempty"`
+	User           User                 `json:"user"`
+	PhoneNumbers   []UserPhoneNumber    `json:"phone_numbers,omitempty"`
+	Education      []UserEducation      `json:"education,omitempty"`
+	Experience     []UserExperience     `json:"experience,omitempty"`
+	Certifications []UserCertification  `json:"certifications,omitempty"`
+	Projects       []UserProject        `json:"projects,omitempty"`
+	Skills         []Skill              `json:"skills,omitempty"`
+	Completeness   *ProfileCompleteness `json:"completeness,omitempty"`
 }
@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+// AccountRepository handles account deletion. Accounts are never removed from the users
+// table: purging deletes everything about the person and leaves an anonymous row behind
+// so the applications companies rely on for reporting keep pointing somewhere.
+type AccountRepository interface {
+	RequestAccountDeletion(userID uuid.UUID, scheduledFor time.Time) (*models.AccountDeletion, error)
+	CancelAccountDeletion(userID uuid.UUID) error
+	GetAccountsDueForDeletion(limit int) ([]uuid.UUID, error)
+	PurgeAccount(userID uuid.UUID) ([]string, error)
+	IsAccountActive(userID uuid.UUID) (bool, error)
+}
+
+type accountRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+// IsAccountActive reports whether the account exists and is neither scheduled for deletion nor purged
+func (r *accountRepository) IsAccountActive(userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT deletion_scheduled_for IS NULL AND deleted_at IS NULL
+		FROM users
+		WHERE id = $1
+	`
+	var active bool
+	err := r.db.QueryRow(query, userID).Scan(&active)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check account status: %w", err)
+	}
+	return active, nil
+}
+
+// RequestAccountDeletion schedules the account for purging. Asking again keeps the original schedule.
+func (r *accountRepository) RequestAccountDeletion(userID uuid.UUID, scheduledFor time.Time) (*models.AccountDeletion, error) {
+	query := `
+		UPDATE users
+		SET deletion_requested_at = COALESCE(deletion_requested_at, NOW()),
+			deletion_scheduled_for = COALESCE(deletion_scheduled_for, $2),
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, deletion_requested_at, deletion_scheduled_for
+	`
+	var deletion models.AccountDeletion
+	err := r.db.QueryRow(query, userID, scheduledFor).Scan(&deletion.UserID, &deletion.RequestedAt, &deletion.ScheduledFor)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with ID %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to request account deletion: %w", err)
+	}
+	return &deletion, nil
+}
+
+func (r *accountRepository) CancelAccountDeletion(userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET deletion_requested_at = NULL, deletion_scheduled_for = NULL, updated_at = NOW()
+		WHERE id = $1 AND deletion_scheduled_for IS NOT NULL AND deleted_at IS NULL
+	`
+	result, err := r.db.Exec(query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pending deletion for user %s not found", userID)
+	}
+	return nil
+}
+
+// GetAccountsDueForDeletion returns accounts whose grace period is over, longest overdue first
+func (r *accountRepository) GetAccountsDueForDeletion(limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deletion_scheduled_for <= NOW() AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_for
+		LIMIT $1
+	`
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PurgeAccount deletes the person's profile data and anonymizes what companies keep.
+// It returns the storage keys of the user's files, which the caller removes once the
+// purge is committed. An account whose deletion was cancelled in the meantime is left alone.
+func (r *accountRepository) PurgeAccount(userID uuid.UUID) ([]string, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var due bool
+	err = tx.QueryRow(`
+		SELECT deletion_scheduled_for <= NOW() AND deleted_at IS NULL
+		FROM users WHERE id = $1
+		FOR UPDATE
+	`, userID).Scan(&due)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with ID %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+	if !due {
+		return nil, nil
+	}
+
+	keys, err := r.purgedFileKeys(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Profile data and personal activity go entirely. The change log comes after the profile
+	// tables, deleting from those logs more changes.
+	personalTables := []string{
+		"user_media", "user_phone_numbers", "user_education", "user_experience",
+		"user_certifications", "user_projects", "user_skills", "profile_section_visibility", "profile_changes",
+		"company_followers", "notifications", "login_events", "data_exports", "pending_referrals", "skill_suggestions",
+	}
+	for _, table := range personalTables {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE user_id = $1`, userID); err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	// Applications stay for the company's funnel and EEO reporting. Free-text answers can
+	// identify the applicant so they go, and applications still in progress are withdrawn.
+	_, err = tx.Exec(`
+		DELETE FROM application_answers
+		WHERE application_id IN (SELECT id FROM applications WHERE applicant_id = $1)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete application answers: %w", err)
+	}
+
+	// The profile submitted with each application is the person's profile all the same
+	for _, table := range []string{"application_snapshot_media", "application_profile_snapshots"} {
+		_, err = tx.Exec(`
+			DELETE FROM `+table+`
+			WHERE application_id IN (SELECT id FROM applications WHERE applicant_id = $1)
+		`, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE applications
+		SET status = CASE WHEN status IN ('accepted', 'rejected', 'withdrawn') THEN status ELSE 'withdrawn' END,
+			anonymized_at = NOW()
+		WHERE applicant_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize applications: %w", err)
+	}
+
+	// Tracking keeps its counts without pointing at the person
+	if _, err := tx.Exec(`UPDATE job_apply_events SET user_id = NULL WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to anonymize apply events: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE job_views SET viewer_key = $2 WHERE viewer_key = $1`,
+		"user:"+userID.String(), "deleted:"+uuid.New().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize job views: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE company_updates SET author_id = NULL WHERE author_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to anonymize company updates: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE recruiters SET is_company_owner = false WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to update recruiter: %w", err)
+	}
+
+	// The row itself is kept as an anonymous placeholder. An empty password hash never matches.
+	_, err = tx.Exec(`
+		UPDATE users
+		SET email = 'deleted-' || id || '@deleted.invalid',
+			password_hash = '',
+			full_name = $2,
+			location = NULL,
+			title = NULL,
+			about_section = NULL,
+			profile_picture = NULL,
+			profile_slug = NULL,
+			profile_published = false,
+			deleted_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+	`, userID, models.DeletedUserName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return keys, nil
+}
+
+// purgedFileKeys collects the storage keys of everything the user uploaded or had generated
+func (r *accountRepository) purgedFileKeys(tx *sql.Tx, userID uuid.UUID) ([]string, error) {
+	rows, err := tx.Query(`
+		SELECT file_path FROM user_media WHERE user_id = $1
+		UNION ALL
+		SELECT t.file_path FROM user_media_thumbnails t
+		INNER JOIN user_media m ON m.id = t.media_id
+		WHERE m.user_id = $1
+		UNION ALL
+		SELECT file_key FROM data_exports WHERE user_id = $1 AND file_key IS NOT NULL
+		UNION ALL
+		SELECT profile_picture FROM users WHERE id = $1 AND profile_picture IS NOT NULL
+		UNION ALL
+		SELECT m.file_path FROM application_snapshot_media m
+		INNER JOIN applications a ON a.id = m.application_id
+		WHERE a.applicant_id = $1
+		UNION ALL
+		SELECT s.picture_path FROM application_profile_snapshots s
+		INNER JOIN applications a ON a.id = s.application_id
+		WHERE a.applicant_id = $1 AND s.picture_path IS NOT NULL
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user files: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan user file: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -3,6 +3,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
@@ -15,6 +16,9 @@ type AdminRepository interface {
 	CreateCompany(req dto.CreateCompanyRequest) (*models.Company, error)
 	UpdateCompany(id string, req dto.UpdateCompanyRequest) (*models.Company, error)
 	DeleteCompany(id string) error
+	GetCompanyByID(id string) (*models.Company, error)
+	SetCompanyTrusted(id string, trusted bool) (*models.Company, error)
+	SetCompanyLogo(id string, path, mimeType string) (*string, error)
 }
 
 type adminRepository struct {
@@ -78,6 +82,7 @@ func (r *adminRepository) CreateRecruiter(req dto.CreateRecruiterRequest) (*mode
 	recruiter := models.Recruiter{
 		User:     *user,
 		CompanyID: req.CompanyID,
+		IsCompanyOwner: req.IsCompanyOwner,
 	}
 	err = r.createRecruiterTx(tx, recruiter)
 	if err != nil {
@@ -91,6 +96,25 @@ func (r *adminRepository) CreateRecruiter(req dto.CreateRecruiterRequest) (*mode
 	return &recruiter, nil
 }
 
+// companyColumns lists the company columns read by scanCompany
+const companyColumns = `id, name, description, website, logo_path, logo_mime_type, industry, size_band, headquarters, social_links, is_trusted, created_at, updated_at`
+
+func scanCompany(row interface{ Scan(dest ...any) error }) (*models.Company, error) {
+	var company models.Company
+	var socialLinks []byte
+	err := row.Scan(
+		&company.ID, &company.Name, &company.Description, &company.Website, &company.LogoPath, &company.LogoMimeType,
+		&company.Industry, &company.SizeBand, &company.Headquarters, &socialLinks, &company.IsTrusted, &company.CreatedAt, &company.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(socialLinks, &company.SocialLinks); err != nil {
+		return nil, fmt.Errorf("failed to decode social links: %w", err)
+	}
+	return &company, nil
+}
+
 func (r *adminRepository) CreateCompany(req dto.CreateCompanyRequest) (*models.Company, error) {
 	// Start transaction
 	tx, err := r.db.Begin()
@@ -99,22 +123,18 @@ func (r *adminRepository) CreateCompany(req dto.CreateCompanyRequest) (*models.C
 	}
 	defer tx.Rollback()
 	// Create company
-	var company models.Company
 	query := `
 		INSERT INTO companies (name, description)
 		VALUES ($1, $2)
-		RETURNING id, name, description, created_at, updated_at
-	`
-	err = tx.QueryRow(query, req.Name, req.Description).Scan(
-		&company.ID, &company.Name, &company.Description, &company.CreatedAt, &company.UpdatedAt,
-	)
+		RETURNING ` + companyColumns
+	company, err := scanCompany(tx.QueryRow(query, req.Name, req.Description))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create company: %w", err)
 	}
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-	return &company, nil
+	return company, nil
 }
 
 func (r *adminRepository) UpdateCompany(id string, req dto.UpdateCompanyRequest) (*models.Company, error) {
@@ -141,17 +161,29 @@ func (r *adminRepository) UpdateCompany(id string, req dto.UpdateCompanyRequest)
 		return nil, fmt.Errorf("company with ID %s not found", id)
 	}
 
+	// Social links replace the previous set when given
+	var socialLinks *string
+	if req.SocialLinks != nil {
+		encoded, err := json.Marshal(req.SocialLinks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode social links: %w", err)
+		}
+		links := string(encoded)
+		socialLinks = &links
+	}
+
 	// Prepare update query
 	query = `
 		UPDATE companies
-		SET name = COALESCE($1, name), description = COALESCE($2, description), updated_at = NOW()
-		WHERE id = $3
-		RETURNING id, name, description, created_at, updated_at
-	`
-	var company models.Company
-	err = tx.QueryRow(query, req.Name, req.Description, id).Scan(
-		&company.ID, &company.Name, &company.Description, &company.CreatedAt, &company.UpdatedAt,
-	)
+		SET name = COALESCE($1, name), description = COALESCE($2, description),
+			website = COALESCE($3, website), industry = COALESCE($4, industry),
+			size_band = COALESCE($5, size_band), headquarters = COALESCE($6, headquarters),
+			social_links = COALESCE($7::jsonb, social_links), updated_at = NOW()
+		WHERE id = $8
+		RETURNING ` + companyColumns
+	company, err := scanCompany(tx.QueryRow(query,
+		req.Name, req.Description, req.Website, req.Industry, req.SizeBand, req.Headquarters, socialLinks, id,
+	))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("company with ID %s not found", id)
@@ -159,11 +191,32 @@ func (r *adminRepository) UpdateCompany(id string, req dto.UpdateCompanyRequest)
 		return nil, fmt.Errorf("failed to update company: %w", err)
 	}
 
+	// Office locations replace the previous list when given
+	if req.Offices != nil {
+		if _, err = tx.Exec(`DELETE FROM company_offices WHERE company_id = $1`, id); err != nil {
+			return nil, fmt.Errorf("failed to clear company offices: %w", err)
+		}
+		query = `
+			INSERT INTO company_offices (company_id, city, country, address)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''))
+		`
+		for _, office := range *req.Offices {
+			if _, err = tx.Exec(query, id, office.City, office.Country, office.Address); err != nil {
+				return nil, fmt.Errorf("failed to add company office: %w", err)
+			}
+		}
+	}
+
+	company.Offices, err = getCompanyOffices(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &company, nil
+	return company, nil
 }
 
 func (r *adminRepository) DeleteCompany(id string) error {
@@ -204,12 +257,109 @@ func (r *adminRepository) DeleteCompany(id string) error {
 	return nil
 }
 
+func (r *adminRepository) GetCompanyByID(id string) (*models.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE id = $1`
+	company, err := scanCompany(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("company with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	company.Offices, err = getCompanyOffices(r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// SetCompanyTrusted marks whether a company's jobs skip moderation
+func (r *adminRepository) SetCompanyTrusted(id string, trusted bool) (*models.Company, error) {
+	query := `
+		UPDATE companies
+		SET is_trusted = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING ` + companyColumns
+	company, err := scanCompany(r.db.QueryRow(query, trusted, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("company with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to update company: %w", err)
+	}
+	return company, nil
+}
+
+// SetCompanyLogo points the company at a newly stored logo and returns the
+// storage path of the logo it replaced, if any, so the caller can remove it
+func (r *adminRepository) SetCompanyLogo(id string, path, mimeType string) (*string, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous *string
+	err = tx.QueryRow(`SELECT logo_path FROM companies WHERE id = $1 FOR UPDATE`, id).Scan(&previous)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("company with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to get company logo: %w", err)
+	}
+
+	query := `
+		UPDATE companies
+		SET logo_path = $1, logo_mime_type = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+	if _, err = tx.Exec(query, path, mimeType, id); err != nil {
+		return nil, fmt.Errorf("failed to update company logo: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return previous, nil
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func getCompanyOffices(q queryer, companyID string) ([]models.CompanyOffice, error) {
+	query := `
+		SELECT id, company_id, city, country, address
+		FROM company_offices
+		WHERE company_id = $1
+		ORDER BY created_at, city
+	`
+	rows, err := q.Query(query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company offices: %w", err)
+	}
+	defer rows.Close()
+
+	var offices []models.CompanyOffice
+	for rows.Next() {
+		var office models.CompanyOffice
+		if err := rows.Scan(&office.ID, &office.CompanyID, &office.City, &office.Country, &office.Address); err != nil {
+			return nil, fmt.Errorf("failed to scan company office: %w", err)
+		}
+		offices = append(offices, office)
+	}
+	return offices, rows.Err()
+}
+
 func (r *adminRepository) createRecruiterTx(tx *sql.Tx, recruiter models.Recruiter) error {
 	query := `
-		INSERT INTO recruiters (user_id, company_id)
-		VALUES ($1, $2)
+		INSERT INTO recruiters (user_id, company_id, is_company_owner)
+		VALUES ($1, $2, $3)
 	`
-	_, err := tx.Exec(query, recruiter.ID, recruiter.CompanyID)
+	_, err := tx.Exec(query, recruiter.ID, recruiter.CompanyID, recruiter.IsCompanyOwner)
 	return err
 }
 
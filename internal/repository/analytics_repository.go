@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/lib/pq"
+)
+
+type AnalyticsRepository interface {
+	GetHiringFunnel(query dto.FunnelQuery) (*models.HiringFunnel, error)
+}
+
+type analyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// scopedApplications restricts applications to the job, company and applied_at range of a FunnelQuery.
+// It expects the filters as $1..$4 in that order.
+const scopedApplications = `
+	WITH scoped AS (
+		SELECT a.id, a.job_id, a.status, a.applied_at
+		FROM applications a
+		INNER JOIN jobs j ON j.id = a.job_id
+		INNER JOIN recruiters r ON r.user_id = j.recruiter_id
+		WHERE ($1::uuid IS NULL OR a.job_id = $1)
+		  AND ($2::uuid IS NULL OR r.company_id = $2)
+		  AND ($3::timestamptz IS NULL OR a.applied_at >= $3)
+		  AND ($4::timestamptz IS NULL OR a.applied_at < $4)
+	)
+`
+
+func (r *analyticsRepository) GetHiringFunnel(query dto.FunnelQuery) (*models.HiringFunnel, error) {
+	args := []any{query.JobID, query.CompanyID, query.From, query.To}
+
+	funnel := &models.HiringFunnel{
+		JobID:       query.JobID,
+		CompanyID:   query.CompanyID,
+		From:        query.From,
+		To:          query.To,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	stages := make(map[string]*models.FunnelStage, len(models.ApplicationPipeline))
+	for _, name := range models.ApplicationPipeline {
+		funnel.Stages = append(funnel.Stages, models.FunnelStage{Stage: name})
+	}
+	for i := range funnel.Stages {
+		stages[funnel.Stages[i].Stage] = &funnel.Stages[i]
+	}
+
+	// Applications currently in each stage
+	rows, err := r.db.Query(scopedApplications+`
+		SELECT status, COUNT(*) FROM scoped GROUP BY status
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count applications by stage: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stage count: %w", err)
+		}
+		funnel.TotalApplications += count
+		if stage, ok := stages[status]; ok {
+			stage.Current = count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count applications by stage: %w", err)
+	}
+
+	// Furthest pipeline stage each application ever reached
+	rows, err = r.db.Query(scopedApplications+`
+		SELECT furthest, COUNT(*)
+		FROM (
+			SELECT h.application_id, MAX(array_position($5::text[], h.to_status)) AS furthest
+			FROM application_status_history h
+			INNER JOIN scoped s ON s.id = h.application_id
+			GROUP BY h.application_id
+		) f
+		WHERE furthest IS NOT NULL
+		GROUP BY furthest
+	`, append(args, pq.Array(models.ApplicationPipeline))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reached stages: %w", err)
+	}
+	reachedAt := make([]int, len(models.ApplicationPipeline))
+	for rows.Next() {
+		var furthest, count int
+		if err := rows.Scan(&furthest, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan reached stage: %w", err)
+		}
+		// array_position is 1-based
+		if furthest >= 1 && furthest <= len(reachedAt) {
+			reachedAt[furthest-1] = count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count reached stages: %w", err)
+	}
+	reached := 0
+	for i := len(funnel.Stages) - 1; i >= 0; i-- {
+		reached += reachedAt[i]
+		funnel.Stages[i].Reached = reached
+	}
+
+	// Median time spent in each stage before moving on
+	rows, err = r.db.Query(scopedApplications+`
+		SELECT stage, percentile_cont(0.5) WITHIN GROUP (ORDER BY hours)
+		FROM (
+			SELECT h.to_status AS stage,
+				   EXTRACT(EPOCH FROM (LEAD(h.changed_at) OVER (PARTITION BY h.application_id ORDER BY h.changed_at) - h.changed_at)) / 3600 AS hours
+			FROM application_status_history h
+			INNER JOIN scoped s ON s.id = h.application_id
+		) t
+		WHERE hours IS NOT NULL
+		GROUP BY stage
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute time in stage: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var median sql.NullFloat64
+		if err := rows.Scan(&status, &median); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan time in stage: %w", err)
+		}
+		if stage, ok := stages[status]; ok && median.Valid {
+			stage.MedianHoursInStage = &median.Float64
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to compute time in stage: %w", err)
+	}
+
+	// Applications that left the pipeline, by the stage they left from
+	rows, err = r.db.Query(scopedApplications+`
+		SELECT h.from_status, h.to_status, COUNT(DISTINCT h.application_id)
+		FROM application_status_history h
+		INNER JOIN scoped s ON s.id = h.application_id
+		WHERE h.to_status IN ('rejected', 'withdrawn') AND h.from_status IS NOT NULL
+		GROUP BY h.from_status, h.to_status
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count drop-offs: %w", err)
+	}
+	for rows.Next() {
+		var from, to string
+		var count int
+		if err := rows.Scan(&from, &to, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan drop-off: %w", err)
+		}
+		stage, ok := stages[from]
+		if !ok {
+			continue
+		}
+		if to == models.ApplicationStatusRejected {
+			stage.Rejected = count
+		} else {
+			stage.Withdrawn = count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count drop-offs: %w", err)
+	}
+
+	for i := range funnel.Stages {
+		stage := &funnel.Stages[i]
+		if stage.Reached == 0 {
+			continue
+		}
+		if i+1 < len(funnel.Stages) {
+			conversion := float64(funnel.Stages[i+1].Reached) / float64(stage.Reached)
+			stage.ConversionRate = &conversion
+		}
+		dropOff := float64(stage.Rejected+stage.Withdrawn) / float64(stage.Reached)
+		stage.DropOffRate = &dropOff
+	}
+
+	// Time from applying to being accepted
+	var median, average sql.NullFloat64
+	err = r.db.QueryRow(scopedApplications+`
+		SELECT COUNT(*), percentile_cont(0.5) WITHIN GROUP (ORDER BY hours), AVG(hours)
+		FROM (
+			SELECT EXTRACT(EPOCH FROM (MIN(h.changed_at) - s.applied_at)) / 3600 AS hours
+			FROM application_status_history h
+			INNER JOIN scoped s ON s.id = h.application_id
+			WHERE h.to_status = 'accepted'
+			GROUP BY s.id, s.applied_at
+		) t
+	`, args...).Scan(&funnel.TimeToHire.Hires, &median, &average)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute time to hire: %w", err)
+	}
+	if median.Valid {
+		funnel.TimeToHire.MedianHours = &median.Float64
+	}
+	if average.Valid {
+		funnel.TimeToHire.AverageHours = &average.Float64
+	}
+
+	return funnel, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+// SaveApplicationSnapshot stores the profile submitted with an application together with the
+// copies of its picture and files, whose FilePath must point at the copy. The profile's
+// ProfilePicture is kept as the picture submitted. The file copies are queued for a malware
+// scan of their own.
+func (r *jobRepository) SaveApplicationSnapshot(applicationID uuid.UUID, profile models.UserProfile, picturePath *string, files []models.UserMedia) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to encode profile: %w", err)
+	}
+
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO application_profile_snapshots (application_id, profile, picture_path, submitted_picture)
+		VALUES ($1, $2, $3, $4)
+	`, applicationID, string(data), picturePath, profile.User.ProfilePicture)
+	if err != nil {
+		return fmt.Errorf("failed to save profile snapshot: %w", err)
+	}
+
+	for _, file := range files {
+		_, err := tx.Exec(`
+			INSERT INTO application_snapshot_media (application_id, media_id, file_path, file_name, media_type, mime_type, scan_status)
+			VALUES ($1, $2, $3, $4, $5, $6, 'pending')
+		`, applicationID, file.ID, file.FilePath, file.FileName, file.MediaType, file.MimeType)
+		if err != nil {
+			return fmt.Errorf("failed to save snapshot file %s: %w", file.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetApplicationSnapshot returns the profile submitted with the application. Its files carry the
+// scan status of their copies, so one found to be malware since is left out like any other.
+func (r *jobRepository) GetApplicationSnapshot(applicationID uuid.UUID) (*models.ApplicationSnapshot, error) {
+	snapshot := models.ApplicationSnapshot{ApplicationID: applicationID}
+	var data []byte
+	var submittedPicture *string
+	err := r.db.QueryRow(`
+		SELECT profile, picture_path, submitted_picture, created_at
+		FROM application_profile_snapshots
+		WHERE application_id = $1
+	`, applicationID).Scan(&data, &snapshot.PicturePath, &submittedPicture, &snapshot.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile snapshot for application %s not found", applicationID)
+		}
+		return nil, fmt.Errorf("failed to get profile snapshot: %w", err)
+	}
+	if err := json.Unmarshal(data, &snapshot.Profile); err != nil {
+		return nil, fmt.Errorf("failed to decode profile snapshot: %w", err)
+	}
+	snapshot.Profile.User.ProfilePicture = submittedPicture
+
+	rows, err := r.db.Query(`
+		SELECT media_id, scan_status
+		FROM application_snapshot_media
+		WHERE application_id = $1
+	`, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot files: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := map[uuid.UUID]string{}
+	for rows.Next() {
+		var mediaID uuid.UUID
+		var status string
+		if err := rows.Scan(&mediaID, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot file: %w", err)
+		}
+		statuses[mediaID] = status
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate snapshot files: %w", err)
+	}
+
+	setStatus := func(media []models.UserMedia) {
+		for i := range media {
+			media[i].ScanStatus = statuses[media[i].ID]
+		}
+	}
+	for i := range snapshot.Profile.Education {
+		setStatus(snapshot.Profile.Education[i].Media)
+	}
+	for i := range snapshot.Profile.Experience {
+		setStatus(snapshot.Profile.Experience[i].Media)
+	}
+	for i := range snapshot.Profile.Certifications {
+		setStatus(snapshot.Profile.Certifications[i].Media)
+	}
+	for i := range snapshot.Profile.Projects {
+		setStatus(snapshot.Profile.Projects[i].Media)
+	}
+
+	return &snapshot, nil
+}
+
+// GetApplicationSnapshotsByJob returns the profiles submitted with the applications to a job,
+// keyed by application. Files keep the scan status they had when the snapshot was taken, so
+// this is for lists that only score or filter the profiles.
+func (r *jobRepository) GetApplicationSnapshotsByJob(jobID uuid.UUID) (map[uuid.UUID]models.ApplicationSnapshot, error) {
+	rows, err := r.db.Query(`
+		SELECT s.application_id, s.profile, s.created_at
+		FROM application_profile_snapshots s
+		INNER JOIN applications a ON a.id = s.application_id
+		WHERE a.job_id = $1
+	`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := map[uuid.UUID]models.ApplicationSnapshot{}
+	for rows.Next() {
+		var snapshot models.ApplicationSnapshot
+		var data []byte
+		if err := rows.Scan(&snapshot.ApplicationID, &data, &snapshot.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan profile snapshot: %w", err)
+		}
+		if err := json.Unmarshal(data, &snapshot.Profile); err != nil {
+			return nil, fmt.Errorf("failed to decode profile snapshot of application %s: %w", snapshot.ApplicationID, err)
+		}
+		snapshots[snapshot.ApplicationID] = snapshot
+	}
+	return snapshots, rows.Err()
+}
+
+// GetSnapshotMedia returns a file of an application's profile snapshot with the scan status of the copy
+func (r *jobRepository) GetSnapshotMedia(applicationID, mediaID uuid.UUID) (*models.UserMedia, error) {
+	media := models.UserMedia{ID: mediaID}
+	err := r.db.QueryRow(`
+		SELECT file_path, file_name, media_type, mime_type, scan_status, created_at
+		FROM application_snapshot_media
+		WHERE application_id = $1 AND media_id = $2
+	`, applicationID, mediaID).Scan(
+		&media.FilePath, &media.FileName, &media.MediaType, &media.MimeType,
+		&media.ScanStatus, &media.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("snapshot media with ID %s not found", mediaID)
+		}
+		return nil, fmt.Errorf("failed to get snapshot media: %w", err)
+	}
+	media.UpdatedAt = media.CreatedAt
+	return &media, nil
+}
+
+// GetSnapshotPicture returns the copy of the profile picture submitted with an application
+func (r *jobRepository) GetSnapshotPicture(applicationID uuid.UUID) (*models.UserMedia, error) {
+	var media models.UserMedia
+	err := r.db.QueryRow(`
+		SELECT picture_path, created_at
+		FROM application_profile_snapshots
+		WHERE application_id = $1 AND picture_path IS NOT NULL
+	`, applicationID).Scan(&media.FilePath, &media.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("snapshot picture of application %s not found", applicationID)
+		}
+		return nil, fmt.Errorf("failed to get snapshot picture: %w", err)
+	}
+	media.UpdatedAt = media.CreatedAt
+	return &media, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+type CompanyRepository interface {
+	// Followers
+	FollowCompany(userID, companyID uuid.UUID) error
+	UnfollowCompany(userID, companyID uuid.UUID) error
+	GetFollowedCompanies(userID uuid.UUID) ([]models.FollowedCompany, error)
+	GetFollowerCount(companyID uuid.UUID) (int, error)
+
+	// Updates feed
+	CreateCompanyUpdate(companyID, authorID uuid.UUID, req dto.CreateCompanyUpdateRequest) (*models.CompanyUpdate, error)
+	GetCompanyUpdates(companyID uuid.UUID, before *time.Time, limit int) ([]models.CompanyUpdate, error)
+	DeleteCompanyUpdate(companyID, updateID uuid.UUID) error
+}
+
+type companyRepository struct {
+	db *sql.DB
+}
+
+func NewCompanyRepository(db *sql.DB) CompanyRepository {
+	return &companyRepository{db: db}
+}
+
+// FollowCompany starts following a company, following it again is a no-op
+func (r *companyRepository) FollowCompany(userID, companyID uuid.UUID) error {
+	query := `
+		INSERT INTO company_followers (company_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (company_id, user_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(query, companyID, userID); err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			return fmt.Errorf("company with ID %s not found", companyID)
+		}
+		return fmt.Errorf("failed to follow company: %w", err)
+	}
+	return nil
+}
+
+func (r *companyRepository) UnfollowCompany(userID, companyID uuid.UUID) error {
+	query := `DELETE FROM company_followers WHERE company_id = $1 AND user_id = $2`
+	result, err := r.db.Exec(query, companyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow company: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("follow of company %s not found", companyID)
+	}
+	return nil
+}
+
+func (r *companyRepository) GetFollowedCompanies(userID uuid.UUID) ([]models.FollowedCompany, error) {
+	query := `
+		SELECT ` + companyColumns + `, f.followed_at
+		FROM companies
+		INNER JOIN (
+			SELECT company_id, created_at AS followed_at FROM company_followers WHERE user_id = $1
+		) f ON f.company_id = companies.id
+		ORDER BY f.followed_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed companies: %w", err)
+	}
+	defer rows.Close()
+
+	var companies []models.FollowedCompany
+	for rows.Next() {
+		var followedAt time.Time
+		company, err := scanCompany(extraColumns{rows, []any{&followedAt}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies = append(companies, models.FollowedCompany{Company: *company, FollowedAt: followedAt})
+	}
+	return companies, rows.Err()
+}
+
+func (r *companyRepository) GetFollowerCount(companyID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM company_followers WHERE company_id = $1`
+	if err := r.db.QueryRow(query, companyID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count followers: %w", err)
+	}
+	return count, nil
+}
+
+// CreateCompanyUpdate posts to the company's feed, followers are notified by the database
+func (r *companyRepository) CreateCompanyUpdate(companyID, authorID uuid.UUID, req dto.CreateCompanyUpdateRequest) (*models.CompanyUpdate, error) {
+	var update models.CompanyUpdate
+	query := `
+		INSERT INTO company_updates (company_id, author_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, company_id, author_id, body, created_at
+	`
+	err := r.db.QueryRow(query, companyID, authorID, req.Body).Scan(
+		&update.ID, &update.CompanyID, &update.AuthorID, &update.Body, &update.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create company update: %w", err)
+	}
+	return &update, nil
+}
+
+// GetCompanyUpdates returns the newest updates, optionally only those posted before a point in time
+func (r *companyRepository) GetCompanyUpdates(companyID uuid.UUID, before *time.Time, limit int) ([]models.CompanyUpdate, error) {
+	query := `
+		SELECT id, company_id, author_id, body, created_at
+		FROM company_updates
+		WHERE company_id = $1 AND ($2::timestamptz IS NULL OR created_at < $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(query, companyID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company updates: %w", err)
+	}
+	defer rows.Close()
+
+	var updates []models.CompanyUpdate
+	for rows.Next() {
+		var update models.CompanyUpdate
+		if err := rows.Scan(&update.ID, &update.CompanyID, &update.AuthorID, &update.Body, &update.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan company update: %w", err)
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
+func (r *companyRepository) DeleteCompanyUpdate(companyID, updateID uuid.UUID) error {
+	query := `DELETE FROM company_updates WHERE id = $1 AND company_id = $2`
+	result, err := r.db.Exec(query, updateID, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete company update: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("company update with ID %s not found", updateID)
+	}
+	return nil
+}
+
+// extraColumns scans columns selected after the ones a shared scan helper knows about
+type extraColumns struct {
+	row   interface{ Scan(dest ...any) error }
+	extra []any
+}
+
+func (e extraColumns) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+type DataExportRepository interface {
+	CreateDataExport(userID uuid.UUID, staleAfter time.Duration) (*models.DataExport, bool, error)
+	GetDataExport(userID, exportID uuid.UUID) (*models.DataExport, error)
+	GetDataExportByID(exportID uuid.UUID) (*models.DataExport, error)
+	CompleteDataExport(exportID uuid.UUID, fileKey string, fileSize int64, expiresAt time.Time) error
+	FailDataExport(exportID uuid.UUID, reason string) error
+	ExpireDataExports() ([]string, error)
+}
+
+type dataExportRepository struct {
+	db *sql.DB
+}
+
+func NewDataExportRepository(db *sql.DB) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+const selectDataExports = `
+	SELECT id, user_id, status, file_key, file_size, error, created_at, completed_at, expires_at
+	FROM data_exports
+`
+
+func scanDataExport(row interface{ Scan(dest ...any) error }) (*models.DataExport, error) {
+	var export models.DataExport
+	err := row.Scan(
+		&export.ID, &export.UserID, &export.Status, &export.FileKey, &export.FileSize, &export.Error,
+		&export.CreatedAt, &export.CompletedAt, &export.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// CreateDataExport queues a new export for the user. If one is already being generated
+// that export is returned instead and created is false. Exports are generated in memory,
+// so one still pending after staleAfter was lost to a restart and is marked failed.
+func (r *dataExportRepository) CreateDataExport(userID uuid.UUID, staleAfter time.Duration) (*models.DataExport, bool, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialise concurrent requests from the same user
+	if _, err := tx.Exec(`SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, false, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE data_exports
+		SET status = 'failed', error = 'the export was interrupted, please request a new one', completed_at = NOW()
+		WHERE user_id = $1 AND status = 'pending' AND created_at < $2
+	`, userID, time.Now().Add(-staleAfter))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fail stale exports: %w", err)
+	}
+
+	pending, err := scanDataExport(tx.QueryRow(selectDataExports+` WHERE user_id = $1 AND status = 'pending'`, userID))
+	if err == nil {
+		return pending, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to check pending exports: %w", err)
+	}
+
+	export, err := scanDataExport(tx.QueryRow(`
+		INSERT INTO data_exports (user_id)
+		VALUES ($1)
+		RETURNING id, user_id, status, file_key, file_size, error, created_at, completed_at, expires_at
+	`, userID))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return export, true, nil
+}
+
+func (r *dataExportRepository) GetDataExport(userID, exportID uuid.UUID) (*models.DataExport, error) {
+	export, err := scanDataExport(r.db.QueryRow(selectDataExports+` WHERE id = $1 AND user_id = $2`, exportID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("data export with ID %s not found", exportID)
+		}
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return export, nil
+}
+
+func (r *dataExportRepository) GetDataExportByID(exportID uuid.UUID) (*models.DataExport, error) {
+	export, err := scanDataExport(r.db.QueryRow(selectDataExports+` WHERE id = $1`, exportID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("data export with ID %s not found", exportID)
+		}
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return export, nil
+}
+
+func (r *dataExportRepository) CompleteDataExport(exportID uuid.UUID, fileKey string, fileSize int64, expiresAt time.Time) error {
+	query := `
+		UPDATE data_exports
+		SET status = 'ready', file_key = $1, file_size = $2, completed_at = NOW(), expires_at = $3
+		WHERE id = $4
+	`
+	if _, err := r.db.Exec(query, fileKey, fileSize, expiresAt, exportID); err != nil {
+		return fmt.Errorf("failed to complete data export: %w", err)
+	}
+	return nil
+}
+
+func (r *dataExportRepository) FailDataExport(exportID uuid.UUID, reason string) error {
+	query := `
+		UPDATE data_exports
+		SET status = 'failed', error = $1, completed_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := r.db.Exec(query, reason, exportID); err != nil {
+		return fmt.Errorf("failed to record data export failure: %w", err)
+	}
+	return nil
+}
+
+// ExpireDataExports marks ready exports past their expiry as expired and
+// returns the storage keys of their archives so the files can be removed
+func (r *dataExportRepository) ExpireDataExports() ([]string, error) {
+	query := `
+		WITH expired AS (
+			SELECT id, file_key FROM data_exports
+			WHERE status = 'ready' AND expires_at < NOW()
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE data_exports d
+		SET status = 'expired', file_key = NULL
+		FROM expired e
+		WHERE d.id = e.id
+		RETURNING e.file_key
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire data exports: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key sql.NullString
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan expired export: %w", err)
+		}
+		if key.Valid {
+			keys = append(keys, key.String)
+		}
+	}
+	return keys, rows.Err()
+}
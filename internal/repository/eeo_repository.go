@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+// EEORepository is the only code that touches self-identification answers.
+// Apart from handing applicants their own answers, they are only ever read as a k-anonymized report.
+type EEORepository interface {
+	SaveSelfIdentification(applicationID uuid.UUID, req dto.EEOSelfIdentificationRequest) error
+	GetSelfIdentificationsByApplicant(applicantID uuid.UUID) ([]models.EEOSelfIdentification, error)
+	GetEEOReport(query dto.FunnelQuery, minBucketSize int) (*models.EEOReport, error)
+}
+
+type eeoRepository struct {
+	db *sql.DB
+}
+
+func NewEEORepository(db *sql.DB) EEORepository {
+	return &eeoRepository{db: db}
+}
+
+func (r *eeoRepository) SaveSelfIdentification(applicationID uuid.UUID, req dto.EEOSelfIdentificationRequest) error {
+	query := `
+		INSERT INTO eeo_self_identifications (application_id, gender, ethnicity, veteran_status, disability_status)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.db.Exec(query, applicationID, req.Gender, req.Ethnicity, req.VeteranStatus, req.DisabilityStatus)
+	if err != nil {
+		return fmt.Errorf("failed to save self-identification: %w", err)
+	}
+	return nil
+}
+
+// GetSelfIdentificationsByApplicant returns the applicant's own answers, for their personal data export only
+func (r *eeoRepository) GetSelfIdentificationsByApplicant(applicantID uuid.UUID) ([]models.EEOSelfIdentification, error) {
+	query := `
+		SELECT e.application_id, e.gender, e.ethnicity, e.veteran_status, e.disability_status, e.created_at
+		FROM eeo_self_identifications e
+		INNER JOIN applications a ON a.id = e.application_id
+		WHERE a.applicant_id = $1
+		ORDER BY e.created_at DESC
+	`
+	rows, err := r.db.Query(query, applicantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get self-identifications: %w", err)
+	}
+	defer rows.Close()
+
+	var answers []models.EEOSelfIdentification
+	for rows.Next() {
+		var a models.EEOSelfIdentification
+		if err := rows.Scan(&a.ApplicationID, &a.Gender, &a.Ethnicity, &a.VeteranStatus, &a.DisabilityStatus, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan self-identification: %w", err)
+		}
+		answers = append(answers, a)
+	}
+	return answers, rows.Err()
+}
+
+// GetEEOReport counts answers per question for the applications in scope, suppressing small buckets.
+// The date range is widened to whole months, see eeoCells for why.
+func (r *eeoRepository) GetEEOReport(query dto.FunnelQuery, minBucketSize int) (*models.EEOReport, error) {
+	query.From, query.To = wholeMonths(query.From, query.To)
+	args := []any{query.JobID, query.CompanyID, query.From, query.To}
+
+	report := &models.EEOReport{
+		JobID:         query.JobID,
+		CompanyID:     query.CompanyID,
+		From:          query.From,
+		To:            query.To,
+		MinBucketSize: minBucketSize,
+		Questions:     []models.EEOQuestionReport{},
+		GeneratedAt:   time.Now().UTC(),
+	}
+
+	var respondents int
+	err := r.db.QueryRow(scopedApplications+`
+		SELECT COUNT(*)
+		FROM scoped s
+		INNER JOIN eeo_self_identifications e ON e.application_id = s.id
+	`, args...).Scan(&respondents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count respondents: %w", err)
+	}
+
+	// Too few respondents to say anything without risking re-identification
+	if respondents < minBucketSize {
+		return report, nil
+	}
+	report.Respondents = &respondents
+
+	rows, err := r.db.Query(scopedApplications+eeoCells+`
+		SELECT question, answer, SUM(applicants), SUM(hired),
+			   bool_or(applicants < $5),
+			   bool_or(hired BETWEEN 1 AND $5 - 1 OR applicants - hired BETWEEN 1 AND $5 - 1)
+		FROM cells
+		GROUP BY position, question, answer
+		ORDER BY position, answer
+	`, append(args, minBucketSize)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get self-identification counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var question, answer string
+		var applicants, hired int
+		var smallApplicants, smallHired bool
+		if err := rows.Scan(&question, &answer, &applicants, &hired, &smallApplicants, &smallHired); err != nil {
+			return nil, fmt.Errorf("failed to scan self-identification counts: %w", err)
+		}
+		if n := len(report.Questions); n == 0 || report.Questions[n-1].Question != question {
+			report.Questions = append(report.Questions, models.EEOQuestionReport{Question: question})
+		}
+		current := &report.Questions[len(report.Questions)-1]
+
+		bucket := models.EEOBucket{
+			Answer:     answer,
+			Applicants: &applicants,
+			Hired:      &hired,
+		}
+		switch {
+		case smallApplicants:
+			suppress(&bucket)
+		case smallHired:
+			bucket.Hired = nil
+		}
+		current.Buckets = append(current.Buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range report.Questions {
+		suppressComplement(report.Questions[i].Buckets)
+	}
+
+	return report, nil
+}
+
+// eeoCells splits the answers in scope into cells of one answer, job and calendar month.
+// Every report is a sum of whole cells, so suppressing a bucket whenever one of its cells
+// is below k keeps the counts safe whatever filters are combined: the difference of two
+// reports, say a company and one of its jobs or March-April and March, is itself a sum
+// of cells that are either empty or at least k. Hired and not hired are held to the same rule.
+// Must follow scopedApplications.
+const eeoCells = `
+	, cells AS (
+		SELECT q.position, q.question, q.answer,
+			   COUNT(*) AS applicants, COUNT(*) FILTER (WHERE s.status = 'accepted') AS hired
+		FROM scoped s
+		INNER JOIN eeo_self_identifications e ON e.application_id = s.id
+		CROSS JOIN LATERAL (VALUES
+			(1, 'gender', e.gender),
+			(2, 'ethnicity', e.ethnicity),
+			(3, 'veteran_status', e.veteran_status),
+			(4, 'disability_status', e.disability_status)
+		) AS q(position, question, answer)
+		WHERE q.answer IS NOT NULL
+		GROUP BY q.position, q.question, q.answer, s.job_id, date_trunc('month', s.applied_at AT TIME ZONE 'UTC')
+	)
+`
+
+// wholeMonths widens a date range to the calendar months it touches, in UTC
+func wholeMonths(from, to *time.Time) (*time.Time, *time.Time) {
+	if from != nil {
+		start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+		from = &start
+	}
+	if to != nil {
+		end := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if end.Before(*to) {
+			end = end.AddDate(0, 1, 0)
+		}
+		to = &end
+	}
+	return from, to
+}
+
+// suppressComplement hides a second bucket of a question when exactly one is hidden,
+// as it could otherwise be worked out from the respondent total.
+func suppressComplement(buckets []models.EEOBucket) {
+	suppressed := 0
+	for i := range buckets {
+		if buckets[i].Suppressed {
+			suppressed++
+		}
+	}
+
+	if suppressed == 1 {
+		visible := make([]int, 0, len(buckets))
+		for i := range buckets {
+			if !buckets[i].Suppressed {
+				visible = append(visible, i)
+			}
+		}
+		if len(visible) > 0 {
+			sort.Slice(visible, func(a, b int) bool {
+				return *buckets[visible[a]].Applicants < *buckets[visible[b]].Applicants
+			})
+			suppress(&buckets[visible[0]])
+		}
+	}
+}
+
+func suppress(bucket *models.EEOBucket) {
+	bucket.Applicants = nil
+	bucket.Hired = nil
+	bucket.Suppressed = true
+}
@@ -0,0 +1,691 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type JobRepository interface {
+	CreateJob(recruiterID uuid.UUID, req dto.CreateJobRequest, status string) (*models.Job, error)
+	UpdateJob(jobID uuid.UUID, req dto.UpdateJobRequest, status string) (*models.Job, error)
+	GetJobByID(id uuid.UUID) (*models.Job, error)
+	GetJobsByCompany(companyID uuid.UUID) ([]models.Job, error)
+	GetPublishedJobsByCompany(companyID uuid.UUID) ([]models.Job, error)
+	GetJobsByStatus(status string) ([]models.Job, error)
+	GetApplicationQuestions(jobID uuid.UUID) ([]models.ApplicationQuestion, error)
+	SetBlindHiring(jobID uuid.UUID, enabled bool, untilStatus string) (*models.Job, error)
+
+	// Moderation
+	ModerateJob(jobID, adminID uuid.UUID, req dto.ModerateJobRequest) (*models.JobModerationDecision, error)
+	GetModerationHistory(jobID uuid.UUID) ([]models.JobModerationDecision, error)
+
+	// Applications
+	CreateApplication(applicantID, jobID uuid.UUID, req dto.ApplyToJobRequest) (*models.Application, error)
+	GetApplicationsByJob(jobID uuid.UUID) ([]models.ApplicationView, error)
+	GetApplicationByID(applicationID uuid.UUID) (*models.ApplicationView, error)
+	GetApplicationsByApplicant(applicantID uuid.UUID) ([]models.ApplicationView, error)
+	UpdateApplicationStatus(applicationID uuid.UUID, status string) error
+	DeleteApplication(applicationID uuid.UUID) error
+
+	// Profile snapshots
+	SaveApplicationSnapshot(applicationID uuid.UUID, profile models.UserProfile, picturePath *string, files []models.UserMedia) error
+	GetApplicationSnapshot(applicationID uuid.UUID) (*models.ApplicationSnapshot, error)
+	GetApplicationSnapshotsByJob(jobID uuid.UUID) (map[uuid.UUID]models.ApplicationSnapshot, error)
+	GetSnapshotMedia(applicationID, mediaID uuid.UUID) (*models.UserMedia, error)
+	GetSnapshotPicture(applicationID uuid.UUID) (*models.UserMedia, error)
+
+	// Tracking
+	RecordJobEvents(events []models.JobEvent) error
+	GetJobActivity(jobID uuid.UUID, from, to time.Time) (*models.JobActivity, error)
+}
+
+type jobRepository struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// selectJobs selects jobs together with the company of the recruiter who posted them
+const selectJobs = `
+	SELECT j.id, j.recruiter_id, r.company_id, c.name, j.title, j.description,
+		   j.location, j.salary_range, j.status, j.published_at, j.created_at, j.updated_at,
+		   j.blind_hiring, j.blind_until_status
+	FROM jobs j
+	INNER JOIN recruiters r ON r.user_id = j.recruiter_id
+	LEFT JOIN companies c ON c.id = r.company_id
+`
+
+func scanJob(row interface{ Scan(dest ...any) error }) (*models.Job, error) {
+	var job models.Job
+	err := row.Scan(
+		&job.ID, &job.RecruiterID, &job.CompanyID, &job.CompanyName, &job.Title, &job.Description,
+		&job.Location, &job.SalaryRange, &job.Status, &job.PublishedAt, &job.CreatedAt, &job.UpdatedAt,
+		&job.BlindHiring, &job.BlindUntilStatus,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) CreateJob(recruiterID uuid.UUID, req dto.CreateJobRequest, status string) (*models.Job, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var jobID uuid.UUID
+	query := `
+		INSERT INTO jobs (recruiter_id, title, description, location, salary_range, status, published_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6,
+			CASE WHEN $6 = 'published' THEN now() END)
+		RETURNING id
+	`
+	err = tx.QueryRow(query, recruiterID, req.Title, req.Description, req.Location, req.SalaryRange, status).Scan(&jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	query = `
+		INSERT INTO application_questions (job_id, question, is_required, question_order)
+		VALUES ($1, $2, $3, $4)
+	`
+	for i, q := range req.Questions {
+		if _, err := tx.Exec(query, jobID, q.Question, q.IsRequired, i); err != nil {
+			return nil, fmt.Errorf("failed to create application question: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetJobByID(jobID)
+}
+
+func (r *jobRepository) UpdateJob(jobID uuid.UUID, req dto.UpdateJobRequest, status string) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET title = $1, description = NULLIF($2, ''), location = NULLIF($3, ''), salary_range = NULLIF($4, ''),
+			status = $5,
+			published_at = CASE WHEN $5 = 'published' THEN COALESCE(published_at, now()) ELSE published_at END,
+			updated_at = NOW()
+		WHERE id = $6
+	`
+	result, err := r.db.Exec(query, req.Title, req.Description, req.Location, req.SalaryRange, status, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("job with ID %s not found", jobID)
+	}
+
+	return r.GetJobByID(jobID)
+}
+
+func (r *jobRepository) GetJobByID(id uuid.UUID) (*models.Job, error) {
+	job, err := scanJob(r.db.QueryRow(selectJobs+` WHERE j.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("job with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *jobRepository) GetJobsByCompany(companyID uuid.UUID) ([]models.Job, error) {
+	return r.queryJobs(selectJobs+` WHERE r.company_id = $1 ORDER BY j.created_at DESC`, companyID)
+}
+
+// GetPublishedJobsByCompany lists the company's live jobs, newest first
+func (r *jobRepository) GetPublishedJobsByCompany(companyID uuid.UUID) ([]models.Job, error) {
+	return r.queryJobs(selectJobs+` WHERE r.company_id = $1 AND j.status = $2 ORDER BY j.published_at DESC`, companyID, models.JobStatusPublished)
+}
+
+// GetJobsByStatus lists jobs in a status, oldest first so the moderation queue is worked in order
+func (r *jobRepository) GetJobsByStatus(status string) ([]models.Job, error) {
+	return r.queryJobs(selectJobs+` WHERE j.status = $1 ORDER BY j.updated_at ASC`, status)
+}
+
+func (r *jobRepository) queryJobs(query string, args ...any) ([]models.Job, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *jobRepository) GetApplicationQuestions(jobID uuid.UUID) ([]models.ApplicationQuestion, error) {
+	query := `
+		SELECT id, job_id, question, is_required, question_order
+		FROM application_questions
+		WHERE job_id = $1
+		ORDER BY question_order ASC
+	`
+	rows, err := r.db.Query(query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []models.ApplicationQuestion
+	for rows.Next() {
+		var q models.ApplicationQuestion
+		if err := rows.Scan(&q.ID, &q.JobID, &q.Question, &q.IsRequired, &q.QuestionOrder); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+
+	return questions, rows.Err()
+}
+
+func (r *jobRepository) CreateApplication(applicantID, jobID uuid.UUID, req dto.ApplyToJobRequest) (*models.Application, error) {
+	questions, err := r.GetApplicationQuestions(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application questions: %w", err)
+	}
+
+	answers := make(map[uuid.UUID]string, len(req.Answers))
+	for _, answer := range req.Answers {
+		answers[answer.QuestionID] = answer.Answer
+	}
+	known := make(map[uuid.UUID]bool, len(questions))
+	for _, q := range questions {
+		known[q.ID] = true
+		if q.IsRequired && strings.TrimSpace(answers[q.ID]) == "" {
+			return nil, fmt.Errorf("an answer is required for question %s", q.ID)
+		}
+	}
+	for questionID := range answers {
+		if !known[questionID] {
+			return nil, fmt.Errorf("question %s not found for this job", questionID)
+		}
+	}
+
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Attribute the application to the referral code it came with, or failing that to a
+	// link for this job the applicant followed before signing up. Self-referrals never count.
+	var application models.Application
+	query := `
+		INSERT INTO applications (applicant_id, job_id, share_name_with_referrer, referral_link_id)
+		VALUES ($1, $2, $4, COALESCE(
+			(SELECT id FROM referral_links
+			 WHERE code = NULLIF($3, '') AND job_id = $2 AND referrer_id <> $1),
+			(SELECT l.id FROM pending_referrals p
+			 INNER JOIN referral_links l ON l.id = p.referral_link_id
+			 WHERE p.user_id = $1 AND l.job_id = $2 AND l.referrer_id <> $1
+			 ORDER BY p.created_at DESC
+			 LIMIT 1)
+		))
+		RETURNING id, applicant_id, job_id, status, referral_link_id, applied_at
+	`
+	err = tx.QueryRow(query, applicantID, jobID, req.ReferralCode, req.ShareNameWithReferrer).Scan(
+		&application.ID, &application.ApplicantID, &application.JobID, &application.Status,
+		&application.ReferralLinkID, &application.AppliedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application: %w", err)
+	}
+
+	query = `
+		INSERT INTO application_answers (application_id, question_id, answer)
+		VALUES ($1, $2, $3)
+		RETURNING id, application_id, question_id, answer
+	`
+	for _, a := range req.Answers {
+		var answer models.ApplicationAnswer
+		err = tx.QueryRow(query, application.ID, a.QuestionID, a.Answer).Scan(
+			&answer.ID, &answer.ApplicationID, &answer.QuestionID, &answer.Answer,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save answer for question %s: %w", a.QuestionID, err)
+		}
+		application.Answers = append(application.Answers, answer)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &application, nil
+}
+
+// ModerateJob records an admin decision on a job waiting for review and moves the job to the matching status
+func (r *jobRepository) ModerateJob(jobID, adminID uuid.UUID, req dto.ModerateJobRequest) (*models.JobModerationDecision, error) {
+	status := map[string]string{
+		models.ModerationApprove:        models.JobStatusPublished,
+		models.ModerationReject:         models.JobStatusRejected,
+		models.ModerationRequestChanges: models.JobStatusChangesRequested,
+	}[req.Decision]
+	if status == "" {
+		return nil, fmt.Errorf("unknown moderation decision %q", req.Decision)
+	}
+
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the job so two admins cannot decide on it at the same time
+	var current string
+	err = tx.QueryRow(`SELECT status FROM jobs WHERE id = $1 FOR UPDATE`, jobID).Scan(&current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("job with ID %s not found", jobID)
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if current != models.JobStatusPendingReview {
+		return nil, fmt.Errorf("job is %s, only jobs pending review can be moderated", current)
+	}
+
+	query := `
+		UPDATE jobs
+		SET status = $1,
+			published_at = CASE WHEN $1 = 'published' THEN COALESCE(published_at, now()) ELSE published_at END,
+			updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.Exec(query, status, jobID); err != nil {
+		return nil, fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	var decision models.JobModerationDecision
+	query = `
+		INSERT INTO job_moderation_decisions (job_id, admin_id, decision, reason)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id, job_id, admin_id, decision, reason, created_at
+	`
+	err = tx.QueryRow(query, jobID, adminID, req.Decision, req.Reason).Scan(
+		&decision.ID, &decision.JobID, &decision.AdminID, &decision.Decision, &decision.Reason, &decision.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record moderation decision: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &decision, nil
+}
+
+func (r *jobRepository) GetModerationHistory(jobID uuid.UUID) ([]models.JobModerationDecision, error) {
+	query := `
+		SELECT id, job_id, admin_id, decision, reason, created_at
+		FROM job_moderation_decisions
+		WHERE job_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decisions []models.JobModerationDecision
+	for rows.Next() {
+		var d models.JobModerationDecision
+		if err := rows.Scan(&d.ID, &d.JobID, &d.AdminID, &d.Decision, &d.Reason, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, d)
+	}
+
+	return decisions, rows.Err()
+}
+
+// SetBlindHiring turns blind hiring on or off for a job and sets the stage at which applicants are revealed
+func (r *jobRepository) SetBlindHiring(jobID uuid.UUID, enabled bool, untilStatus string) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET blind_hiring = $1, blind_until_status = COALESCE(NULLIF($2, ''), blind_until_status), updated_at = NOW()
+		WHERE id = $3
+	`
+	result, err := r.db.Exec(query, enabled, untilStatus, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update blind hiring: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("job with ID %s not found", jobID)
+	}
+
+	return r.GetJobByID(jobID)
+}
+
+// selectApplicationViews selects applications as reviewers see them. An application to a
+// blind hiring job stays anonymized until it has at some point reached the job's reveal
+// stage, so rejecting a candidate after the reveal does not hide them again. Applications
+// of deleted accounts are always anonymized.
+// $1 must be the application pipeline.
+const selectApplicationViews = `
+	SELECT a.id, a.applicant_id, a.job_id, a.status, a.referral_link_id, a.applied_at, j.title,
+		   a.anonymized_at IS NOT NULL OR (j.blind_hiring AND NOT EXISTS (
+			   SELECT 1 FROM application_status_history h
+			   WHERE h.application_id = a.id
+				 AND array_position($1::text[], h.to_status) >= array_position($1::text[], j.blind_until_status)
+		   ))
+	FROM applications a
+	INNER JOIN jobs j ON j.id = a.job_id
+`
+
+func scanApplicationView(row interface{ Scan(dest ...any) error }) (*models.ApplicationView, error) {
+	var view models.ApplicationView
+	err := row.Scan(
+		&view.ID, &view.ApplicantID, &view.JobID, &view.Status, &view.ReferralLinkID, &view.AppliedAt,
+		&view.JobTitle, &view.Anonymized,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *jobRepository) GetApplicationsByJob(jobID uuid.UUID) ([]models.ApplicationView, error) {
+	rows, err := r.db.Query(selectApplicationViews+` WHERE a.job_id = $2 ORDER BY a.applied_at ASC`,
+		pq.Array(models.ApplicationPipeline), jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applications: %w", err)
+	}
+	defer rows.Close()
+
+	var views []models.ApplicationView
+	for rows.Next() {
+		view, err := scanApplicationView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		views = append(views, *view)
+	}
+
+	return views, rows.Err()
+}
+
+// GetApplicationByID returns the application with its answers
+func (r *jobRepository) GetApplicationByID(applicationID uuid.UUID) (*models.ApplicationView, error) {
+	view, err := scanApplicationView(r.db.QueryRow(selectApplicationViews+` WHERE a.id = $2`,
+		pq.Array(models.ApplicationPipeline), applicationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("application with ID %s not found", applicationID)
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	query := `
+		SELECT id, application_id, question_id, answer
+		FROM application_answers
+		WHERE application_id = $1
+	`
+	rows, err := r.db.Query(query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application answers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var answer models.ApplicationAnswer
+		if err := rows.Scan(&answer.ID, &answer.ApplicationID, &answer.QuestionID, &answer.Answer); err != nil {
+			return nil, fmt.Errorf("failed to scan application answer: %w", err)
+		}
+		view.Answers = append(view.Answers, answer)
+	}
+
+	return view, rows.Err()
+}
+
+// GetApplicationsByApplicant returns everything the applicant has applied to, with their answers
+func (r *jobRepository) GetApplicationsByApplicant(applicantID uuid.UUID) ([]models.ApplicationView, error) {
+	rows, err := r.db.Query(selectApplicationViews+` WHERE a.applicant_id = $2 ORDER BY a.applied_at DESC`,
+		pq.Array(models.ApplicationPipeline), applicantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applications: %w", err)
+	}
+	defer rows.Close()
+
+	views := []models.ApplicationView{}
+	var ids []uuid.UUID
+	byID := map[uuid.UUID]int{}
+	for rows.Next() {
+		view, err := scanApplicationView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		// The applicant is never anonymized from themselves
+		view.Anonymized = false
+		byID[view.ID] = len(views)
+		ids = append(ids, view.ID)
+		views = append(views, *view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return views, nil
+	}
+
+	// The answers of every application in one query
+	query := `
+		SELECT id, application_id, question_id, answer
+		FROM application_answers
+		WHERE application_id = ANY($1)
+	`
+	answerRows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application answers: %w", err)
+	}
+	defer answerRows.Close()
+
+	for answerRows.Next() {
+		var answer models.ApplicationAnswer
+		if err := answerRows.Scan(&answer.ID, &answer.ApplicationID, &answer.QuestionID, &answer.Answer); err != nil {
+			return nil, fmt.Errorf("failed to scan application answer: %w", err)
+		}
+		i := byID[answer.ApplicationID]
+		views[i].Answers = append(views[i].Answers, answer)
+	}
+
+	return views, answerRows.Err()
+}
+
+// UpdateApplicationStatus moves an application through the pipeline. Withdrawn applications are final.
+func (r *jobRepository) UpdateApplicationStatus(applicationID uuid.UUID, status string) error {
+	query := `
+		UPDATE applications
+		SET status = $1
+		WHERE id = $2 AND status <> 'withdrawn'
+		RETURNING id
+	`
+	var id uuid.UUID
+	err := r.db.QueryRow(query, status, applicationID).Scan(&id)
+	if err == sql.ErrNoRows {
+		var exists bool
+		if err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM applications WHERE id = $1)`, applicationID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check if application exists: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("application with ID %s not found", applicationID)
+		}
+		return fmt.Errorf("application has been withdrawn and can no longer change status")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update application status: %w", err)
+	}
+	return nil
+}
+
+// DeleteApplication removes an application with its answers, snapshot and history, for one
+// that could not be completed
+func (r *jobRepository) DeleteApplication(applicationID uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM applications WHERE id = $1`, applicationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete application: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("application with ID %s not found", applicationID)
+	}
+	return nil
+}
+
+// RecordJobEvents writes a batch of tracking events in a single transaction.
+// Views that were already recorded for the same viewer on the same day are ignored,
+// as are events for jobs deleted since the event was queued. An event that cannot be
+// written is skipped without losing the rest of the batch; the returned error lists
+// the skipped events.
+func (r *jobRepository) RecordJobEvents(events []models.JobEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	viewStmt, err := tx.Prepare(`
+		INSERT INTO job_views (job_id, viewer_key, view_date, first_viewed_at)
+		SELECT id, $2, $3::timestamptz::date, $3 FROM jobs WHERE id = $1
+		ON CONFLICT (job_id, viewer_key, view_date) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare view insert: %w", err)
+	}
+	defer viewStmt.Close()
+
+	applyStmt, err := tx.Prepare(`
+		INSERT INTO job_apply_events (job_id, user_id, event_type, created_at)
+		SELECT id, (SELECT u.id FROM users u WHERE u.id = $2), $3, $4 FROM jobs WHERE id = $1
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare apply event insert: %w", err)
+	}
+	defer applyStmt.Close()
+
+	// Each event runs under a savepoint so a failing one only rolls back itself
+	var skipped []error
+	for _, event := range events {
+		if _, err := tx.Exec(`SAVEPOINT job_event`); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		switch event.EventType {
+		case models.JobEventView:
+			_, err = viewStmt.Exec(event.JobID, event.ViewerKey, event.OccurredAt)
+		case models.JobEventApplyStart, models.JobEventApplyComplete:
+			_, err = applyStmt.Exec(event.JobID, event.UserID, event.EventType, event.OccurredAt)
+		default:
+			err = fmt.Errorf("unknown event type %q", event.EventType)
+		}
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("skipped %s event for job %s: %w", event.EventType, event.JobID, err))
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT job_event`); err != nil {
+				return fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			continue
+		}
+		if _, err := tx.Exec(`RELEASE SAVEPOINT job_event`); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return errors.Join(skipped...)
+}
+
+func (r *jobRepository) GetJobActivity(jobID uuid.UUID, from, to time.Time) (*models.JobActivity, error) {
+	activity := &models.JobActivity{JobID: jobID}
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM job_views WHERE job_id = $1),
+			COUNT(*) FILTER (WHERE event_type = 'apply_start'),
+			COUNT(*) FILTER (WHERE event_type = 'apply_complete')
+		FROM job_apply_events
+		WHERE job_id = $1
+	`
+	err := r.db.QueryRow(query, jobID).Scan(&activity.Views, &activity.ApplyStarts, &activity.ApplyCompletes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job counters: %w", err)
+	}
+
+	query = `
+		SELECT to_char(day, 'YYYY-MM-DD'),
+			   COALESCE(v.views, 0), COALESCE(e.starts, 0), COALESCE(e.completes, 0)
+		FROM generate_series($2::date, $3::date, interval '1 day') AS day
+		LEFT JOIN (
+			SELECT view_date, COUNT(*) AS views
+			FROM job_views
+			WHERE job_id = $1 AND view_date BETWEEN $2::date AND $3::date
+			GROUP BY view_date
+		) v ON v.view_date = day::date
+		LEFT JOIN (
+			SELECT created_at::date AS event_date,
+				   COUNT(*) FILTER (WHERE event_type = 'apply_start') AS starts,
+				   COUNT(*) FILTER (WHERE event_type = 'apply_complete') AS completes
+			FROM job_apply_events
+			WHERE job_id = $1 AND created_at::date BETWEEN $2::date AND $3::date
+			GROUP BY created_at::date
+		) e ON e.event_date = day::date
+		ORDER BY day ASC
+	`
+	rows, err := r.db.Query(query, jobID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily job activity: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day models.JobActivityDaily
+		if err := rows.Scan(&day.Date, &day.Views, &day.ApplyStarts, &day.ApplyCompletes); err != nil {
+			return nil, fmt.Errorf("failed to scan daily job activity: %w", err)
+		}
+		activity.Daily = append(activity.Daily, day)
+	}
+
+	return activity, rows.Err()
+}
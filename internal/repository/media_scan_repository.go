@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxMediaScanAttempts is how often a file that could not be scanned is tried again,
+// with the wait doubling each time, before it is left for an admin to look at
+const maxMediaScanAttempts = 5
+
+// MediaScanRepository tracks the malware scans of uploaded files
+type MediaScanRepository interface {
+	GetMediaToScan(limit int) ([]models.MediaScanJob, error)
+	RecordMediaScan(job models.MediaScanJob, status, result string) error
+	GetFlaggedMedia(status string) ([]models.FlaggedMedia, error)
+	RescanMedia(mediaID uuid.UUID) (*models.UserMedia, error)
+	RescanAllMedia(status string) (int64, error)
+}
+
+type mediaScanRepository struct {
+	db *sql.DB
+}
+
+func NewMediaScanRepository(db *sql.DB) MediaScanRepository {
+	return &mediaScanRepository{db: db}
+}
+
+// GetMediaToScan returns files not scanned yet, oldest first, and files whose scan failed
+// once their backoff (2, 4, 8... minutes) is over. Copies of files submitted with applications
+// are scanned like uploads.
+func (r *mediaScanRepository) GetMediaToScan(limit int) ([]models.MediaScanJob, error) {
+	query := `
+		SELECT id, NULL::uuid, user_id, file_path, scan_generation, created_at
+		FROM user_media
+		WHERE scan_status = 'pending'
+		   OR (scan_status = 'error' AND scan_attempts < $2
+		       AND scanned_at < NOW() - make_interval(mins => power(2, scan_attempts)::int))
+		UNION ALL
+		SELECT m.media_id, m.application_id, a.applicant_id, m.file_path, m.scan_generation, m.created_at
+		FROM application_snapshot_media m
+		INNER JOIN applications a ON a.id = m.application_id
+		WHERE m.scan_status = 'pending'
+		   OR (m.scan_status = 'error' AND m.scan_attempts < $2
+		       AND m.scanned_at < NOW() - make_interval(mins => power(2, m.scan_attempts)::int))
+		ORDER BY created_at
+		LIMIT $1
+	`
+	rows, err := r.db.Query(query, limit, maxMediaScanAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media to scan: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []models.MediaScanJob
+	for rows.Next() {
+		var job models.MediaScanJob
+		var createdAt time.Time
+		if err := rows.Scan(&job.MediaID, &job.ApplicationID, &job.UserID, &job.FilePath, &job.Generation, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// RecordMediaScan stores the outcome of a scan: the signature found for quarantined files,
+// the reason for failed scans. A file deleted while it was being scanned is ignored, and so is
+// the outcome of a scan overtaken by a rescan.
+func (r *mediaScanRepository) RecordMediaScan(job models.MediaScanJob, status, result string) error {
+	query := `
+		UPDATE user_media
+		SET scan_status = $3, scan_result = NULLIF($4, ''), scan_attempts = scan_attempts + 1, scanned_at = NOW()
+		WHERE id = $1 AND scan_generation = $2
+	`
+	args := []any{job.MediaID, job.Generation, status, result}
+	if job.ApplicationID != nil {
+		query = `
+			UPDATE application_snapshot_media
+			SET scan_status = $3, scan_result = NULLIF($4, ''), scan_attempts = scan_attempts + 1, scanned_at = NOW()
+			WHERE media_id = $1 AND scan_generation = $2 AND application_id = $5
+		`
+		args = append(args, *job.ApplicationID)
+	}
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to record media scan: %w", err)
+	}
+	return nil
+}
+
+// GetFlaggedMedia lists quarantined files and files that could not be scanned, most recent
+// first. An empty status returns both.
+func (r *mediaScanRepository) GetFlaggedMedia(status string) ([]models.FlaggedMedia, error) {
+	query := `
+		SELECT m.id, m.user_id, m.media_type, m.file_name, m.file_path, m.file_size, m.mime_type,
+			   m.alt_text, m.description, m.education_id, m.experience_id, m.certification_id, m.project_id,
+			   m.scan_status, m.scan_result, m.scanned_at, m.created_at, m.updated_at,
+			   u.email, u.full_name, m.scan_attempts
+		FROM user_media m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.scan_status IN ('quarantined', 'error') AND ($1 = '' OR m.scan_status = $1)
+		ORDER BY m.scanned_at DESC NULLS LAST
+	`
+	rows, err := r.db.Query(query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flagged media: %w", err)
+	}
+	defer rows.Close()
+
+	flagged := []models.FlaggedMedia{}
+	for rows.Next() {
+		var f models.FlaggedMedia
+		m := &f.UserMedia
+		err := rows.Scan(
+			&m.ID, &m.UserID, &m.MediaType, &m.FileName, &m.FilePath, &m.FileSize,
+			&m.MimeType, &m.AltText, &m.Description, &m.EducationID, &m.ExperienceID,
+			&m.CertificationID, &m.ProjectID, &m.ScanStatus, &m.ScanResult, &m.ScannedAt,
+			&m.CreatedAt, &m.UpdatedAt, &f.OwnerEmail, &f.OwnerName, &f.ScanAttempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan flagged media: %w", err)
+		}
+		flagged = append(flagged, f)
+	}
+	return flagged, rows.Err()
+}
+
+// RescanMedia queues one file and its copies submitted with applications for another scan,
+// hiding them from recruiters until it is done
+func (r *mediaScanRepository) RescanMedia(mediaID uuid.UUID) (*models.UserMedia, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE user_media
+		SET scan_status = 'pending', scan_result = NULL, scan_attempts = 0, scan_generation = scan_generation + 1
+		WHERE id = $1
+		RETURNING ` + mediaColumns
+
+	media, err := scanMedia(tx.QueryRow(query, mediaID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("media with ID %s not found", mediaID)
+		}
+		return nil, fmt.Errorf("failed to queue media scan: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE application_snapshot_media
+		SET scan_status = 'pending', scan_result = NULL, scan_attempts = 0, scan_generation = scan_generation + 1
+		WHERE media_id = $1
+	`, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue scans of submitted copies: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return media, nil
+}
+
+// RescanAllMedia queues every file with the status for another scan, e.g. all clean files
+// after the scanner got new signatures, and returns how many were queued. Copies of files
+// submitted with applications are included and counted.
+func (r *mediaScanRepository) RescanAllMedia(status string) (int64, error) {
+	var queued int64
+	for _, table := range []string{"user_media", "application_snapshot_media"} {
+		query := `
+			UPDATE ` + table + `
+			SET scan_status = 'pending', scan_result = NULL, scan_attempts = 0, scan_generation = scan_generation + 1
+			WHERE scan_status = $1
+		`
+		result, err := r.db.Exec(query, status)
+		if err != nil {
+			return 0, fmt.Errorf("failed to queue media scans: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		queued += rows
+	}
+	return queued, nil
+}
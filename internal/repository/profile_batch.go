@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetUserProfilesByIDs reads the profiles of many users as the viewer may see them, with one
+// query per section instead of one per user. Entries come without their media, this is for
+// lists that score or filter profiles. Users that do not exist are left out of the map.
+func (r *userRepository) GetUserProfilesByIDs(ids []uuid.UUID, viewer models.ProfileViewer) (map[uuid.UUID]*models.UserProfile, error) {
+	profiles := map[uuid.UUID]*models.UserProfile{}
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+	args := []any{pq.Array(ids), pq.Array(viewer.Visibilities())}
+
+	// The picture has no entry visibility, the NULL makes visibleTo fall through to the section
+	rows, err := r.db.Query(`
+		SELECT `+userColumns+`, `+visibleTo("u", models.ProfileSectionPicture, 2)+`
+		FROM (SELECT *, id AS user_id, NULL::text AS visibility FROM users WHERE id = ANY($1)) u
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var user models.User
+		var pictureVisible bool
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Location, &user.Title,
+			&user.AboutSection, &user.ProfilePicture, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+			&user.DeletionScheduledFor, &pictureVisible,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if user.ProfilePicture != nil && (pictureVisible || viewer == models.ViewerOwner) {
+			url := "/api/v1/users/" + user.ID.String() + "/picture"
+			user.ProfilePictureURL = &url
+		} else {
+			user.ProfilePicture = nil
+		}
+		profiles[user.ID] = &models.UserProfile{User: user}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	// each reads the rows of one section, scan adds a row to the profile of its user
+	each := func(section, query string, scan func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error) error {
+		rows, err := r.db.Query(query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", section, err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			err := scan(rows, func(userID uuid.UUID) *models.UserProfile {
+				if profile, ok := profiles[userID]; ok {
+					return profile
+				}
+				// Entries of a user deleted in between go to a profile nobody reads
+				return &models.UserProfile{}
+			})
+			if err != nil {
+				return fmt.Errorf("failed to scan %s: %w", section, err)
+			}
+		}
+		return rows.Err()
+	}
+
+	err = each(models.ProfileSectionPhoneNumbers, `
+		SELECT id, user_id, phone_number, phone_type, is_primary, visibility, created_at, updated_at
+		FROM user_phone_numbers
+		WHERE user_id = ANY($1) AND `+visibleTo("user_phone_numbers", models.ProfileSectionPhoneNumbers, 2)+`
+		ORDER BY is_primary DESC, created_at ASC
+	`, func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error {
+		var phone models.UserPhoneNumber
+		err := rows.Scan(
+			&phone.ID, &phone.UserID, &phone.PhoneNumber, &phone.PhoneType,
+			&phone.IsPrimary, &phone.Visibility, &phone.CreatedAt, &phone.UpdatedAt,
+		)
+		if err == nil {
+			p := profile(phone.UserID)
+			p.PhoneNumbers = append(p.PhoneNumbers, phone)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = each(models.ProfileSectionEducation, `
+		SELECT id, user_id, institution_name, degree, field_of_study, start_date, end_date,
+			   is_current, grade_gpa, description, visibility, created_at, updated_at
+		FROM user_education
+		WHERE user_id = ANY($1) AND `+visibleTo("user_education", models.ProfileSectionEducation, 2)+`
+		ORDER BY is_current DESC, end_date DESC NULLS FIRST, start_date DESC
+	`, func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error {
+		var edu models.UserEducation
+		err := rows.Scan(
+			&edu.ID, &edu.UserID, &edu.InstitutionName, &edu.Degree, &edu.FieldOfStudy,
+			&edu.StartDate, &edu.EndDate, &edu.IsCurrent, &edu.GradeGPA, &edu.Description,
+			&edu.Visibility, &edu.CreatedAt, &edu.UpdatedAt,
+		)
+		if err == nil {
+			p := profile(edu.UserID)
+			p.Education = append(p.Education, edu)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = each(models.ProfileSectionExperience, `
+		SELECT id, user_id, company_name, position_title, employment_type, start_date, end_date,
+			   is_current, location, description, visibility, created_at, updated_at
+		FROM user_experience
+		WHERE user_id = ANY($1) AND `+visibleTo("user_experience", models.ProfileSectionExperience, 2)+`
+		ORDER BY is_current DESC, end_date DESC NULLS FIRST, start_date DESC
+	`, func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error {
+		var exp models.UserExperience
+		err := rows.Scan(
+			&exp.ID, &exp.UserID, &exp.CompanyName, &exp.PositionTitle, &exp.EmploymentType,
+			&exp.StartDate, &exp.EndDate, &exp.IsCurrent, &exp.Location, &exp.Description,
+			&exp.Visibility, &exp.CreatedAt, &exp.UpdatedAt,
+		)
+		if err == nil {
+			p := profile(exp.UserID)
+			p.Experience = append(p.Experience, exp)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = each(models.ProfileSectionCertifications, `
+		SELECT id, user_id, certification_name, issuing_organization, issue_date, expiration_date,
+			   credential_id, credential_url, description, visibility, created_at, updated_at
+		FROM user_certifications
+		WHERE user_id = ANY($1) AND `+visibleTo("user_certifications", models.ProfileSectionCertifications, 2)+`
+		ORDER BY issue_date DESC NULLS LAST, created_at DESC
+	`, func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error {
+		var cert models.UserCertification
+		err := rows.Scan(
+			&cert.ID, &cert.UserID, &cert.CertificationName, &cert.IssuingOrganization,
+			&cert.IssueDate, &cert.ExpirationDate, &cert.CredentialID, &cert.CredentialURL,
+			&cert.Description, &cert.Visibility, &cert.CreatedAt, &cert.UpdatedAt,
+		)
+		if err == nil {
+			p := profile(cert.UserID)
+			p.Certifications = append(p.Certifications, cert)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = each(models.ProfileSectionProjects, `
+		SELECT id, user_id, project_name, description, start_date, end_date, is_ongoing,
+			   project_url, visibility, created_at, updated_at
+		FROM user_projects
+		WHERE user_id = ANY($1) AND `+visibleTo("user_projects", models.ProfileSectionProjects, 2)+`
+		ORDER BY is_ongoing DESC, end_date DESC NULLS FIRST, start_date DESC
+	`, func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error {
+		var project models.UserProject
+		err := rows.Scan(
+			&project.ID, &project.UserID, &project.ProjectName, &project.Description,
+			&project.StartDate, &project.EndDate, &project.IsOngoing, &project.ProjectURL, &project.Visibility,
+			&project.CreatedAt, &project.UpdatedAt,
+		)
+		if err == nil {
+			p := profile(project.UserID)
+			p.Projects = append(p.Projects, project)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = each(models.ProfileSectionSkills, `
+		SELECT us.user_id, s.id, s.name, us.proficiency, us.years_used, us.last_used_year, us.experience_ids, us.project_ids, us.visibility
+		FROM skills s
+		INNER JOIN user_skills us ON s.id = us.skill_id
+		WHERE us.user_id = ANY($1) AND `+visibleTo("us", models.ProfileSectionSkills, 2)+`
+		ORDER BY s.name ASC
+	`, func(rows interface{ Scan(dest ...any) error }, profile func(uuid.UUID) *models.UserProfile) error {
+		var userID uuid.UUID
+		var skill models.Skill
+		err := rows.Scan(&userID, &skill.ID, &skill.Name, &skill.Proficiency, &skill.YearsUsed, &skill.LastUsedYear,
+			pq.Array(&skill.ExperienceIDs), pq.Array(&skill.ProjectIDs), &skill.Visibility)
+		if err == nil {
+			p := profile(userID)
+			p.Skills = append(p.Skills, skill)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// profileEntries is what the entries of a section are read from under the alias: the table
+// itself, or with asOf the rows the change log had for the entries that existed at that time.
+// Reading the log takes the user ID as $1 and asOf as $3, see profileArgs.
+func profileEntries(section, alias string, asOf *time.Time) string {
+	table := sectionTables[section].table
+	if asOf == nil {
+		if alias == table {
+			return table
+		}
+		return table + " " + alias
+	}
+
+	return fmt.Sprintf(`(
+			SELECT (jsonb_populate_record(NULL::%[1]s, c.after)).*
+			FROM (
+				SELECT DISTINCT ON (entry_id) action, after
+				FROM profile_changes
+				WHERE user_id = $1 AND section = '%[2]s' AND changed_at <= $3
+				ORDER BY entry_id, changed_at DESC
+			) c
+			WHERE c.action <> 'delete'
+		) %[3]s`, table, section, alias)
+}
+
+// profileArgs are the query arguments for reading a section through profileEntries and visibleTo
+func profileArgs(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) []any {
+	args := []any{userID, pq.Array(viewer.Visibilities())}
+	if asOf != nil {
+		args = append(args, *asOf)
+	}
+	return args
+}
+
+// GetProfileAsOf reconstructs the user's profile entries as they were at the time. Entries are
+// rebuilt from the change log; the user's own fields and the media are as they are now.
+func (r *userRepository) GetProfileAsOf(userID uuid.UUID, asOf time.Time) (*models.UserProfile, error) {
+	return r.getUserProfile(userID, models.ViewerOwner, &asOf)
+}
+
+// GetProfileHistory returns the newest changes to the user's profile entries, optionally only
+// those of one section or those made before a point in time. A limit of 0 returns all of them.
+func (r *userRepository) GetProfileHistory(userID uuid.UUID, section string, before *time.Time, limit int) ([]models.ProfileChange, error) {
+	query := `
+		SELECT id, section, entry_id, action, before, after, changed_at
+		FROM profile_changes
+		WHERE user_id = $1 AND ($2::text = '' OR section = $2) AND ($3::timestamptz IS NULL OR changed_at < $3)
+		ORDER BY changed_at DESC
+		LIMIT NULLIF($4, 0)
+	`
+	rows, err := r.db.Query(query, userID, section, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.ProfileChange
+	for rows.Next() {
+		change, err := scanProfileChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile change: %w", err)
+		}
+		changes = append(changes, *change)
+	}
+
+	return changes, rows.Err()
+}
+
+// skillLinkColumns are the user_skills columns that link skills to the entries of a section
+var skillLinkColumns = map[string]string{
+	models.ProfileSectionExperience: "experience_ids",
+	models.ProfileSectionProjects:   "project_ids",
+}
+
+// RestoreProfileEntry puts a deleted entry back as it was before the delete, with its original
+// ID, linked again to the skills it was linked to that the user still has. Its media is not
+// restored, files are removed together with the entry. Returns the change the restore was
+// logged as.
+func (r *userRepository) RestoreProfileEntry(userID, changeID uuid.UUID) (*models.ProfileChange, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	change, err := scanProfileChange(tx.QueryRow(`
+		SELECT id, section, entry_id, action, before, after, changed_at
+		FROM profile_changes
+		WHERE id = $1 AND user_id = $2
+	`, changeID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile change with ID %s not found", changeID)
+		}
+		return nil, fmt.Errorf("failed to get profile change: %w", err)
+	}
+	if change.Action != models.ProfileChangeDelete {
+		return nil, fmt.Errorf("only deleted entries can be restored, this change is a %s", change.Action)
+	}
+
+	// What the entry pointed at may have changed since it was deleted
+	var conflict bool
+	switch change.Section {
+	case models.ProfileSectionPhoneNumbers:
+		err = tx.QueryRow(`
+			SELECT ($2::jsonb->>'is_primary')::boolean
+			   AND EXISTS (SELECT 1 FROM user_phone_numbers WHERE user_id = $1 AND is_primary)
+		`, userID, string(change.Before)).Scan(&conflict)
+		if err == nil && conflict {
+			return nil, fmt.Errorf("another phone number is already primary, unmark it to restore this one")
+		}
+	case models.ProfileSectionSkills:
+		err = tx.QueryRow(`
+			SELECT NOT EXISTS (SELECT 1 FROM skills WHERE id = ($1::jsonb->>'skill_id')::int)
+		`, string(change.Before)).Scan(&conflict)
+		if err == nil && conflict {
+			return nil, fmt.Errorf("the skill of this entry no longer exists")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check entry: %w", err)
+	}
+
+	table := sectionTables[change.Section].table
+	_, err = tx.Exec(fmt.Sprintf(`INSERT INTO %[1]s SELECT (jsonb_populate_record(NULL::%[1]s, $1::jsonb)).*`, table), string(change.Before))
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore entry: %w", err)
+	}
+
+	// The delete took the entry off its skills, logged as skill updates following the delete
+	// and before the entry's next change
+	if column, ok := skillLinkColumns[change.Section]; ok {
+		_, err = tx.Exec(fmt.Sprintf(`
+			UPDATE user_skills us
+			SET %[1]s = array_append(us.%[1]s, $4::uuid)
+			FROM profile_changes c
+			WHERE us.user_id = $1 AND c.user_id = $1 AND c.section = 'skills' AND c.action = 'update'
+			  AND c.entry_id = us.skill_id::text
+			  AND c.changed_at > $3
+			  AND c.changed_at < COALESCE((
+				  SELECT min(changed_at) FROM profile_changes
+				  WHERE user_id = $1 AND section = $2 AND entry_id = $4::text AND changed_at > $3
+			  ), 'infinity')
+			  AND c.before->'%[1]s' ? $4::text
+			  AND NOT COALESCE(c.after->'%[1]s' ? $4::text, false)
+			  AND NOT $4::uuid = ANY(COALESCE(us.%[1]s, '{}'))
+		`, column), userID, change.Section, change.ChangedAt, change.EntryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to link entry to its skills again: %w", err)
+		}
+	}
+
+	restored, err := scanProfileChange(tx.QueryRow(`
+		SELECT id, section, entry_id, action, before, after, changed_at
+		FROM profile_changes
+		WHERE user_id = $1 AND section = $2 AND entry_id = $3
+		ORDER BY changed_at DESC
+		LIMIT 1
+	`, userID, change.Section, change.EntryID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return restored, nil
+}
+
+func scanProfileChange(row interface{ Scan(dest ...any) error }) (*models.ProfileChange, error) {
+	var change models.ProfileChange
+	var before, after []byte
+	err := row.Scan(&change.ID, &change.Section, &change.EntryID, &change.Action, &before, &after, &change.ChangedAt)
+	if err != nil {
+		return nil, err
+	}
+	change.Before, change.After = before, after
+	return &change, nil
+}
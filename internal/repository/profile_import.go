@@ -0,0 +1,523 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+// importSection describes how one profile section takes part in an import. Entries are
+// matched by key; existingQuery selects id, key and label of the user's current entries
+// and must build the key the same way key does for incoming entries. mediaColumn is empty
+// for sections that cannot have media.
+type importSection struct {
+	table         string
+	mediaColumn   string
+	existingQuery string
+	count         int
+	key           func(i int) string
+	label         func(i int) string
+	create        func(q rowQueryer, i int) error
+	update        func(q rowQueryer, id uuid.UUID, i int) error
+}
+
+// importKey joins the matching fields of an entry the same way the SQL side does
+func importKey(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(p))
+	}
+	return strings.Join(parts, "|")
+}
+
+// importMonth reduces a YYYY-MM-DD date to its month, which is what entries are matched on
+func importMonth(date string) string {
+	if len(date) >= 7 {
+		return date[:7]
+	}
+	return date
+}
+
+func withMonth(label, date string) string {
+	if month := importMonth(date); month != "" {
+		return label + " (" + month + ")"
+	}
+	return label
+}
+
+// ImportProfile applies imported profile data in a single transaction. In merge mode new
+// entries are added and everything else is left alone; in replace mode matching entries are
+// updated and entries missing from the import are removed. A preview works out the same
+// changes and rolls them back.
+func (r *userRepository) ImportProfile(userID uuid.UUID, data dto.ProfileImport, mode string, preview bool) (*models.ProfileImportResult, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// One import at a time per user
+	if _, err := tx.Exec(`SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	result := &models.ProfileImportResult{
+		Mode:           mode,
+		Preview:        preview,
+		Basics:         []string{},
+		PhoneNumbers:   newImportSectionDiff(),
+		Education:      newImportSectionDiff(),
+		Experience:     newImportSectionDiff(),
+		Certifications: newImportSectionDiff(),
+		Projects:       newImportSectionDiff(),
+		Skills: models.SkillImportDiff{
+			Added: []string{}, Unchanged: []string{}, Removed: []string{}, Suggested: []string{},
+		},
+	}
+
+	if result.Basics, err = applyBasicsImport(tx, userID, data.Basics, mode); err != nil {
+		return nil, err
+	}
+
+	phones, err := phoneNumberImport(tx, userID, data.PhoneNumbers, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := []struct {
+		section importSection
+		diff    *models.ImportSectionDiff
+	}{
+		{phones, &result.PhoneNumbers},
+		{educationImport(userID, data.Education), &result.Education},
+		{experienceImport(userID, data.Experience), &result.Experience},
+		{certificationImport(userID, data.Certifications), &result.Certifications},
+		{projectImport(userID, data.Projects), &result.Projects},
+	}
+	for _, s := range sections {
+		removed, err := applyImportSection(tx, userID, s.section, mode, s.diff)
+		if err != nil {
+			return nil, err
+		}
+		result.RemovedFileKeys = append(result.RemovedFileKeys, removed...)
+	}
+
+	// Replacing makes the imported primary number the only primary one
+	if mode == dto.ImportModeReplace {
+		for _, phone := range data.PhoneNumbers {
+			if !phone.IsPrimary {
+				continue
+			}
+			_, err := tx.Exec(`
+				UPDATE user_phone_numbers SET is_primary = lower(trim(phone_number)) = $2, updated_at = NOW()
+				WHERE user_id = $1 AND is_primary <> (lower(trim(phone_number)) = $2)
+			`, userID, importKey(phone.PhoneNumber))
+			if err != nil {
+				return nil, fmt.Errorf("failed to set primary phone number: %w", err)
+			}
+			break
+		}
+	}
+
+	if err := applySkillImport(tx, userID, data.Skills, mode, &result.Skills); err != nil {
+		return nil, err
+	}
+
+	if preview {
+		result.RemovedFileKeys = nil
+		return result, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+func newImportSectionDiff() models.ImportSectionDiff {
+	return models.ImportSectionDiff{Added: []string{}, Updated: []string{}, Unchanged: []string{}, Removed: []string{}}
+}
+
+func applyImportSection(tx *sql.Tx, userID uuid.UUID, section importSection, mode string, diff *models.ImportSectionDiff) ([]string, error) {
+	type existingEntry struct {
+		id      uuid.UUID
+		label   string
+		matched bool
+	}
+
+	rows, err := tx.Query(section.existingQuery, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current %s: %w", section.table, err)
+	}
+	var existing []*existingEntry
+	byKey := map[string]*existingEntry{}
+	for rows.Next() {
+		var entry existingEntry
+		var key string
+		if err := rows.Scan(&entry.id, &key, &entry.label); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan current %s: %w", section.table, err)
+		}
+		existing = append(existing, &entry)
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = &entry
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	imported := map[string]bool{}
+	for i := 0; i < section.count; i++ {
+		key := section.key(i)
+		// The same entry listed twice in the import is only taken once
+		if imported[key] {
+			continue
+		}
+		imported[key] = true
+
+		if entry, ok := byKey[key]; ok {
+			entry.matched = true
+			if mode == dto.ImportModeReplace {
+				if err := section.update(tx, entry.id, i); err != nil {
+					return nil, err
+				}
+				diff.Updated = append(diff.Updated, section.label(i))
+			} else {
+				diff.Unchanged = append(diff.Unchanged, entry.label)
+			}
+			continue
+		}
+
+		if err := section.create(tx, i); err != nil {
+			return nil, err
+		}
+		diff.Added = append(diff.Added, section.label(i))
+	}
+
+	if mode != dto.ImportModeReplace {
+		return nil, nil
+	}
+
+	var removedFiles []string
+	for _, entry := range existing {
+		if entry.matched {
+			continue
+		}
+		if section.mediaColumn == "" {
+			if _, err := tx.Exec(`DELETE FROM `+section.table+` WHERE id = $1 AND user_id = $2`, entry.id, userID); err != nil {
+				return nil, fmt.Errorf("failed to remove %s entry: %w", section.table, err)
+			}
+			diff.Removed = append(diff.Removed, entry.label)
+			continue
+		}
+		files, err := tx.Query(`
+			SELECT file_path FROM user_media WHERE `+section.mediaColumn+` = $1
+			UNION ALL
+			SELECT t.file_path FROM user_media_thumbnails t
+			INNER JOIN user_media m ON m.id = t.media_id
+			WHERE m.`+section.mediaColumn+` = $1
+		`, entry.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get media of removed entry: %w", err)
+		}
+		for files.Next() {
+			var path string
+			if err := files.Scan(&path); err != nil {
+				files.Close()
+				return nil, fmt.Errorf("failed to scan media of removed entry: %w", err)
+			}
+			removedFiles = append(removedFiles, path)
+		}
+		files.Close()
+
+		if _, err := tx.Exec(`DELETE FROM `+section.table+` WHERE id = $1 AND user_id = $2`, entry.id, userID); err != nil {
+			return nil, fmt.Errorf("failed to remove %s entry: %w", section.table, err)
+		}
+		diff.Removed = append(diff.Removed, entry.label)
+	}
+	return removedFiles, nil
+}
+
+// applyBasicsImport sets the basic profile fields the import has. Merge mode only fills in
+// fields the profile has no value for. Returns the fields that changed.
+func applyBasicsImport(tx *sql.Tx, userID uuid.UUID, basics dto.UpdateProfileRequest, mode string) ([]string, error) {
+	var fullName string
+	var location, title, about sql.NullString
+	err := tx.QueryRow(`SELECT full_name, location, title, about_section FROM users WHERE id = $1`, userID).
+		Scan(&fullName, &location, &title, &about)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	changed := []string{}
+	var update dto.UpdateProfileRequest
+	pick := func(field string, current string, imported *string) *string {
+		if imported == nil {
+			return nil
+		}
+		value := strings.TrimSpace(*imported)
+		if value == "" || value == current || (mode != dto.ImportModeReplace && current != "") {
+			return nil
+		}
+		changed = append(changed, field)
+		return &value
+	}
+	update.FullName = pick("full_name", fullName, basics.FullName)
+	update.Location = pick("location", location.String, basics.Location)
+	update.Title = pick("title", title.String, basics.Title)
+	update.AboutSection = pick("about_section", about.String, basics.AboutSection)
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users
+		SET full_name = COALESCE($2, full_name), location = COALESCE($3, location),
+			title = COALESCE($4, title), about_section = COALESCE($5, about_section), updated_at = NOW()
+		WHERE id = $1
+	`, userID, update.FullName, update.Location, update.Title, update.AboutSection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	return changed, nil
+}
+
+// phoneNumberImport matches phone numbers on the number itself. Merging never takes the
+// primary mark from a number already on the profile.
+func phoneNumberImport(tx *sql.Tx, userID uuid.UUID, entries []dto.CreatePhoneNumberRequest, mode string) (importSection, error) {
+	var hasPrimary bool
+	err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM user_phone_numbers WHERE user_id = $1 AND is_primary)`, userID).
+		Scan(&hasPrimary)
+	if err != nil {
+		return importSection{}, fmt.Errorf("failed to check primary phone number: %w", err)
+	}
+
+	return importSection{
+		table: "user_phone_numbers",
+		existingQuery: `
+			SELECT id, lower(trim(phone_number)), phone_number
+			FROM user_phone_numbers WHERE user_id = $1
+		`,
+		count: len(entries),
+		key: func(i int) string {
+			return importKey(entries[i].PhoneNumber)
+		},
+		label: func(i int) string {
+			return entries[i].PhoneNumber
+		},
+		create: func(q rowQueryer, i int) error {
+			phone := entries[i]
+			if mode != dto.ImportModeReplace && hasPrimary {
+				phone.IsPrimary = false
+			}
+			_, err := createPhoneNumber(q, userID, phone)
+			return err
+		},
+		update: func(q rowQueryer, id uuid.UUID, i int) error {
+			_, err := updatePhoneNumber(q, userID, id, entries[i])
+			return err
+		},
+	}, nil
+}
+
+func educationImport(userID uuid.UUID, entries []dto.CreateEducationRequest) importSection {
+	return importSection{
+		table:       "user_education",
+		mediaColumn: "education_id",
+		existingQuery: `
+			SELECT id,
+				lower(trim(institution_name)) || '|' || lower(trim(degree)) || '|' || COALESCE(to_char(start_date, 'YYYY-MM'), ''),
+				degree || ', ' || institution_name || COALESCE(' (' || to_char(start_date, 'YYYY-MM') || ')', '')
+			FROM user_education WHERE user_id = $1
+		`,
+		count: len(entries),
+		key: func(i int) string {
+			return importKey(entries[i].InstitutionName, entries[i].Degree, importMonth(entries[i].StartDate))
+		},
+		label: func(i int) string {
+			return withMonth(entries[i].Degree+", "+entries[i].InstitutionName, entries[i].StartDate)
+		},
+		create: func(q rowQueryer, i int) error {
+			_, err := createEducation(q, userID, entries[i])
+			return err
+		},
+		update: func(q rowQueryer, id uuid.UUID, i int) error {
+			_, err := updateEducation(q, userID, id, entries[i])
+			return err
+		},
+	}
+}
+
+func experienceImport(userID uuid.UUID, entries []dto.CreateExperienceRequest) importSection {
+	return importSection{
+		table:       "user_experience",
+		mediaColumn: "experience_id",
+		existingQuery: `
+			SELECT id,
+				lower(trim(company_name)) || '|' || lower(trim(position_title)) || '|' || COALESCE(to_char(start_date, 'YYYY-MM'), ''),
+				position_title || ' at ' || company_name || COALESCE(' (' || to_char(start_date, 'YYYY-MM') || ')', '')
+			FROM user_experience WHERE user_id = $1
+		`,
+		count: len(entries),
+		key: func(i int) string {
+			return importKey(entries[i].CompanyName, entries[i].PositionTitle, importMonth(entries[i].StartDate))
+		},
+		label: func(i int) string {
+			return withMonth(entries[i].PositionTitle+" at "+entries[i].CompanyName, entries[i].StartDate)
+		},
+		create: func(q rowQueryer, i int) error {
+			_, err := createExperience(q, userID, entries[i])
+			return err
+		},
+		update: func(q rowQueryer, id uuid.UUID, i int) error {
+			_, err := updateExperience(q, userID, id, entries[i])
+			return err
+		},
+	}
+}
+
+func certificationImport(userID uuid.UUID, entries []dto.CreateCertificationRequest) importSection {
+	return importSection{
+		table:       "user_certifications",
+		mediaColumn: "certification_id",
+		existingQuery: `
+			SELECT id,
+				lower(trim(certification_name)) || '|' || lower(trim(issuing_organization)),
+				certification_name || ' from ' || issuing_organization
+			FROM user_certifications WHERE user_id = $1
+		`,
+		count: len(entries),
+		key: func(i int) string {
+			return importKey(entries[i].CertificationName, entries[i].IssuingOrganization)
+		},
+		label: func(i int) string {
+			return entries[i].CertificationName + " from " + entries[i].IssuingOrganization
+		},
+		create: func(q rowQueryer, i int) error {
+			_, err := createCertification(q, userID, entries[i])
+			return err
+		},
+		update: func(q rowQueryer, id uuid.UUID, i int) error {
+			_, err := updateCertification(q, userID, id, entries[i])
+			return err
+		},
+	}
+}
+
+func projectImport(userID uuid.UUID, entries []dto.CreateProjectRequest) importSection {
+	return importSection{
+		table:       "user_projects",
+		mediaColumn: "project_id",
+		existingQuery: `
+			SELECT id, lower(trim(project_name)), project_name
+			FROM user_projects WHERE user_id = $1
+		`,
+		count: len(entries),
+		key: func(i int) string {
+			return importKey(entries[i].ProjectName)
+		},
+		label: func(i int) string {
+			return entries[i].ProjectName
+		},
+		create: func(q rowQueryer, i int) error {
+			_, err := createProject(q, userID, entries[i])
+			return err
+		},
+		update: func(q rowQueryer, id uuid.UUID, i int) error {
+			_, err := updateProject(q, userID, id, entries[i])
+			return err
+		},
+	}
+}
+
+// applySkillImport adds the imported skills the skills table knows by name or alias,
+// case-insensitively, and files the rest as suggestions for an admin to review
+func applySkillImport(tx *sql.Tx, userID uuid.UUID, names []string, mode string, diff *models.SkillImportDiff) error {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	known, err := resolveSkillNames(tx, names)
+	if err != nil {
+		return err
+	}
+
+	current := map[int]string{}
+	rows, err := tx.Query(`
+		SELECT s.id, s.name FROM user_skills us
+		INNER JOIN skills s ON s.id = us.skill_id
+		WHERE us.user_id = $1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get current skills: %w", err)
+	}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan skill: %w", err)
+		}
+		current[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	keep := map[int]bool{}
+	for i, name := range names {
+		skill, ok := known[lowered[i]]
+		if !ok {
+			_, err := tx.Exec(`
+				INSERT INTO skill_suggestions (user_id, name)
+				VALUES ($1, $2)
+				ON CONFLICT (user_id, lower(name)) DO NOTHING
+			`, userID, name)
+			if err != nil {
+				return fmt.Errorf("failed to suggest skill %s: %w", name, err)
+			}
+			diff.Suggested = append(diff.Suggested, name)
+			continue
+		}
+		if keep[skill.ID] {
+			continue
+		}
+		keep[skill.ID] = true
+
+		if _, ok := current[skill.ID]; ok {
+			diff.Unchanged = append(diff.Unchanged, skill.Name)
+			continue
+		}
+		_, err := tx.Exec(`
+			INSERT INTO user_skills (user_id, skill_id)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id, skill_id) DO NOTHING
+		`, userID, skill.ID)
+		if err != nil {
+			return fmt.Errorf("failed to add skill %s: %w", skill.Name, err)
+		}
+		diff.Added = append(diff.Added, skill.Name)
+	}
+
+	if mode != dto.ImportModeReplace {
+		return nil
+	}
+	for id, name := range current {
+		if keep[id] {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM user_skills WHERE user_id = $1 AND skill_id = $2`, userID, id); err != nil {
+			return fmt.Errorf("failed to remove skill %s: %w", name, err)
+		}
+		diff.Removed = append(diff.Removed, name)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// sectionTables maps each profile section to its table and the column its entries are addressed by
+var sectionTables = map[string]struct{ table, idColumn string }{
+	models.ProfileSectionPhoneNumbers:   {"user_phone_numbers", "id"},
+	models.ProfileSectionEducation:      {"user_education", "id"},
+	models.ProfileSectionExperience:     {"user_experience", "id"},
+	models.ProfileSectionCertifications: {"user_certifications", "id"},
+	models.ProfileSectionProjects:       {"user_projects", "id"},
+	models.ProfileSectionSkills:         {"user_skills", "skill_id"},
+}
+
+// visibleTo builds the condition for entries of a section the viewer may see, comparing against
+// the viewer's visibilities in parameter $param. An entry uses its own visibility, then the one
+// the user set for the section, then the section default.
+func visibleTo(alias, section string, param int) string {
+	return fmt.Sprintf(`COALESCE(%[1]s.visibility,
+			(SELECT v.visibility FROM profile_section_visibility v WHERE v.user_id = %[1]s.user_id AND v.section = '%[2]s'),
+			'%[3]s') = ANY($%[4]d)`, alias, section, models.DefaultSectionVisibility[section], param)
+}
+
+// GetProfileVisibility returns the public link of the profile and the visibility of every section
+func (r *userRepository) GetProfileVisibility(userID uuid.UUID) (*models.ProfileVisibility, error) {
+	visibility := models.ProfileVisibility{Sections: map[string]string{}}
+	err := r.db.QueryRow(`
+		SELECT profile_slug, profile_published FROM users WHERE id = $1 AND deleted_at IS NULL
+	`, userID).Scan(&visibility.Slug, &visibility.Published)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with ID %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to get profile visibility: %w", err)
+	}
+	if visibility.Slug != nil {
+		url := "/api/v1/profiles/" + *visibility.Slug
+		visibility.PublicURL = &url
+	}
+
+	for section, defaultVisibility := range models.DefaultSectionVisibility {
+		visibility.Sections[section] = defaultVisibility
+	}
+	rows, err := r.db.Query(`SELECT section, visibility FROM profile_section_visibility WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section visibility: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var section, sectionVisibility string
+		if err := rows.Scan(&section, &sectionVisibility); err != nil {
+			return nil, fmt.Errorf("failed to scan section visibility: %w", err)
+		}
+		visibility.Sections[section] = sectionVisibility
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate section visibility: %w", err)
+	}
+
+	return &visibility, nil
+}
+
+// UpdateProfileVisibility changes the public link and the visibility of the given sections. A
+// profile cannot be published without a slug.
+func (r *userRepository) UpdateProfileVisibility(userID uuid.UUID, req dto.UpdateProfileVisibilityRequest) (*models.ProfileVisibility, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var slug *string
+	var published bool
+	err = tx.QueryRow(`
+		UPDATE users
+		SET profile_slug = CASE WHEN $2::text IS NULL THEN profile_slug ELSE NULLIF($2, '') END,
+			profile_published = COALESCE($3, profile_published),
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING profile_slug, profile_published
+	`, userID, req.Slug, req.Published).Scan(&slug, &published)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with ID %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to update profile link: %w", err)
+	}
+	if published && slug == nil {
+		return nil, fmt.Errorf("a slug is required to publish the profile")
+	}
+
+	for section, visibility := range req.Sections {
+		_, err := tx.Exec(`
+			INSERT INTO profile_section_visibility (user_id, section, visibility)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, section) DO UPDATE SET visibility = EXCLUDED.visibility
+		`, userID, section, visibility)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set visibility of %s: %w", section, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetProfileVisibility(userID)
+}
+
+// SetEntryVisibility overrides the visibility of one entry of a section, nil makes it follow the
+// section again. Skills are addressed by skill ID, everything else by entry ID.
+func (r *userRepository) SetEntryVisibility(userID uuid.UUID, section, entryID string, visibility *string) error {
+	target, ok := sectionTables[section]
+	if !ok {
+		return fmt.Errorf("section %s not found", section)
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET visibility = $3 WHERE user_id = $1 AND %s::text = $2`, target.table, target.idColumn)
+	result, err := r.db.Exec(query, userID, entryID, visibility)
+	if err != nil {
+		return fmt.Errorf("failed to set entry visibility: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("entry %s in %s not found", entryID, section)
+	}
+	return nil
+}
+
+// GetProfilePicture returns the storage key of the user's picture when the viewer may see it
+func (r *userRepository) GetProfilePicture(userID uuid.UUID, viewer models.ProfileViewer) (*string, error) {
+	// The picture has no entry visibility, the NULL makes visibleTo fall through to the section
+	query := `
+		SELECT p.profile_picture
+		FROM (
+			SELECT id AS user_id, profile_picture, NULL::text AS visibility
+			FROM users
+			WHERE id = $1 AND deleted_at IS NULL AND profile_picture IS NOT NULL
+		) p
+		WHERE ` + visibleTo("p", models.ProfileSectionPicture, 2)
+
+	var key string
+	if err := r.db.QueryRow(query, userID, pq.Array(viewer.Visibilities())).Scan(&key); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile picture of user %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to get profile picture: %w", err)
+	}
+	return &key, nil
+}
+
+// GetPublicProfile returns the published profile at the slug as the viewer may see it
+func (r *userRepository) GetPublicProfile(slug string, viewer models.ProfileViewer) (*models.UserProfile, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRow(`
+		SELECT id FROM users WHERE profile_slug = $1 AND profile_published AND deleted_at IS NULL
+	`, slug).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile %s not found", slug)
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return r.GetUserProfileByID(userID, viewer)
+}
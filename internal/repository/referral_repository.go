@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ReferralRepository interface {
+	CreateReferralLink(referrerID, jobID uuid.UUID) (*models.ReferralLink, error)
+	GetReferralLinkByCode(code string) (*models.ReferralLink, error)
+	GetReferralLinksByReferrer(referrerID uuid.UUID) ([]models.ReferralLink, error)
+	SavePendingReferral(userID uuid.UUID, code string) error
+}
+
+type referralRepository struct {
+	db *sql.DB
+}
+
+func NewReferralRepository(db *sql.DB) ReferralRepository {
+	return &referralRepository{db: db}
+}
+
+// CreateReferralLink returns the referrer's link for the job, creating it on first use
+func (r *referralRepository) CreateReferralLink(referrerID, jobID uuid.UUID) (*models.ReferralLink, error) {
+	code, err := newReferralCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate referral code: %w", err)
+	}
+
+	query := `
+		WITH inserted AS (
+			INSERT INTO referral_links (code, job_id, referrer_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (job_id, referrer_id) DO NOTHING
+			RETURNING id, code, job_id, referrer_id, created_at
+		)
+		SELECT l.id, l.code, l.job_id, j.title, l.referrer_id, l.created_at
+		FROM (
+			SELECT * FROM inserted
+			UNION ALL
+			SELECT id, code, job_id, referrer_id, created_at FROM referral_links
+			WHERE job_id = $2 AND referrer_id = $3
+		) l
+		INNER JOIN jobs j ON j.id = l.job_id
+		LIMIT 1
+	`
+	var link models.ReferralLink
+	err = r.db.QueryRow(query, code, jobID, referrerID).Scan(
+		&link.ID, &link.Code, &link.JobID, &link.JobTitle, &link.ReferrerID, &link.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create referral link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *referralRepository) GetReferralLinkByCode(code string) (*models.ReferralLink, error) {
+	query := `
+		SELECT l.id, l.code, l.job_id, j.title, l.referrer_id, l.created_at
+		FROM referral_links l
+		INNER JOIN jobs j ON j.id = l.job_id
+		WHERE l.code = $1
+	`
+	var link models.ReferralLink
+	err := r.db.QueryRow(query, code).Scan(
+		&link.ID, &link.Code, &link.JobID, &link.JobTitle, &link.ReferrerID, &link.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("referral code %s not found", code)
+		}
+		return nil, fmt.Errorf("failed to get referral link: %w", err)
+	}
+	return &link, nil
+}
+
+// GetReferralLinksByReferrer lists the referrer's links with the applications each one brought in
+func (r *referralRepository) GetReferralLinksByReferrer(referrerID uuid.UUID) ([]models.ReferralLink, error) {
+	query := `
+		SELECT l.id, l.code, l.job_id, j.title, l.referrer_id, l.created_at
+		FROM referral_links l
+		INNER JOIN jobs j ON j.id = l.job_id
+		WHERE l.referrer_id = $1
+		ORDER BY l.created_at DESC
+	`
+	rows, err := r.db.Query(query, referrerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.ReferralLink
+	for rows.Next() {
+		var link models.ReferralLink
+		err := rows.Scan(&link.ID, &link.Code, &link.JobID, &link.JobTitle, &link.ReferrerID, &link.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// The referrer only learns who applied once the applicant agreed to it or the application
+	// has at some point reached the job's reveal stage, the same stage blind hiring reveals at.
+	// Until then and for purged accounts the name is NULL and the candidate alias is shown.
+	query = `
+		SELECT a.id, CASE WHEN a.anonymized_at IS NULL AND (a.share_name_with_referrer OR EXISTS (
+				   SELECT 1 FROM application_status_history h
+				   WHERE h.application_id = a.id
+					 AND array_position($2::text[], h.to_status) >= array_position($2::text[], j.blind_until_status)
+			   )) THEN u.full_name END,
+			   a.status, a.applied_at
+		FROM applications a
+		INNER JOIN users u ON u.id = a.applicant_id
+		INNER JOIN jobs j ON j.id = a.job_id
+		WHERE a.referral_link_id = $1
+		ORDER BY a.applied_at DESC
+	`
+	for i := range links {
+		referrals, err := r.getReferrals(query, links[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get referrals for link %s: %w", links[i].ID, err)
+		}
+		links[i].Referrals = referrals
+	}
+
+	return links, nil
+}
+
+func (r *referralRepository) getReferrals(query string, linkID uuid.UUID) ([]models.Referral, error) {
+	rows, err := r.db.Query(query, linkID, pq.Array(models.ApplicationPipeline))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var referrals []models.Referral
+	for rows.Next() {
+		var referral models.Referral
+		var name sql.NullString
+		err := rows.Scan(&referral.ApplicationID, &name, &referral.Status, &referral.AppliedAt)
+		if err != nil {
+			return nil, err
+		}
+		referral.ApplicantName = models.CandidateAlias(referral.ApplicationID)
+		if name.Valid {
+			referral.ApplicantName = name.String
+			referral.NameRevealed = true
+		}
+		referrals = append(referrals, referral)
+	}
+
+	return referrals, rows.Err()
+}
+
+// SavePendingReferral remembers a referral link followed by a user before they applied.
+// Unknown codes and the referrer's own links are ignored.
+func (r *referralRepository) SavePendingReferral(userID uuid.UUID, code string) error {
+	query := `
+		INSERT INTO pending_referrals (user_id, referral_link_id)
+		SELECT $1, id FROM referral_links WHERE code = $2 AND referrer_id <> $1
+		ON CONFLICT (user_id, referral_link_id) DO NOTHING
+	`
+	_, err := r.db.Exec(query, userID, code)
+	if err != nil {
+		return fmt.Errorf("failed to save pending referral: %w", err)
+	}
+	return nil
+}
+
+// newReferralCode returns a short random code that is safe to put in a URL
+func newReferralCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)), nil
+}
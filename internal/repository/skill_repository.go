@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// SkillRepository manages the shared skills table. Users only ever suggest names;
+// admins decide which of them become skills and arrange them into categories, parent
+// skills and aliases.
+type SkillRepository interface {
+	GetSkillSuggestions() ([]models.SkillSuggestion, error)
+	ApproveSkillSuggestion(name string) (*models.Skill, error)
+	RejectSkillSuggestion(name string) error
+
+	// Taxonomy
+	GetSkillTaxonomy() ([]models.SkillCategory, error)
+	GetSkillRequirements(names []string) ([]models.SkillRequirement, error)
+	CreateSkill(req dto.SkillRequest) (*models.Skill, error)
+	UpdateSkill(id int, req dto.SkillRequest) (*models.Skill, error)
+	DeleteSkill(id int, mergeInto *int) error
+	CreateSkillCategory(name string) (*models.SkillCategory, error)
+	UpdateSkillCategory(id int, name string) (*models.SkillCategory, error)
+	DeleteSkillCategory(id int) error
+}
+
+type skillRepository struct {
+	db *sql.DB
+}
+
+func NewSkillRepository(db *sql.DB) SkillRepository {
+	return &skillRepository{db: db}
+}
+
+// GetSkillSuggestions groups pending suggestions by name, most requested first
+func (r *skillRepository) GetSkillSuggestions() ([]models.SkillSuggestion, error) {
+	query := `
+		SELECT min(name), count(*), min(created_at)
+		FROM skill_suggestions
+		WHERE status = 'pending'
+		GROUP BY lower(name)
+		ORDER BY count(*) DESC, min(created_at)
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skill suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []models.SkillSuggestion{}
+	for rows.Next() {
+		var s models.SkillSuggestion
+		if err := rows.Scan(&s.Name, &s.Requesters, &s.FirstSuggestedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan skill suggestion: %w", err)
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// ApproveSkillSuggestion adds the skill (or finds it by name or alias, ignoring case) and gives it to everyone who suggested it
+func (r *skillRepository) ApproveSkillSuggestion(name string) (*models.Skill, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var pending int
+	err = tx.QueryRow(`SELECT count(*) FROM skill_suggestions WHERE lower(name) = lower($1) AND status = 'pending'`, name).Scan(&pending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skill suggestion: %w", err)
+	}
+	if pending == 0 {
+		return nil, fmt.Errorf("skill suggestion %s not found", name)
+	}
+
+	var skill models.Skill
+	err = tx.QueryRow(`
+		SELECT s.id, s.name FROM skills s
+		INNER JOIN `+skillNames+` n ON n.skill_id = s.id
+		WHERE n.name = lower($1)
+	`, name).Scan(&skill.ID, &skill.Name)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(`INSERT INTO skills (name) VALUES ($1) RETURNING id, name`, name).Scan(&skill.ID, &skill.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO user_skills (user_id, skill_id)
+		SELECT user_id, $2 FROM skill_suggestions
+		WHERE lower(name) = lower($1) AND status = 'pending'
+		ON CONFLICT (user_id, skill_id) DO NOTHING
+	`, name, skill.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add skill to users: %w", err)
+	}
+
+	if err := reviewSkillSuggestion(tx, name, "approved"); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &skill, nil
+}
+
+func (r *skillRepository) RejectSkillSuggestion(name string) error {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := reviewSkillSuggestion(tx, name, "rejected"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func reviewSkillSuggestion(tx *sql.Tx, name, status string) error {
+	result, err := tx.Exec(`
+		UPDATE skill_suggestions SET status = $2, reviewed_at = now()
+		WHERE lower(name) = lower($1) AND status = 'pending'
+	`, name, status)
+	if err != nil {
+		return fmt.Errorf("failed to review skill suggestion: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("skill suggestion %s not found", name)
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/lib/pq"
+)
+
+// skillNames is every name a skill goes by, its own and its aliases, lowercased
+const skillNames = `(
+		SELECT id AS skill_id, lower(name) AS name FROM skills
+		UNION ALL
+		SELECT skill_id, lower(alias) FROM skill_aliases
+	)`
+
+// likeEscaper makes % and _ match themselves in a LIKE pattern, backslash being its escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// skillColumns selects a skill of skills s with the aliases joined as a, grouped by s.id
+const skillColumns = `s.id, s.name, s.category_id, s.parent_id,
+		COALESCE(array_agg(a.alias ORDER BY lower(a.alias)) FILTER (WHERE a.alias IS NOT NULL), '{}')`
+
+// resolveSkillNames looks up skills by their name or one of their aliases, ignoring case. The
+// skills are keyed by the lowercased name asked for, names no skill goes by are left out.
+func resolveSkillNames(q queryer, names []string) (map[string]models.Skill, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	rows, err := q.Query(`
+		SELECT n.name, s.id, s.name
+		FROM `+skillNames+` n
+		INNER JOIN skills s ON s.id = n.skill_id
+		WHERE n.name = ANY($1)
+	`, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up skills: %w", err)
+	}
+	defer rows.Close()
+
+	skills := map[string]models.Skill{}
+	for rows.Next() {
+		var name string
+		var skill models.Skill
+		if err := rows.Scan(&name, &skill.ID, &skill.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan skill: %w", err)
+		}
+		skills[name] = skill
+	}
+	return skills, rows.Err()
+}
+
+// GetSkillRequirements resolves the names or aliases of skills looked for, each with the IDs of
+// the skill and every skill below it, which count for it as well
+func (r *skillRepository) GetSkillRequirements(names []string) ([]models.SkillRequirement, error) {
+	known, err := resolveSkillNames(r.db, names)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := make([]models.SkillRequirement, len(names))
+	ids := make([]int64, len(names))
+	for i, name := range names {
+		skill, ok := known[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("skill %s not found", name)
+		}
+		requirements[i].Skill = skill
+		ids[i] = int64(skill.ID)
+	}
+
+	rows, err := r.db.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT id AS root, id FROM skills WHERE id = ANY($1)
+			UNION
+			SELECT t.root, s.id FROM skills s INNER JOIN tree t ON s.parent_id = t.id
+		)
+		SELECT root, id FROM tree
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get narrower skills: %w", err)
+	}
+	defer rows.Close()
+
+	covers := map[int][]int{}
+	for rows.Next() {
+		var root, id int
+		if err := rows.Scan(&root, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan skill: %w", err)
+		}
+		covers[root] = append(covers[root], id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range requirements {
+		requirements[i].Covers = covers[requirements[i].Skill.ID]
+	}
+	return requirements, nil
+}
+
+// GetSkillTaxonomy returns every category with its skills nested under their parents
+func (r *skillRepository) GetSkillTaxonomy() ([]models.SkillCategory, error) {
+	rows, err := r.db.Query(`SELECT id, name FROM skill_categories ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skill categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []models.SkillCategory
+	for rows.Next() {
+		var category models.SkillCategory
+		if err := rows.Scan(&category.ID, &category.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan skill category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(`
+		SELECT ` + skillColumns + `
+		FROM skills s
+		LEFT JOIN skill_aliases a ON a.skill_id = s.id
+		GROUP BY s.id
+		ORDER BY s.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %w", err)
+	}
+	defer rows.Close()
+
+	var skills []models.Skill
+	for rows.Next() {
+		skill, err := scanSkill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan skill: %w", err)
+		}
+		skills = append(skills, *skill)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.BuildSkillTaxonomy(categories, skills), nil
+}
+
+// CreateSkill adds a skill with its place in the taxonomy and its aliases
+func (r *skillRepository) CreateSkill(req dto.SkillRequest) (*models.Skill, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSkill(tx, 0, req); err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO skills (name, category_id, parent_id)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, strings.TrimSpace(req.Name), req.CategoryID, req.ParentID).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+	if err := replaceSkillAliases(tx, id, req); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return getSkill(r.db, id)
+}
+
+// UpdateSkill renames, recategorizes or moves a skill and replaces its aliases
+func (r *skillRepository) UpdateSkill(id int, req dto.SkillRequest) (*models.Skill, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSkill(tx, id, req); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE skills SET name = $2, category_id = $3, parent_id = $4
+		WHERE id = $1
+	`, id, strings.TrimSpace(req.Name), req.CategoryID, req.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update skill: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("skill with ID %d not found", id)
+	}
+	if err := replaceSkillAliases(tx, id, req); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return getSkill(r.db, id)
+}
+
+// DeleteSkill removes a skill, its children move up to the top level. Without mergeInto the
+// skill is taken off every profile; with it, users get that skill instead, the deleted skill's
+// name and aliases become aliases of it and its children move under it. A mergeInto below the
+// deleted skill first takes the deleted skill's place, so the children do not end up below
+// themselves.
+func (r *skillRepository) DeleteSkill(id int, mergeInto *int) error {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if mergeInto != nil {
+		if *mergeInto == id {
+			return fmt.Errorf("a skill cannot be merged into itself")
+		}
+		if err := checkExists(tx, `SELECT EXISTS (SELECT 1 FROM skills WHERE id = $1)`, *mergeInto, "skill %d to merge into does not exist"); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM skills WHERE id = $2
+				UNION
+				SELECT s.id, s.parent_id FROM skills s INNER JOIN ancestors a ON s.id = a.parent_id
+			)
+			UPDATE skills SET parent_id = (SELECT parent_id FROM skills WHERE id = $1)
+			WHERE id = $2 AND EXISTS (SELECT 1 FROM ancestors WHERE id = $1)
+		`, id, *mergeInto)
+		if err != nil {
+			return fmt.Errorf("failed to move skill to merge into: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO user_skills (user_id, skill_id, proficiency, years_used, last_used_year, experience_ids, project_ids, visibility)
+			SELECT user_id, $2, proficiency, years_used, last_used_year, experience_ids, project_ids, visibility
+			FROM user_skills WHERE skill_id = $1
+			ON CONFLICT (user_id, skill_id) DO NOTHING
+		`, id, *mergeInto)
+		if err != nil {
+			return fmt.Errorf("failed to move skill to users: %w", err)
+		}
+		if _, err = tx.Exec(`UPDATE skill_aliases SET skill_id = $2 WHERE skill_id = $1`, id, *mergeInto); err != nil {
+			return fmt.Errorf("failed to move aliases: %w", err)
+		}
+		if _, err = tx.Exec(`INSERT INTO skill_aliases (alias, skill_id) SELECT name, $2 FROM skills WHERE id = $1`, id, *mergeInto); err != nil {
+			return fmt.Errorf("failed to keep skill name as alias: %w", err)
+		}
+		if _, err = tx.Exec(`UPDATE skills SET parent_id = $2 WHERE parent_id = $1 AND id <> $2`, id, *mergeInto); err != nil {
+			return fmt.Errorf("failed to move child skills: %w", err)
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM skills WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete skill: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("skill with ID %d not found", id)
+	}
+
+	return tx.Commit()
+}
+
+func (r *skillRepository) CreateSkillCategory(name string) (*models.SkillCategory, error) {
+	var category models.SkillCategory
+	err := r.db.QueryRow(`INSERT INTO skill_categories (name) VALUES ($1) RETURNING id, name`, name).Scan(&category.ID, &category.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill category: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *skillRepository) UpdateSkillCategory(id int, name string) (*models.SkillCategory, error) {
+	var category models.SkillCategory
+	err := r.db.QueryRow(`UPDATE skill_categories SET name = $2 WHERE id = $1 RETURNING id, name`, id, name).Scan(&category.ID, &category.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("skill category with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to update skill category: %w", err)
+	}
+	return &category, nil
+}
+
+// DeleteSkillCategory removes a category, its skills stay without one
+func (r *skillRepository) DeleteSkillCategory(id int) error {
+	result, err := r.db.Exec(`DELETE FROM skill_categories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete skill category: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("skill category with ID %d not found", id)
+	}
+	return nil
+}
+
+// checkSkill makes sure the skill's category and parent exist, the parent is not the skill or
+// below it, and no other skill goes by its name or one of its aliases. id is 0 for a new skill.
+func checkSkill(tx *sql.Tx, id int, req dto.SkillRequest) error {
+	if req.CategoryID != nil {
+		if err := checkExists(tx, `SELECT EXISTS (SELECT 1 FROM skill_categories WHERE id = $1)`, *req.CategoryID, "skill category %d does not exist"); err != nil {
+			return err
+		}
+	}
+
+	if req.ParentID != nil {
+		if err := checkExists(tx, `SELECT EXISTS (SELECT 1 FROM skills WHERE id = $1)`, *req.ParentID, "parent skill %d does not exist"); err != nil {
+			return err
+		}
+
+		var cycle bool
+		err := tx.QueryRow(`
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM skills WHERE id = $1
+				UNION
+				SELECT s.id, s.parent_id FROM skills s INNER JOIN ancestors a ON s.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)
+		`, *req.ParentID, id).Scan(&cycle)
+		if err != nil {
+			return fmt.Errorf("failed to check parent skill: %w", err)
+		}
+		if cycle {
+			return fmt.Errorf("a skill cannot be placed under itself or one of its own children")
+		}
+	}
+
+	names := append([]string{req.Name}, req.Aliases...)
+	for i := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(names[i]))
+	}
+	var taken string
+	err := tx.QueryRow(`
+		SELECT name FROM `+skillNames+` n
+		WHERE name = ANY($1) AND skill_id <> $2
+		LIMIT 1
+	`, pq.Array(names), id).Scan(&taken)
+	if err == nil {
+		return fmt.Errorf("%s is already taken by another skill", taken)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check skill names: %w", err)
+	}
+	return nil
+}
+
+func checkExists(tx *sql.Tx, query string, id int, message string) error {
+	var exists bool
+	if err := tx.QueryRow(query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up %d: %w", id, err)
+	}
+	if !exists {
+		return fmt.Errorf(message, id)
+	}
+	return nil
+}
+
+// replaceSkillAliases sets the skill's aliases, leaving out repeats and the skill's own name
+func replaceSkillAliases(tx *sql.Tx, id int, req dto.SkillRequest) error {
+	if _, err := tx.Exec(`DELETE FROM skill_aliases WHERE skill_id = $1`, id); err != nil {
+		return fmt.Errorf("failed to clear aliases: %w", err)
+	}
+
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(req.Name)): true}
+	for _, alias := range req.Aliases {
+		alias = strings.TrimSpace(alias)
+		if seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+
+		if _, err := tx.Exec(`INSERT INTO skill_aliases (alias, skill_id) VALUES ($1, $2)`, alias, id); err != nil {
+			return fmt.Errorf("failed to add alias %s: %w", alias, err)
+		}
+	}
+	return nil
+}
+
+func getSkill(q rowQueryer, id int) (*models.Skill, error) {
+	skill, err := scanSkill(q.QueryRow(`
+		SELECT `+skillColumns+`
+		FROM skills s
+		LEFT JOIN skill_aliases a ON a.skill_id = s.id
+		WHERE s.id = $1
+		GROUP BY s.id
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("skill with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get skill: %w", err)
+	}
+	return skill, nil
+}
+
+func scanSkill(row interface{ Scan(dest ...any) error }) (*models.Skill, error) {
+	var skill models.Skill
+	err := row.Scan(&skill.ID, &skill.Name, &skill.CategoryID, &skill.ParentID, pq.Array(&skill.Aliases))
+	if err != nil {
+		return nil, err
+	}
+	return &skill, nil
+}
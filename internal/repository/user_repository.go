@@ -4,10 +4,13 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
 	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -15,15 +18,31 @@ type UserRepository interface {
 	CreateUser(req dto.CreateUserRequest, role string) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(id uuid.UUID) (*models.User, error)
-	GetUserProfileByID(id uuid.UUID) (*models.UserProfile, error)
-	GetUserPhoneNumbersByID(userID uuid.UUID) ([]models.UserPhoneNumber, error)
-	GetUserEducationByID(userID uuid.UUID) ([]models.UserEducation, error)
-	GetUserExperienceByID(userID uuid.UUID) ([]models.UserExperience, error)
-	GetUserCertificationsByID(userID uuid.UUID) ([]models.UserCertification, error)
-	GetUserProjectsByID(userID uuid.UUID) ([]models.UserProject, error)
-	GetUserSkillsByID(userID uuid.UUID) ([]models.Skill, error)
+	GetRecruiterByUserID(userID uuid.UUID) (*models.Recruiter, error)
+	UpdateUserProfile(userID uuid.UUID, req dto.UpdateProfileRequest) (*models.User, error)
+	SetProfilePicture(userID uuid.UUID, key *string) (*string, error)
+	GetUserProfileByID(id uuid.UUID, viewer models.ProfileViewer) (*models.UserProfile, error)
+	GetUserProfilesByIDs(ids []uuid.UUID, viewer models.ProfileViewer) (map[uuid.UUID]*models.UserProfile, error)
+	GetUserPhoneNumbersByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserPhoneNumber, error)
+	GetUserEducationByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserEducation, error)
+	GetUserExperienceByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserExperience, error)
+	GetUserCertificationsByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserCertification, error)
+	GetUserProjectsByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserProject, error)
+	GetUserSkillsByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.Skill, error)
 	GetSkillsByName(name string) ([]models.Skill, error)
 
+	// Visibility
+	GetProfileVisibility(userID uuid.UUID) (*models.ProfileVisibility, error)
+	UpdateProfileVisibility(userID uuid.UUID, req dto.UpdateProfileVisibilityRequest) (*models.ProfileVisibility, error)
+	SetEntryVisibility(userID uuid.UUID, section, entryID string, visibility *string) error
+	GetPublicProfile(slug string, viewer models.ProfileViewer) (*models.UserProfile, error)
+	GetProfilePicture(userID uuid.UUID, viewer models.ProfileViewer) (*string, error)
+
+	// History
+	GetProfileHistory(userID uuid.UUID, section string, before *time.Time, limit int) ([]models.ProfileChange, error)
+	RestoreProfileEntry(userID, changeID uuid.UUID) (*models.ProfileChange, error)
+	GetProfileAsOf(userID uuid.UUID, asOf time.Time) (*models.UserProfile, error)
+
 	// Phone Numbers
 	CreatePhoneNumber(userID uuid.UUID, req dto.CreatePhoneNumberRequest) (*models.UserPhoneNumber, error)
 	UpdatePhoneNumber(userID, phoneID uuid.UUID, req dto.CreatePhoneNumberRequest) (*models.UserPhoneNumber, error)
@@ -50,14 +69,41 @@ type UserRepository interface {
 	DeleteProject(userID, projectID uuid.UUID) error
 
 	// Skills
-	AddUserSkills(userID uuid.UUID, skillIDs []int) error
+	AddUserSkills(userID uuid.UUID, req dto.AddSkillsRequest) error
+	UpdateUserSkill(userID uuid.UUID, skillID int, details dto.UserSkillDetails) error
 	RemoveUserSkill(userID uuid.UUID, skillID int) error
+
+	// Media
+	CreateMedia(userID uuid.UUID, entity string, entityID uuid.UUID, media models.UserMedia) (*models.UserMedia, error)
+	GetMedia(userID uuid.UUID, entity string, entityID uuid.UUID) ([]models.UserMedia, error)
+	GetMediaByID(mediaID uuid.UUID) (*models.UserMedia, error)
+	DeleteMedia(userID, mediaID uuid.UUID) (*models.UserMedia, error)
+
+	// Import
+	ImportProfile(userID uuid.UUID, data dto.ProfileImport, mode string, preview bool) (*models.ProfileImportResult, error)
+
+	// Login history
+	RecordLogin(userID uuid.UUID, ipAddress, userAgent string) error
+	GetLoginHistory(userID uuid.UUID) ([]models.LoginEvent, error)
 }
 
 type userRepository struct {
 	db *sql.DB
 }
 
+// rowQueryer is satisfied by both *sql.DB and *sql.Tx, so writes can run inside an import transaction
+type rowQueryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// nullIfEmpty sends an omitted optional date as NULL rather than an empty string
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 func NewUserRepository(db *sql.DB) UserRepository {
 	return &userRepository{db: db}
 }
@@ -101,81 +147,162 @@ func (r *userRepository) CreateUser(req dto.CreateUserRequest, role string) (*mo
 	return &user, nil
 }
 
-func (r *userRepository) GetUserByEmail(email string) (*models.User, error) {
+const userColumns = `id, email, password_hash, full_name, location, title, about_section, profile_picture,
+			   role, created_at, updated_at, deletion_scheduled_for`
+
+func scanUser(row interface{ Scan(dest ...any) error }) (*models.User, error) {
 	var user models.User
-	query := `
-		SELECT id, email, password_hash, full_name, role, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+	err := row.Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Location, &user.Title,
+		&user.AboutSection, &user.ProfilePicture, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.DeletionScheduledFor,
 	)
 	if err != nil {
 		return nil, err
 	}
+
+	// The key stays private, pictures are served by user
+	if user.ProfilePicture != nil {
+		url := "/api/v1/users/" + user.ID.String() + "/picture"
+		user.ProfilePictureURL = &url
+	}
 	return &user, nil
 }
 
+func (r *userRepository) GetUserByEmail(email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return scanUser(r.db.QueryRow(query, email))
+}
+
 func (r *userRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
-	var user models.User
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.db.QueryRow(query, id))
+}
+
+// UpdateUserProfile changes the basic profile fields that were given. An empty location,
+// title or about section clears it.
+func (r *userRepository) UpdateUserProfile(userID uuid.UUID, req dto.UpdateProfileRequest) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, role, created_at, updated_at
-		FROM users
+		UPDATE users
+		SET full_name = COALESCE($2, full_name),
+			location = CASE WHEN $3::text IS NULL THEN location ELSE NULLIF($3, '') END,
+			title = CASE WHEN $4::text IS NULL THEN title ELSE NULLIF($4, '') END,
+			about_section = CASE WHEN $5::text IS NULL THEN about_section ELSE NULLIF($5, '') END,
+			updated_at = NOW()
 		WHERE id = $1
-	`
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.FullName, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.db.QueryRow(query, userID, req.FullName, req.Location, req.Title, req.AboutSection))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with ID %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	return user, nil
+}
+
+// SetProfilePicture points the user at a new picture, or at none when key is nil, and returns
+// the key of the previous one so the caller can delete it
+func (r *userRepository) SetProfilePicture(userID uuid.UUID, key *string) (*string, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous *string
+	err = tx.QueryRow(`SELECT profile_picture FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&previous)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with ID %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to get profile picture: %w", err)
+	}
+
+	if _, err = tx.Exec(`UPDATE users SET profile_picture = $2, updated_at = NOW() WHERE id = $1`, userID, key); err != nil {
+		return nil, fmt.Errorf("failed to update profile picture: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return previous, nil
+}
+
+func (r *userRepository) GetRecruiterByUserID(userID uuid.UUID) (*models.Recruiter, error) {
+	user, err := r.GetUserByID(userID)
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+
+	recruiter := models.Recruiter{User: *user}
+	query := `SELECT company_id, is_company_owner FROM recruiters WHERE user_id = $1`
+	err = r.db.QueryRow(query, userID).Scan(&recruiter.CompanyID, &recruiter.IsCompanyOwner)
+	if err != nil {
+		return nil, err
+	}
+	return &recruiter, nil
 }
 
-func (r *userRepository) GetUserProfileByID(id uuid.UUID) (*models.UserProfile, error) {
+func (r *userRepository) GetUserProfileByID(id uuid.UUID, viewer models.ProfileViewer) (*models.UserProfile, error) {
+	return r.getUserProfile(id, viewer, nil)
+}
+
+// getUserProfile reads the profile as it is now, or with asOf as the change log had it then
+func (r *userRepository) getUserProfile(id uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) (*models.UserProfile, error) {
 	// Get the base user information
 	user, err := r.GetUserByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	if user.ProfilePicture != nil && viewer != models.ViewerOwner {
+		if _, err := r.GetProfilePicture(id, viewer); err != nil {
+			if !strings.Contains(err.Error(), "not found") {
+				return nil, err
+			}
+			user.ProfilePicture, user.ProfilePictureURL = nil, nil
+		}
+	}
+
 	profile := &models.UserProfile{
 		User: *user,
 	}
 
 	// Get all related data concurrently or sequentially
-	phoneNumbers, err := r.GetUserPhoneNumbersByID(id)
+	phoneNumbers, err := r.getUserPhoneNumbers(id, viewer, asOf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get phone numbers: %w", err)
 	}
 	profile.PhoneNumbers = phoneNumbers
 
-	education, err := r.GetUserEducationByID(id)
+	education, err := r.getUserEducation(id, viewer, asOf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get education: %w", err)
 	}
 	profile.Education = education
 
-	experience, err := r.GetUserExperienceByID(id)
+	experience, err := r.getUserExperience(id, viewer, asOf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get experience: %w", err)
 	}
 	profile.Experience = experience
 
-	certifications, err := r.GetUserCertificationsByID(id)
+	certifications, err := r.getUserCertifications(id, viewer, asOf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get certifications: %w", err)
 	}
 	profile.Certifications = certifications
 
-	projects, err := r.GetUserProjectsByID(id)
+	projects, err := r.getUserProjects(id, viewer, asOf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
 	profile.Projects = projects
 
-	skills, err := r.GetUserSkillsByID(id)
+	skills, err := r.getUserSkills(id, viewer, asOf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get skills: %w", err)
 	}
@@ -184,15 +311,19 @@ func (r *userRepository) GetUserProfileByID(id uuid.UUID) (*models.UserProfile,
 	return profile, nil
 }
 
-func (r *userRepository) GetUserPhoneNumbersByID(userID uuid.UUID) ([]models.UserPhoneNumber, error) {
+func (r *userRepository) GetUserPhoneNumbersByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserPhoneNumber, error) {
+	return r.getUserPhoneNumbers(userID, viewer, nil)
+}
+
+func (r *userRepository) getUserPhoneNumbers(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) ([]models.UserPhoneNumber, error) {
 	query := `
-		SELECT id, user_id, phone_number, phone_type, is_primary, created_at, updated_at
-		FROM user_phone_numbers
-		WHERE user_id = $1
+		SELECT id, user_id, phone_number, phone_type, is_primary, visibility, created_at, updated_at
+		FROM ` + profileEntries(models.ProfileSectionPhoneNumbers, "user_phone_numbers", asOf) + `
+		WHERE user_id = $1 AND ` + visibleTo("user_phone_numbers", models.ProfileSectionPhoneNumbers, 2) + `
 		ORDER BY is_primary DESC, created_at ASC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, profileArgs(userID, viewer, asOf)...)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +334,7 @@ func (r *userRepository) GetUserPhoneNumbersByID(userID uuid.UUID) ([]models.Use
 		var phone models.UserPhoneNumber
 		err := rows.Scan(
 			&phone.ID, &phone.UserID, &phone.PhoneNumber, &phone.PhoneType,
-			&phone.IsPrimary, &phone.CreatedAt, &phone.UpdatedAt,
+			&phone.IsPrimary, &phone.Visibility, &phone.CreatedAt, &phone.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -214,16 +345,20 @@ func (r *userRepository) GetUserPhoneNumbersByID(userID uuid.UUID) ([]models.Use
 	return phoneNumbers, rows.Err()
 }
 
-func (r *userRepository) GetUserEducationByID(userID uuid.UUID) ([]models.UserEducation, error) {
+func (r *userRepository) GetUserEducationByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserEducation, error) {
+	return r.getUserEducation(userID, viewer, nil)
+}
+
+func (r *userRepository) getUserEducation(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) ([]models.UserEducation, error) {
 	query := `
 		SELECT id, user_id, institution_name, degree, field_of_study, start_date, end_date,
-			   is_current, grade_gpa, description, created_at, updated_at
-		FROM user_education
-		WHERE user_id = $1
+			   is_current, grade_gpa, description, visibility, created_at, updated_at
+		FROM ` + profileEntries(models.ProfileSectionEducation, "user_education", asOf) + `
+		WHERE user_id = $1 AND ` + visibleTo("user_education", models.ProfileSectionEducation, 2) + `
 		ORDER BY is_current DESC, end_date DESC NULLS FIRST, start_date DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, profileArgs(userID, viewer, asOf)...)
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +370,7 @@ func (r *userRepository) GetUserEducationByID(userID uuid.UUID) ([]models.UserEd
 		err := rows.Scan(
 			&edu.ID, &edu.UserID, &edu.InstitutionName, &edu.Degree, &edu.FieldOfStudy,
 			&edu.StartDate, &edu.EndDate, &edu.IsCurrent, &edu.GradeGPA, &edu.Description,
-			&edu.CreatedAt, &edu.UpdatedAt,
+			&edu.Visibility, &edu.CreatedAt, &edu.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -254,16 +389,20 @@ func (r *userRepository) GetUserEducationByID(userID uuid.UUID) ([]models.UserEd
 	return education, rows.Err()
 }
 
-func (r *userRepository) GetUserExperienceByID(userID uuid.UUID) ([]models.UserExperience, error) {
+func (r *userRepository) GetUserExperienceByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserExperience, error) {
+	return r.getUserExperience(userID, viewer, nil)
+}
+
+func (r *userRepository) getUserExperience(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) ([]models.UserExperience, error) {
 	query := `
 		SELECT id, user_id, company_name, position_title, employment_type, start_date, end_date,
-			   is_current, location, description, created_at, updated_at
-		FROM user_experience
-		WHERE user_id = $1
+			   is_current, location, description, visibility, created_at, updated_at
+		FROM ` + profileEntries(models.ProfileSectionExperience, "user_experience", asOf) + `
+		WHERE user_id = $1 AND ` + visibleTo("user_experience", models.ProfileSectionExperience, 2) + `
 		ORDER BY is_current DESC, end_date DESC NULLS FIRST, start_date DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, profileArgs(userID, viewer, asOf)...)
 	if err != nil {
 		return nil, err
 	}
@@ -275,7 +414,7 @@ func (r *userRepository) GetUserExperienceByID(userID uuid.UUID) ([]models.UserE
 		err := rows.Scan(
 			&exp.ID, &exp.UserID, &exp.CompanyName, &exp.PositionTitle, &exp.EmploymentType,
 			&exp.StartDate, &exp.EndDate, &exp.IsCurrent, &exp.Location, &exp.Description,
-			&exp.CreatedAt, &exp.UpdatedAt,
+			&exp.Visibility, &exp.CreatedAt, &exp.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -294,16 +433,20 @@ func (r *userRepository) GetUserExperienceByID(userID uuid.UUID) ([]models.UserE
 	return experience, rows.Err()
 }
 
-func (r *userRepository) GetUserCertificationsByID(userID uuid.UUID) ([]models.UserCertification, error) {
+func (r *userRepository) GetUserCertificationsByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserCertification, error) {
+	return r.getUserCertifications(userID, viewer, nil)
+}
+
+func (r *userRepository) getUserCertifications(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) ([]models.UserCertification, error) {
 	query := `
 		SELECT id, user_id, certification_name, issuing_organization, issue_date, expiration_date,
-			   credential_id, credential_url, description, created_at, updated_at
-		FROM user_certifications
-		WHERE user_id = $1
+			   credential_id, credential_url, description, visibility, created_at, updated_at
+		FROM ` + profileEntries(models.ProfileSectionCertifications, "user_certifications", asOf) + `
+		WHERE user_id = $1 AND ` + visibleTo("user_certifications", models.ProfileSectionCertifications, 2) + `
 		ORDER BY issue_date DESC NULLS LAST, created_at DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, profileArgs(userID, viewer, asOf)...)
 	if err != nil {
 		return nil, err
 	}
@@ -315,7 +458,7 @@ func (r *userRepository) GetUserCertificationsByID(userID uuid.UUID) ([]models.U
 		err := rows.Scan(
 			&cert.ID, &cert.UserID, &cert.CertificationName, &cert.IssuingOrganization,
 			&cert.IssueDate, &cert.ExpirationDate, &cert.CredentialID, &cert.CredentialURL,
-			&cert.Description, &cert.CreatedAt, &cert.UpdatedAt,
+			&cert.Description, &cert.Visibility, &cert.CreatedAt, &cert.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -334,16 +477,20 @@ func (r *userRepository) GetUserCertificationsByID(userID uuid.UUID) ([]models.U
 	return certifications, rows.Err()
 }
 
-func (r *userRepository) GetUserProjectsByID(userID uuid.UUID) ([]models.UserProject, error) {
+func (r *userRepository) GetUserProjectsByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.UserProject, error) {
+	return r.getUserProjects(userID, viewer, nil)
+}
+
+func (r *userRepository) getUserProjects(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) ([]models.UserProject, error) {
 	query := `
 		SELECT id, user_id, project_name, description, start_date, end_date, is_ongoing,
-			   project_url, created_at, updated_at
-		FROM user_projects
-		WHERE user_id = $1
+			   project_url, visibility, created_at, updated_at
+		FROM ` + profileEntries(models.ProfileSectionProjects, "user_projects", asOf) + `
+		WHERE user_id = $1 AND ` + visibleTo("user_projects", models.ProfileSectionProjects, 2) + `
 		ORDER BY is_ongoing DESC, end_date DESC NULLS FIRST, start_date DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, profileArgs(userID, viewer, asOf)...)
 	if err != nil {
 		return nil, err
 	}
@@ -354,7 +501,8 @@ func (r *userRepository) GetUserProjectsByID(userID uuid.UUID) ([]models.UserPro
 		var project models.UserProject
 		err := rows.Scan(
 			&project.ID, &project.UserID, &project.ProjectName, &project.Description,
-			&project.StartDate, &project.EndDate, &project.IsOngoing, &project.ProjectURL, &project.CreatedAt, &project.UpdatedAt,
+			&project.StartDate, &project.EndDate, &project.IsOngoing, &project.ProjectURL, &project.Visibility,
+			&project.CreatedAt, &project.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -373,16 +521,20 @@ func (r *userRepository) GetUserProjectsByID(userID uuid.UUID) ([]models.UserPro
 	return projects, rows.Err()
 }
 
-func (r *userRepository) GetUserSkillsByID(userID uuid.UUID) ([]models.Skill, error) {
+func (r *userRepository) GetUserSkillsByID(userID uuid.UUID, viewer models.ProfileViewer) ([]models.Skill, error) {
+	return r.getUserSkills(userID, viewer, nil)
+}
+
+func (r *userRepository) getUserSkills(userID uuid.UUID, viewer models.ProfileViewer, asOf *time.Time) ([]models.Skill, error) {
 	query := `
-		SELECT s.id, s.name
+		SELECT s.id, s.name, us.proficiency, us.years_used, us.last_used_year, us.experience_ids, us.project_ids, us.visibility
 		FROM skills s
-		INNER JOIN user_skills us ON s.id = us.skill_id
-		WHERE us.user_id = $1
+		INNER JOIN ` + profileEntries(models.ProfileSectionSkills, "us", asOf) + ` ON s.id = us.skill_id
+		WHERE us.user_id = $1 AND ` + visibleTo("us", models.ProfileSectionSkills, 2) + `
 		ORDER BY s.name ASC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.Query(query, profileArgs(userID, viewer, asOf)...)
 	if err != nil {
 		return nil, err
 	}
@@ -391,7 +543,8 @@ func (r *userRepository) GetUserSkillsByID(userID uuid.UUID) ([]models.Skill, er
 	var user_skills []models.Skill
 	for rows.Next() {
 		var user_skill models.Skill
-		err := rows.Scan(&user_skill.ID, &user_skill.Name)
+		err := rows.Scan(&user_skill.ID, &user_skill.Name, &user_skill.Proficiency, &user_skill.YearsUsed, &user_skill.LastUsedYear,
+			pq.Array(&user_skill.ExperienceIDs), pq.Array(&user_skill.ProjectIDs), &user_skill.Visibility)
 		if err != nil {
 			return nil, err
 		}
@@ -401,23 +554,31 @@ func (r *userRepository) GetUserSkillsByID(userID uuid.UUID) ([]models.Skill, er
 	return user_skills, rows.Err()
 }
 
-// GetSkillsByName queries the database for user skills matching the given name.
+// GetSkillsByName queries the database for skills whose name or one of whose aliases contains
+// the given name, ignoring case. Skills it is the exact name or alias of come first.
 func (r *userRepository) GetSkillsByName(name string) ([]models.Skill, error) {
 	var skills []models.Skill
 
-	query := `SELECT id, name FROM skills WHERE name LIKE '%$1%' ORDER BY name ASC`
-	rows, err := r.db.Query(query, name)
+	query := `
+		SELECT ` + skillColumns + `
+		FROM skills s
+		LEFT JOIN skill_aliases a ON a.skill_id = s.id
+		GROUP BY s.id
+		HAVING s.name ILIKE '%' || $2 || '%' OR bool_or(a.alias ILIKE '%' || $2 || '%')
+		ORDER BY lower(s.name) = lower($1) OR coalesce(bool_or(lower(a.alias) = lower($1)), false) DESC, s.name ASC
+	`
+	rows, err := r.db.Query(query, name, likeEscaper.Replace(name))
 	if err != nil {
 		return nil, fmt.Errorf("query error: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var skill models.Skill
-		if err := rows.Scan(&skill.ID, &skill.Name); err != nil {
+		skill, err := scanSkill(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan error: %w", err)
 		}
-		skills = append(skills, skill)
+		skills = append(skills, *skill)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -446,9 +607,7 @@ func (r *userRepository) getMediaForProject(projectID uuid.UUID) ([]models.UserM
 
 func (r *userRepository) getMedia(fieldName string, entityID uuid.UUID) ([]models.UserMedia, error) {
 	query := fmt.Sprintf(`
-		SELECT id, user_id, media_type, file_name, file_path, file_size, mime_type,
-			   alt_text, description, education_id, experience_id, certification_id, project_id,
-			   created_at, updated_at
+		SELECT `+mediaColumns+`
 		FROM user_media
 		WHERE %s = $1
 		ORDER BY created_at ASC
@@ -462,29 +621,227 @@ func (r *userRepository) getMedia(fieldName string, entityID uuid.UUID) ([]model
 
 	var media []models.UserMedia
 	for rows.Next() {
-		var m models.UserMedia
-		err := rows.Scan(
-			&m.ID, &m.UserID, &m.MediaType, &m.FileName, &m.FilePath, &m.FileSize,
-			&m.MimeType, &m.AltText, &m.Description, &m.EducationID, &m.ExperienceID,
-			&m.CertificationID, &m.ProjectID, &m.CreatedAt, &m.UpdatedAt,
-		)
+		m, err := scanMedia(rows)
 		if err != nil {
 			return nil, err
 		}
-		media = append(media, m)
+		media = append(media, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachThumbnails(r.db, media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// attachThumbnails loads the thumbnails of all the media in one query
+func attachThumbnails(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, media []models.UserMedia) error {
+	if len(media) == 0 {
+		return nil
+	}
+	ids := make([]string, len(media))
+	byID := map[uuid.UUID]*models.UserMedia{}
+	for i := range media {
+		ids[i] = media[i].ID.String()
+		byID[media[i].ID] = &media[i]
+	}
+
+	rows, err := q.Query(`
+		SELECT media_id, size, file_path, mime_type, file_size, width, height
+		FROM user_media_thumbnails
+		WHERE media_id = ANY($1::uuid[])
+		ORDER BY width DESC
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get thumbnails: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mediaID uuid.UUID
+		var t models.MediaThumbnail
+		if err := rows.Scan(&mediaID, &t.Size, &t.FilePath, &t.MimeType, &t.FileSize, &t.Width, &t.Height); err != nil {
+			return fmt.Errorf("failed to scan thumbnail: %w", err)
+		}
+		if m, ok := byID[mediaID]; ok {
+			m.Thumbnails = append(m.Thumbnails, t)
+		}
+	}
+	return rows.Err()
+}
+
+const mediaColumns = `id, user_id, media_type, file_name, file_path, file_size, mime_type,
+			   alt_text, description, education_id, experience_id, certification_id, project_id,
+			   scan_status, scan_result, scanned_at, created_at, updated_at`
+
+func scanMedia(row interface{ Scan(dest ...any) error }) (*models.UserMedia, error) {
+	var m models.UserMedia
+	err := row.Scan(
+		&m.ID, &m.UserID, &m.MediaType, &m.FileName, &m.FilePath, &m.FileSize,
+		&m.MimeType, &m.AltText, &m.Description, &m.EducationID, &m.ExperienceID,
+		&m.CertificationID, &m.ProjectID, &m.ScanStatus, &m.ScanResult, &m.ScannedAt,
+		&m.CreatedAt, &m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// mediaEntity is the user_media column and table of a profile entry media can be attached to
+type mediaEntity struct {
+	column string
+	table  string
+}
+
+var mediaEntities = map[string]mediaEntity{
+	models.MediaEntityEducation:     {column: "education_id", table: "user_education"},
+	models.MediaEntityExperience:    {column: "experience_id", table: "user_experience"},
+	models.MediaEntityCertification: {column: "certification_id", table: "user_certifications"},
+	models.MediaEntityProject:       {column: "project_id", table: "user_projects"},
+}
+
+// CreateMedia records an uploaded file and its thumbnails against one of the user's profile entries
+func (r *userRepository) CreateMedia(userID uuid.UUID, entity string, entityID uuid.UUID, media models.UserMedia) (*models.UserMedia, error) {
+	target, ok := mediaEntities[entity]
+	if !ok {
+		return nil, fmt.Errorf("unknown media entity %q", entity)
+	}
+
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The entry must belong to the user, otherwise nothing is inserted
+	query := `
+		INSERT INTO user_media (user_id, media_type, file_name, file_path, file_size, mime_type, alt_text, description, ` + target.column + `)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, e.id
+		FROM ` + target.table + ` e
+		WHERE e.id = $9 AND e.user_id = $1
+		RETURNING ` + mediaColumns
+
+	created, err := scanMedia(tx.QueryRow(query,
+		userID, media.MediaType, media.FileName, media.FilePath, media.FileSize,
+		media.MimeType, media.AltText, media.Description, entityID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s with ID %s not found", entity, entityID)
+		}
+		return nil, fmt.Errorf("failed to create media: %w", err)
+	}
+
+	for _, t := range media.Thumbnails {
+		_, err := tx.Exec(`
+			INSERT INTO user_media_thumbnails (media_id, size, file_path, mime_type, file_size, width, height)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, created.ID, t.Size, t.FilePath, t.MimeType, t.FileSize, t.Width, t.Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create thumbnail: %w", err)
+		}
+	}
+	created.Thumbnails = media.Thumbnails
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
+// GetMedia lists the media of one of the user's profile entries
+func (r *userRepository) GetMedia(userID uuid.UUID, entity string, entityID uuid.UUID) ([]models.UserMedia, error) {
+	target, ok := mediaEntities[entity]
+	if !ok {
+		return nil, fmt.Errorf("unknown media entity %q", entity)
+	}
+
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM `+target.table+` WHERE id = $1 AND user_id = $2)`, entityID, userID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", entity, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s with ID %s not found", entity, entityID)
+	}
+
+	media, err := r.getMedia(target.column, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	if media == nil {
+		media = []models.UserMedia{}
+	}
+	return media, nil
+}
+
+// GetMediaByID returns one media file with its thumbnails, whoever it belongs to
+func (r *userRepository) GetMediaByID(mediaID uuid.UUID) (*models.UserMedia, error) {
+	media, err := scanMedia(r.db.QueryRow(`SELECT `+mediaColumns+` FROM user_media WHERE id = $1`, mediaID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("media with ID %s not found", mediaID)
+		}
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+
+	found := []models.UserMedia{*media}
+	if err := attachThumbnails(r.db, found); err != nil {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// DeleteMedia removes the record and returns it with its thumbnails, so the caller can delete the stored files
+func (r *userRepository) DeleteMedia(userID, mediaID uuid.UUID) (*models.UserMedia, error) {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Read the thumbnails first, the delete cascades to them
+	found := []models.UserMedia{{ID: mediaID}}
+	if err := attachThumbnails(tx, found); err != nil {
+		return nil, err
+	}
+
+	query := `DELETE FROM user_media WHERE id = $1 AND user_id = $2 RETURNING ` + mediaColumns
+
+	media, err := scanMedia(tx.QueryRow(query, mediaID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("media with ID %s not found", mediaID)
+		}
+		return nil, fmt.Errorf("failed to delete media: %w", err)
 	}
+	media.Thumbnails = found[0].Thumbnails
 
-	return media, rows.Err()
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return media, nil
 }
 
 func (r *userRepository) CreatePhoneNumber(userID uuid.UUID, req dto.CreatePhoneNumberRequest) (*models.UserPhoneNumber, error) {
+	return createPhoneNumber(r.db, userID, req)
+}
+func createPhoneNumber(q rowQueryer, userID uuid.UUID, req dto.CreatePhoneNumberRequest) (*models.UserPhoneNumber, error) {
 	query := `
   INSERT INTO user_phone_numbers (user_id, phone_number, phone_type, is_primary)
   VALUES ($1, $2, $3, $4)
   RETURNING id, user_id, phone_number, phone_type, is_primary, created_at, updated_at
  `
 	var phone models.UserPhoneNumber
-	err := r.db.QueryRow(query, userID, req.PhoneNumber, req.PhoneType, req.IsPrimary).Scan(
+	err := q.QueryRow(query, userID, req.PhoneNumber, req.PhoneType, req.IsPrimary).Scan(
 		&phone.ID, &phone.UserID, &phone.PhoneNumber, &phone.PhoneType,
 		&phone.IsPrimary, &phone.CreatedAt, &phone.UpdatedAt,
 	)
@@ -495,6 +852,9 @@ func (r *userRepository) CreatePhoneNumber(userID uuid.UUID, req dto.CreatePhone
 }
 
 func (r *userRepository) UpdatePhoneNumber(userID, phoneID uuid.UUID, req dto.CreatePhoneNumberRequest) (*models.UserPhoneNumber, error) {
+	return updatePhoneNumber(r.db, userID, phoneID, req)
+}
+func updatePhoneNumber(q rowQueryer, userID, phoneID uuid.UUID, req dto.CreatePhoneNumberRequest) (*models.UserPhoneNumber, error) {
 	query := `
   UPDATE user_phone_numbers
   SET phone_number = $1, phone_type = $2, is_primary = $3, updated_at = NOW()
@@ -502,7 +862,7 @@ func (r *userRepository) UpdatePhoneNumber(userID, phoneID uuid.UUID, req dto.Cr
   RETURNING id, user_id, phone_number, phone_type, is_primary, created_at, updated_at
  `
 	var phone models.UserPhoneNumber
-	err := r.db.QueryRow(query, req.PhoneNumber, req.PhoneType, req.IsPrimary, userID, phoneID).Scan(
+	err := q.QueryRow(query, req.PhoneNumber, req.PhoneType, req.IsPrimary, userID, phoneID).Scan(
 		&phone.ID, &phone.UserID, &phone.PhoneNumber, &phone.PhoneType,
 		&phone.IsPrimary, &phone.CreatedAt, &phone.UpdatedAt,
 	)
@@ -533,6 +893,9 @@ func (r *userRepository) DeletePhoneNumber(userID, phoneID uuid.UUID) error {
 	return nil
 }
 func (r *userRepository) CreateEducation(userID uuid.UUID, req dto.CreateEducationRequest) (*models.UserEducation, error) {
+	return createEducation(r.db, userID, req)
+}
+func createEducation(q rowQueryer, userID uuid.UUID, req dto.CreateEducationRequest) (*models.UserEducation, error) {
 	query := `
   INSERT INTO user_education (user_id, institution_name, degree, field_of_study,
 	  start_date, end_date, is_current, grade_gpa, description)
@@ -542,9 +905,9 @@ func (r *userRepository) CreateEducation(userID uuid.UUID, req dto.CreateEducati
 	  created_at, updated_at
  `
 	var edu models.UserEducation
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		userID, req.InstitutionName, req.Degree, req.FieldOfStudy,
-		req.StartDate, req.EndDate, req.IsCurrent, req.GradeGPA,
+		nullIfEmpty(req.StartDate), nullIfEmpty(req.EndDate), req.IsCurrent, req.GradeGPA,
 		req.Description).Scan(
 		&edu.ID, &edu.UserID, &edu.InstitutionName, &edu.Degree,
 		&edu.FieldOfStudy, &edu.StartDate, &edu.EndDate,
@@ -557,6 +920,9 @@ func (r *userRepository) CreateEducation(userID uuid.UUID, req dto.CreateEducati
 	return &edu, nil
 }
 func (r *userRepository) UpdateEducation(userID, educationID uuid.UUID, req dto.CreateEducationRequest) (*models.UserEducation, error) {
+	return updateEducation(r.db, userID, educationID, req)
+}
+func updateEducation(q rowQueryer, userID, educationID uuid.UUID, req dto.CreateEducationRequest) (*models.UserEducation, error) {
 	query := `
   UPDATE user_education
   SET institution_name = $1, degree = $2, field_of_study = $3,
@@ -568,9 +934,9 @@ func (r *userRepository) UpdateEducation(userID, educationID uuid.UUID, req dto.
 	  created_at, updated_at
  `
 	var edu models.UserEducation
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		req.InstitutionName, req.Degree, req.FieldOfStudy,
-		req.StartDate, req.EndDate, req.IsCurrent, req.GradeGPA,
+		nullIfEmpty(req.StartDate), nullIfEmpty(req.EndDate), req.IsCurrent, req.GradeGPA,
 		req.Description, userID, educationID).Scan(
 		&edu.ID, &edu.UserID, &edu.InstitutionName, &edu.Degree,
 		&edu.FieldOfStudy, &edu.StartDate, &edu.EndDate,
@@ -604,6 +970,9 @@ func (r *userRepository) DeleteEducation(userID, educationID uuid.UUID) error {
 	return nil
 }
 func (r *userRepository) CreateExperience(userID uuid.UUID, req dto.CreateExperienceRequest) (*models.UserExperience, error) {
+	return createExperience(r.db, userID, req)
+}
+func createExperience(q rowQueryer, userID uuid.UUID, req dto.CreateExperienceRequest) (*models.UserExperience, error) {
 	query := `
   INSERT INTO user_experience (user_id, company_name, position_title, employment_type,
    start_date, end_date, is_current, location, description)
@@ -613,9 +982,9 @@ func (r *userRepository) CreateExperience(userID uuid.UUID, req dto.CreateExperi
    created_at, updated_at
  `
 	var exp models.UserExperience
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		userID, req.CompanyName, req.PositionTitle, req.EmploymentType,
-		req.StartDate, req.EndDate, req.IsCurrent, req.Location,
+		nullIfEmpty(req.StartDate), nullIfEmpty(req.EndDate), req.IsCurrent, req.Location,
 		req.Description).Scan(
 		&exp.ID, &exp.UserID, &exp.CompanyName, &exp.PositionTitle,
 		&exp.EmploymentType, &exp.StartDate, &exp.EndDate,
@@ -628,6 +997,9 @@ func (r *userRepository) CreateExperience(userID uuid.UUID, req dto.CreateExperi
 	return &exp, nil
 }
 func (r *userRepository) UpdateExperience(userID, experienceID uuid.UUID, req dto.CreateExperienceRequest) (*models.UserExperience, error) {
+	return updateExperience(r.db, userID, experienceID, req)
+}
+func updateExperience(q rowQueryer, userID, experienceID uuid.UUID, req dto.CreateExperienceRequest) (*models.UserExperience, error) {
 	query := `
   UPDATE user_experience
   SET company_name = $1, position_title = $2, employment_type = $3,
@@ -639,9 +1011,9 @@ func (r *userRepository) UpdateExperience(userID, experienceID uuid.UUID, req dt
    created_at, updated_at
  `
 	var exp models.UserExperience
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		req.CompanyName, req.PositionTitle, req.EmploymentType,
-		req.StartDate, req.EndDate, req.IsCurrent, req.Location,
+		nullIfEmpty(req.StartDate), nullIfEmpty(req.EndDate), req.IsCurrent, req.Location,
 		req.Description, userID, experienceID).Scan(
 		&exp.ID, &exp.UserID, &exp.CompanyName, &exp.PositionTitle,
 		&exp.EmploymentType, &exp.StartDate, &exp.EndDate,
@@ -675,6 +1047,9 @@ func (r *userRepository) DeleteExperience(userID, experienceID uuid.UUID) error
 	return nil
 }
 func (r *userRepository) CreateCertification(userID uuid.UUID, req dto.CreateCertificationRequest) (*models.UserCertification, error) {
+	return createCertification(r.db, userID, req)
+}
+func createCertification(q rowQueryer, userID uuid.UUID, req dto.CreateCertificationRequest) (*models.UserCertification, error) {
 	query := `
   INSERT INTO user_certifications (user_id, certification_name, issuing_organization,
    issue_date, expiration_date, credential_id, credential_url, description)
@@ -684,9 +1059,9 @@ func (r *userRepository) CreateCertification(userID uuid.UUID, req dto.CreateCer
    description, created_at, updated_at
  `
 	var cert models.UserCertification
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		userID, req.CertificationName, req.IssuingOrganization,
-		req.IssueDate, req.ExpirationDate, req.CredentialID,
+		nullIfEmpty(req.IssueDate), nullIfEmpty(req.ExpirationDate), req.CredentialID,
 		req.CredentialURL, req.Description).Scan(
 		&cert.ID, &cert.UserID, &cert.CertificationName,
 		&cert.IssuingOrganization, &cert.IssueDate,
@@ -700,6 +1075,9 @@ func (r *userRepository) CreateCertification(userID uuid.UUID, req dto.CreateCer
 	return &cert, nil
 }
 func (r *userRepository) UpdateCertification(userID, certificationID uuid.UUID, req dto.CreateCertificationRequest) (*models.UserCertification, error) {
+	return updateCertification(r.db, userID, certificationID, req)
+}
+func updateCertification(q rowQueryer, userID, certificationID uuid.UUID, req dto.CreateCertificationRequest) (*models.UserCertification, error) {
 	query := `
   UPDATE user_certifications
   SET certification_name = $1, issuing_organization = $2,
@@ -711,9 +1089,9 @@ func (r *userRepository) UpdateCertification(userID, certificationID uuid.UUID,
    description, created_at, updated_at
  `
 	var cert models.UserCertification
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		req.CertificationName, req.IssuingOrganization,
-		req.IssueDate, req.ExpirationDate, req.CredentialID,
+		nullIfEmpty(req.IssueDate), nullIfEmpty(req.ExpirationDate), req.CredentialID,
 		req.CredentialURL, req.Description, userID, certificationID).Scan(
 		&cert.ID, &cert.UserID, &cert.CertificationName,
 		&cert.IssuingOrganization, &cert.IssueDate,
@@ -748,6 +1126,9 @@ func (r *userRepository) DeleteCertification(userID, certificationID uuid.UUID)
 	return nil
 }
 func (r *userRepository) CreateProject(userID uuid.UUID, req dto.CreateProjectRequest) (*models.UserProject, error) {
+	return createProject(r.db, userID, req)
+}
+func createProject(q rowQueryer, userID uuid.UUID, req dto.CreateProjectRequest) (*models.UserProject, error) {
 	query := `
   INSERT INTO user_projects (user_id, project_name, description, start_date,
    end_date, is_ongoing, project_url)
@@ -757,9 +1138,9 @@ func (r *userRepository) CreateProject(userID uuid.UUID, req dto.CreateProjectRe
    created_at, updated_at
  `
 	var project models.UserProject
-	err := r.db.QueryRow(query,
+	err := q.QueryRow(query,
 		userID, req.ProjectName, req.Description,
-		req.StartDate, req.EndDate, req.IsOngoing,
+		nullIfEmpty(req.StartDate), nullIfEmpty(req.EndDate), req.IsOngoing,
 		req.ProjectURL).Scan(
 		&project.ID, &project.UserID, &project.ProjectName,
 		&project.Description, &project.StartDate,
@@ -773,6 +1154,9 @@ func (r *userRepository) CreateProject(userID uuid.UUID, req dto.CreateProjectRe
 	return &project, nil
 }
 func (r *userRepository) UpdateProject(userID, projectID uuid.UUID, req dto.CreateProjectRequest) (*models.UserProject, error) {
+	return updateProject(r.db, userID, projectID, req)
+}
+func updateProject(q rowQueryer, userID, projectID uuid.UUID, req dto.CreateProjectRequest) (*models.UserProject, error) {
 	query := `
   UPDATE user_projects
   SET project_name = $1, description = $2, start_date = $3,
@@ -784,9 +1168,9 @@ func (r *userRepository) UpdateProject(userID, projectID uuid.UUID, req dto.Crea
    created_at, updated_at
  `
 	var project models.UserProject
-	err := r.db.QueryRow(query,
-		req.ProjectName, req.Description, req.StartDate,
-		req.EndDate, req.IsOngoing, req.ProjectURL,
+	err := q.QueryRow(query,
+		req.ProjectName, req.Description, nullIfEmpty(req.StartDate),
+		nullIfEmpty(req.EndDate), req.IsOngoing, req.ProjectURL,
 		userID, projectID).Scan(
 		&project.ID, &project.UserID, &project.ProjectName,
 		&project.Description, &project.StartDate,
@@ -820,20 +1204,192 @@ func (r *userRepository) DeleteProject(userID, projectID uuid.UUID) error {
 
 	return nil
 }
-func (r *userRepository) AddUserSkills(userID uuid.UUID, skillIDs []int) error {
+
+// AddUserSkills adds skills by ID and by name, names are resolved through the skills' aliases.
+// Skills given with details replace the details of skills the user already has. Nothing is
+// added if one of the names is not a skill or a linked entry is not the user's.
+func (r *userRepository) AddUserSkills(userID uuid.UUID, req dto.AddSkillsRequest) error {
+	names := req.SkillNames
+	for _, skill := range req.Skills {
+		if skill.SkillID == nil {
+			names = append(names, skill.Name)
+		}
+	}
+	known, err := resolveSkillNames(r.db, names)
+	if err != nil {
+		return err
+	}
+	skillID := func(name string) (int, error) {
+		skill, ok := known[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("skill %s not found", name)
+		}
+		return skill.ID, nil
+	}
+
+	plain := req.SkillIDs
+	for _, name := range req.SkillNames {
+		id, err := skillID(name)
+		if err != nil {
+			return err
+		}
+		plain = append(plain, id)
+	}
+
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := append([]int(nil), req.SkillIDs...)
+	for _, skill := range req.Skills {
+		if skill.SkillID != nil {
+			ids = append(ids, *skill.SkillID)
+		}
+	}
+	if err := checkSkillIDs(tx, ids); err != nil {
+		return err
+	}
+
 	query := `
   INSERT INTO user_skills (user_id, skill_id)
   VALUES ($1, $2)
   ON CONFLICT (user_id, skill_id) DO NOTHING
  `
-	for _, skillID := range skillIDs {
-		_, err := r.db.Exec(query, userID, skillID)
+	for _, skillID := range plain {
+		_, err := tx.Exec(query, userID, skillID)
 		if err != nil {
 			return fmt.Errorf("failed to add skill %d for user %s: %w", skillID, userID, err)
 		}
 	}
+
+	for _, skill := range req.Skills {
+		id := 0
+		if skill.SkillID != nil {
+			id = *skill.SkillID
+		} else if id, err = skillID(skill.Name); err != nil {
+			return err
+		}
+		if err := checkSkillEntries(tx, userID, skill.UserSkillDetails); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO user_skills (user_id, skill_id, proficiency, years_used, last_used_year, experience_ids, project_ids)
+			VALUES ($1, $2, $3, $4, $5, COALESCE($6, '{}'::uuid[]), COALESCE($7, '{}'::uuid[]))
+			ON CONFLICT (user_id, skill_id) DO UPDATE SET
+				proficiency = EXCLUDED.proficiency,
+				years_used = EXCLUDED.years_used,
+				last_used_year = EXCLUDED.last_used_year,
+				experience_ids = EXCLUDED.experience_ids,
+				project_ids = EXCLUDED.project_ids
+		`, append([]any{userID, id}, skillDetailArgs(skill.UserSkillDetails)...)...)
+		if err != nil {
+			return fmt.Errorf("failed to add skill %d for user %s: %w", id, userID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateUserSkill replaces how the user used one of their skills
+func (r *userRepository) UpdateUserSkill(userID uuid.UUID, skillID int, details dto.UserSkillDetails) error {
+	// Start transaction
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSkillEntries(tx, userID, details); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE user_skills SET
+			proficiency = $3,
+			years_used = $4,
+			last_used_year = $5,
+			experience_ids = COALESCE($6, '{}'::uuid[]),
+			project_ids = COALESCE($7, '{}'::uuid[])
+		WHERE user_id = $1 AND skill_id = $2
+	`, append([]any{userID, skillID}, skillDetailArgs(details)...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update skill %d for user %s: %w", skillID, userID, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("skill with ID %d not found on the profile", skillID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// skillDetailArgs are the details as query arguments $3 to $7
+func skillDetailArgs(details dto.UserSkillDetails) []any {
+	return []any{details.Proficiency, details.YearsUsed, details.LastUsedYear, pq.Array(details.ExperienceIDs), pq.Array(details.ProjectIDs)}
+}
+
+// checkSkillIDs makes sure every skill ID is a skill
+func checkSkillIDs(tx *sql.Tx, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var missing int
+	err := tx.QueryRow(`
+		SELECT id FROM unnest($1::int[]) id
+		WHERE id NOT IN (SELECT id FROM skills)
+		LIMIT 1
+	`, pq.Array(ids)).Scan(&missing)
+	if err == nil {
+		return fmt.Errorf("skill with ID %d not found", missing)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check skills: %w", err)
+	}
+	return nil
+}
+
+// checkSkillEntries makes sure the experience and project entries a skill is linked to are the user's
+func checkSkillEntries(tx *sql.Tx, userID uuid.UUID, details dto.UserSkillDetails) error {
+	lists := []struct {
+		table, label string
+		ids          []uuid.UUID
+	}{
+		{"user_experience", "experience", details.ExperienceIDs},
+		{"user_projects", "project", details.ProjectIDs},
+	}
+	for _, list := range lists {
+		if len(list.ids) == 0 {
+			continue
+		}
+		var missing uuid.UUID
+		err := tx.QueryRow(`
+			SELECT id FROM unnest($2::uuid[]) id
+			WHERE id NOT IN (SELECT id FROM `+list.table+` WHERE user_id = $1)
+			LIMIT 1
+		`, userID, pq.Array(list.ids)).Scan(&missing)
+		if err == nil {
+			return fmt.Errorf("%s with ID %s not found", list.label, missing)
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check %s entries: %w", list.label, err)
+		}
+	}
 	return nil
 }
+
 func (r *userRepository) RemoveUserSkill(userID uuid.UUID, skillID int) error {
 	query := `
   DELETE FROM user_skills
@@ -855,3 +1411,39 @@ func (r *userRepository) RemoveUserSkill(userID uuid.UUID, skillID int) error {
 
 	return nil
 }
+
+func (r *userRepository) RecordLogin(userID uuid.UUID, ipAddress, userAgent string) error {
+	query := `
+		INSERT INTO login_events (user_id, ip_address, user_agent)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))
+	`
+	_, err := r.db.Exec(query, userID, ipAddress, userAgent)
+	if err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) GetLoginHistory(userID uuid.UUID) ([]models.LoginEvent, error) {
+	query := `
+		SELECT id, user_id, ip_address, user_agent, created_at
+		FROM login_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login history: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.LoginEvent
+	for rows.Next() {
+		var event models.LoginEvent
+		if err := rows.Scan(&event.ID, &event.UserID, &event.IPAddress, &event.UserAgent, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan login event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
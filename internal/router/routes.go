@@ -51,48 +51,125 @@ func setupUserRoutes(router chi.Router, userHandler handlers.UserHandler, jwtSer
 	router.Route("/user", func(user chi.Router) {
 
 		// Public routes (no middleware)
-		user.Post("/login", userHandler.HandleUserLogIn) 
+		user.Post("/login", userHandler.HandleUserLogIn)
+		user.Post("/account/deletion/cancel", userHandler.HandleCancelAccountDeletion) // Cancel a pending deletion with the account's credentials
 
 		// Protected routes (with middleware)
 		user.Group(func(protected chi.Router) {
 			protected.Use(middleware.JWTAuth(&jwtService))
 
 			protected.Route("/profile", func(profile chi.Router) {
-				profile.Get("/", userHandler.HandleGetProfile) // Get current user's profile
+				profile.Get("/", userHandler.HandleGetProfile)                     // Get current user's profile
+				profile.Patch("/", userHandler.HandleUpdateProfile)                // Edit name, location, title and about section
+				profile.Post("/picture", userHandler.HandleUploadProfilePicture)   // Upload a profile picture, cropped to a square
+				profile.Delete("/picture", userHandler.HandleDeleteProfilePicture) // Remove the profile picture
+				profile.Get("/resume", userHandler.HandleGetMyResume)              // Download my profile as a résumé
+
+				// Public link and who sees which sections and entries
+				profile.Get("/visibility", userHandler.HandleGetProfileVisibility)
+				profile.Put("/visibility", userHandler.HandleUpdateProfileVisibility)
+				profile.Put("/visibility/{section}/{entryID}", userHandler.HandleSetEntryVisibility)
+
+				// Change history of the entries below
+				profile.Get("/history", userHandler.HandleGetProfileHistory)                       // List changes, newest first
+				profile.Post("/history/{changeID}/restore", userHandler.HandleRestoreProfileEntry) // Bring back a deleted entry
+				profile.Get("/as-of", userHandler.HandleGetProfileAsOf)                            // The profile at a point in time
+
 				// User Phone Numbers
 				profile.Post("/phone-numbers", userHandler.HandleCreatePhoneNumber)             // Add phone number
 				profile.Put("/phone-numbers/{phoneID}", userHandler.HandleUpdatePhoneNumber)    // Update phone number
 				profile.Delete("/phone-numbers/{phoneID}", userHandler.HandleDeletePhoneNumber) // Delete phone number
 
 				// User Education
-				profile.Post("/education", userHandler.HandleCreateEducation)                 // Add education
-				profile.Put("/education/{educationID}", userHandler.HandleUpdateEducation)    // Update education
-				profile.Delete("/education/{educationID}", userHandler.HandleDeleteEducation) // Delete education
+				profile.Post("/education", userHandler.HandleCreateEducation)                          // Add education
+				profile.Put("/education/{educationID}", userHandler.HandleUpdateEducation)             // Update education
+				profile.Delete("/education/{educationID}", userHandler.HandleDeleteEducation)          // Delete education
+				profile.Post("/education/{educationID}/media", userHandler.HandleUploadEducationMedia) // Upload education media
+				profile.Get("/education/{educationID}/media", userHandler.HandleGetEducationMedia)     // List education media
 
 				// User Experience
-				profile.Post("/experience", userHandler.HandleCreateExperience)                  // Add experience
-				profile.Put("/experience/{experienceID}", userHandler.HandleUpdateExperience)    // Update experience
-				profile.Delete("/experience/{experienceID}", userHandler.HandleDeleteExperience) // Delete experience
+				profile.Post("/experience", userHandler.HandleCreateExperience)                           // Add experience
+				profile.Put("/experience/{experienceID}", userHandler.HandleUpdateExperience)             // Update experience
+				profile.Delete("/experience/{experienceID}", userHandler.HandleDeleteExperience)          // Delete experience
+				profile.Post("/experience/{experienceID}/media", userHandler.HandleUploadExperienceMedia) // Upload experience media
+				profile.Get("/experience/{experienceID}/media", userHandler.HandleGetExperienceMedia)     // List experience media
 
 				// User Certifications
-				profile.Post("/certifications", userHandler.HandleCreateCertification)                     // Add certification
-				profile.Put("/certifications/{certificationID}", userHandler.HandleUpdateCertification)    // Update certification
-				profile.Delete("/certifications/{certificationID}", userHandler.HandleDeleteCertification) // Delete certification
+				profile.Post("/certifications", userHandler.HandleCreateCertification)                              // Add certification
+				profile.Put("/certifications/{certificationID}", userHandler.HandleUpdateCertification)             // Update certification
+				profile.Delete("/certifications/{certificationID}", userHandler.HandleDeleteCertification)          // Delete certification
+				profile.Post("/certifications/{certificationID}/media", userHandler.HandleUploadCertificationMedia) // Upload certification media
+				profile.Get("/certifications/{certificationID}/media", userHandler.HandleGetCertificationMedia)     // List certification media
 
 				// User Projects
-				profile.Post("/projects", userHandler.HandleCreateProject)               // Add project
-				profile.Put("/projects/{projectID}", userHandler.HandleUpdateProject)    // Update project
-				profile.Delete("/projects/{projectID}", userHandler.HandleDeleteProject) // Delete project
+				profile.Post("/projects", userHandler.HandleCreateProject)                        // Add project
+				profile.Put("/projects/{projectID}", userHandler.HandleUpdateProject)             // Update project
+				profile.Delete("/projects/{projectID}", userHandler.HandleDeleteProject)          // Delete project
+				profile.Post("/projects/{projectID}/media", userHandler.HandleUploadProjectMedia) // Upload project media
+				profile.Get("/projects/{projectID}/media", userHandler.HandleGetProjectMedia)     // List project media
+
+				// Media
+				profile.Delete("/media/{mediaID}", userHandler.HandleDeleteMedia) // Delete a media file
 
 				// User Skills
 				profile.Post("/skills", userHandler.HandleAddUserSkills)               // Add skill
+				profile.Put("/skills/{skillID}", userHandler.HandleUpdateUserSkill)    // Update proficiency and usage
 				profile.Delete("/skills/{skillID}", userHandler.HandleRemoveUserSkill) // Delete skill
+
+				// Import from other sources, ?mode=merge|replace&preview=true
+				profile.Post("/import/json-resume", userHandler.HandleImportJSONResume)
+				profile.Post("/import/europass", userHandler.HandleImportEuropass)
+				profile.Post("/import/linkedin", userHandler.HandleImportLinkedIn)
+
+				// Export to formats other sites import
+				profile.Get("/export/json-resume", userHandler.HandleExportJSONResume)
+				profile.Get("/export/europass", userHandler.HandleExportEuropass)
 			})
+
+			// Notifications
+			protected.Get("/notifications", userHandler.HandleGetNotifications)                            // List my notifications
+			protected.Post("/notifications/read-all", userHandler.HandleMarkAllNotificationsRead)          // Mark all as read
+			protected.Post("/notifications/{notificationID}/read", userHandler.HandleMarkNotificationRead) // Mark one as read
+
+			// Account deletion
+			protected.Post("/account/deletion", userHandler.HandleRequestAccountDeletion) // Schedule my account for deletion
+
+			// Personal data export
+			protected.Post("/data-export", userHandler.HandleRequestDataExport)       // Start building an archive of my data
+			protected.Get("/data-export/{exportID}", userHandler.HandleGetDataExport) // Export status and download link
+
+			// Employee referrals
+			protected.Post("/referrals", userHandler.HandleCreateReferralLink) // Get a referral link for a company job
+			protected.Get("/referrals", userHandler.HandleGetMyReferrals)      // List my referral links and referred applications
 		})
 	})
 
 	// Public routes (no middleware)
-	router.Get("/skills", userHandler.HandleSearchSkills) // Get all skills
+	router.Get("/skills", userHandler.HandleSearchSkills)                // Get all skills
+	router.Get("/skills/categories", userHandler.HandleGetSkillTaxonomy) // Categories with their skill trees
+
+	// Public job routes, signed-in callers are recognised for view tracking
+	setupJobRoutes(router, userHandler, jwtService)
+
+	// Public company profiles
+	router.Get("/companies/{companyID}", userHandler.HandleGetCompanyProfile)         // Company profile with open jobs
+	router.Get("/companies/{companyID}/logo", userHandler.HandleGetCompanyLogo)       // Company logo image
+	router.Get("/companies/{companyID}/updates", userHandler.HandleGetCompanyUpdates) // Company news feed
+
+	// Published profiles, signed-in recruiters see the sections shown to recruiters as well
+	router.With(middleware.OptionalJWTAuth(&jwtService)).Get("/profiles/{slug}", userHandler.HandleGetPublicProfile)
+
+	// Profile pictures follow the visibility of the profile_picture section
+	router.With(middleware.OptionalJWTAuth(&jwtService)).Get("/users/{userID}/picture", userHandler.HandleGetProfilePicture)
+
+	// Data export downloads are authorized by the signed link itself
+	router.Get("/data-exports/{exportID}/download", userHandler.HandleDownloadDataExport)
+
+	// Media files too, owners and recruiters get links on the profiles they may see
+	router.Get("/media/{mediaID}", userHandler.HandleDownloadMedia)
+	router.Get("/media/{mediaID}/thumbnails/{size}", userHandler.HandleDownloadMediaThumbnail)
+	router.Get("/applications/{applicationID}/media/{mediaID}", userHandler.HandleDownloadSnapshotMedia) // Copies submitted with an application
+	router.Get("/applications/{applicationID}/picture", userHandler.HandleDownloadSnapshotPicture)
 
 	// Applicant specific routes
 	setupApplicantRoutes(router, userHandler, jwtService)
@@ -110,10 +187,30 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 		r.Context().Value(chiMiddleware.RequestIDKey).(string) + `"}`))
 }
 
+func setupJobRoutes(router chi.Router, userHandler handlers.UserHandler, jwtService services.JWTService) {
+	router.Route("/jobs", func(jobs chi.Router) {
+		jobs.Use(middleware.OptionalJWTAuth(&jwtService))
+
+		jobs.Get("/{jobID}", userHandler.HandleGetJob)                       // Get job details
+		jobs.Post("/{jobID}/apply-start", userHandler.HandleTrackApplyStart) // Track opening the application form
+	})
+}
+
 func setupApplicantRoutes(router chi.Router, userHandler handlers.UserHandler, jwtService services.JWTService) {
 	router.Route("/applicant", func(applicant chi.Router) {
 		applicant.Post("/signup", userHandler.HandleApplicantSignUp)
 
+		applicant.Group(func(protected chi.Router) {
+			protected.Use(middleware.JWTAuth(&jwtService))
+			protected.Use(middleware.RequireRole("applicant"))
+
+			protected.Post("/jobs/{jobID}/apply", userHandler.HandleApplyToJob) // Apply to a job
+
+			// Company follows
+			protected.Get("/companies/following", userHandler.HandleGetFollowedCompanies)
+			protected.Post("/companies/{companyID}/follow", userHandler.HandleFollowCompany)
+			protected.Delete("/companies/{companyID}/follow", userHandler.HandleUnfollowCompany)
+		})
 	})
 }
 func setupRecruiterRoutes(router chi.Router, userHandler handlers.UserHandler, jwtService services.JWTService) {
@@ -122,6 +219,32 @@ func setupRecruiterRoutes(router chi.Router, userHandler handlers.UserHandler, j
 			protected.Use(middleware.JWTAuth(&jwtService))
 			protected.Use(middleware.RequireRole("recruiter"))
 
+			// Hiring analytics for the recruiter's company
+			protected.Get("/analytics/funnel", userHandler.HandleGetRecruiterFunnel)
+			protected.Get("/jobs/{jobID}/activity", userHandler.HandleGetJobActivity) // Views and apply clicks
+
+			// Company profile, owners only
+			protected.Patch("/company", userHandler.HandleUpdateMyCompany)
+			protected.Post("/company/logo", userHandler.HandleUploadMyCompanyLogo)
+
+			// Company news and followers
+			protected.Post("/company/updates", userHandler.HandleCreateCompanyUpdate)
+			protected.Delete("/company/updates/{updateID}", userHandler.HandleDeleteCompanyUpdate)
+			protected.Get("/company/followers", userHandler.HandleGetCompanyFollowerCount)
+
+			// Company jobs
+			protected.Post("/jobs", userHandler.HandleCreateJob)                          // Post a job
+			protected.Get("/jobs", userHandler.HandleGetCompanyJobs)                      // List company jobs
+			protected.Put("/jobs/{jobID}", userHandler.HandleUpdateJob)                   // Edit a job
+			protected.Get("/jobs/{jobID}/moderation", userHandler.HandleGetJobModeration) // Moderation decisions and reasons
+			protected.Put("/jobs/{jobID}/blind-hiring", userHandler.HandleSetBlindHiring) // Hide applicant identity from reviewers
+
+			// Applications to company jobs
+			protected.Get("/jobs/{jobID}/applications", userHandler.HandleGetJobApplications)
+			protected.Get("/applications/{applicationID}", userHandler.HandleGetApplication)
+			protected.Patch("/applications/{applicationID}/status", userHandler.HandleUpdateApplicationStatus)
+			protected.Get("/applications/{applicationID}/resume", userHandler.HandleGetApplicantResume)
+			protected.Get("/applications/{applicationID}/profile-diff", userHandler.HandleGetApplicationProfileDiff) // Profile changes since applying
 		})
 	})
 }
@@ -137,6 +260,36 @@ func setupAdminRoutes(router chi.Router, userHandler handlers.UserHandler, jwtSe
 			protected.Post("/company", userHandler.HandleCreateCompany)
 			protected.Patch("/company/{companyID}", userHandler.HandleUpdateCompany)
 			protected.Delete("/company/{companyID}", userHandler.HandleDeleteCompany)
+
+			protected.Post("/company/{companyID}/logo", userHandler.HandleUploadCompanyLogo)
+			protected.Patch("/company/{companyID}/trusted", userHandler.HandleSetCompanyTrusted)
+
+			// Job moderation
+			protected.Get("/jobs/moderation", userHandler.HandleGetModerationQueue)
+			protected.Post("/jobs/{jobID}/moderation", userHandler.HandleModerateJob)
+			protected.Get("/jobs/{jobID}/moderation", userHandler.HandleGetAdminJobModeration)
+
+			// Platform-wide hiring analytics
+			protected.Get("/analytics/funnel", userHandler.HandleGetAdminFunnel)
+			protected.Get("/analytics/eeo", userHandler.HandleGetEEOReport) // Aggregated, k-anonymized self-identification
+
+			// Skills users asked for that are not in the skills list yet
+			protected.Get("/skills/suggestions", userHandler.HandleGetSkillSuggestions)
+			protected.Post("/skills/suggestions/approve", userHandler.HandleApproveSkillSuggestion)
+			protected.Post("/skills/suggestions/reject", userHandler.HandleRejectSkillSuggestion)
+
+			// Skill taxonomy: categories, parent skills and aliases
+			protected.Post("/skills", userHandler.HandleCreateSkill)
+			protected.Put("/skills/{skillID}", userHandler.HandleUpdateSkill)
+			protected.Delete("/skills/{skillID}", userHandler.HandleDeleteSkill) // ?merge_into= moves users to another skill
+			protected.Post("/skills/categories", userHandler.HandleCreateSkillCategory)
+			protected.Put("/skills/categories/{categoryID}", userHandler.HandleUpdateSkillCategory)
+			protected.Delete("/skills/categories/{categoryID}", userHandler.HandleDeleteSkillCategory)
+
+			// Uploaded files the malware scan quarantined or could not scan
+			protected.Get("/media/flagged", userHandler.HandleGetFlaggedMedia)
+			protected.Post("/media/rescan", userHandler.HandleRescanAllMedia)
+			protected.Post("/media/{mediaID}/rescan", userHandler.HandleRescanMedia)
 		})
 	})
 }
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountPurgeStore finds and purges accounts whose deletion grace period is over
+type AccountPurgeStore interface {
+	GetAccountsDueForDeletion(limit int) ([]uuid.UUID, error)
+	PurgeAccount(userID uuid.UUID) ([]string, error)
+}
+
+// AccountPurger periodically purges accounts once their deletion grace period has passed
+// and removes their files from storage.
+type AccountPurger struct {
+	store     AccountPurgeStore
+	storage   FileStorage
+	interval  time.Duration
+	batchSize int
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewAccountPurger(store AccountPurgeStore, storage FileStorage, interval time.Duration) *AccountPurger {
+	p := &AccountPurger{
+		store:     store,
+		storage:   storage,
+		interval:  interval,
+		batchSize: 50,
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Close stops the purger, waiting for a purge in progress to finish
+func (p *AccountPurger) Close() {
+	p.closeOnce.Do(func() {
+		close(p.quit)
+		<-p.done
+	})
+}
+
+func (p *AccountPurger) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purgeDue()
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *AccountPurger) purgeDue() {
+	ids, err := p.store.GetAccountsDueForDeletion(p.batchSize)
+	if err != nil {
+		log.Printf("failed to get accounts due for deletion: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		keys, err := p.store.PurgeAccount(id)
+		if err != nil {
+			log.Printf("failed to purge account %s: %v", id, err)
+			continue
+		}
+		for _, key := range keys {
+			if err := p.storage.Delete(key); err != nil {
+				log.Printf("failed to delete file %s of purged account %s: %v", key, id, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// AvatarSize is the side of the square profile pictures are stored at. Smaller crops are kept
+// at their own size rather than scaled up.
+const AvatarSize = 512
+
+// ErrInvalidAvatarCrop is returned when the crop square does not fit inside the image
+var ErrInvalidAvatarCrop = errors.New("crop must be a square inside the image")
+
+// AvatarCrop is the square to keep, in pixels of the upright image. A zero Size keeps the
+// largest square in the middle of the image.
+type AvatarCrop struct {
+	X, Y, Size int
+}
+
+// Avatar is an encoded, square profile picture
+type Avatar struct {
+	MimeType  string
+	Extension string
+	Data      []byte
+}
+
+// ProcessAvatar checks an uploaded image like any other media, crops it to a square and scales
+// it down to AvatarSize. The picture is encoded again from its pixels, so nothing but the image
+// itself survives from the upload.
+func ProcessAvatar(file io.ReaderAt, size int64, crop AvatarCrop) (*Avatar, error) {
+	processed, err := ProcessMedia(file, size, "image")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(processed.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s cannot be used as a profile picture", ErrMediaTypeNotAllowed, processed.MimeType)
+	}
+	if config.Width*config.Height > maxThumbnailPixels {
+		return nil, fmt.Errorf("%w: images can be at most %d megapixels", ErrMediaTooLarge, maxThumbnailPixels/1_000_000)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s could not be decoded", ErrMediaTypeNotAllowed, processed.MimeType)
+	}
+
+	b := img.Bounds()
+	if crop.Size == 0 {
+		crop.Size = min(b.Dx(), b.Dy())
+		crop.X, crop.Y = (b.Dx()-crop.Size)/2, (b.Dy()-crop.Size)/2
+	}
+	if crop.Size < 0 || crop.X < 0 || crop.Y < 0 || crop.X+crop.Size > b.Dx() || crop.Y+crop.Size > b.Dy() {
+		return nil, fmt.Errorf("%w: the image is %dx%d", ErrInvalidAvatarCrop, b.Dx(), b.Dy())
+	}
+
+	square := image.NewRGBA(image.Rect(0, 0, crop.Size, crop.Size))
+	draw.Draw(square, square.Bounds(), img, b.Min.Add(image.Pt(crop.X, crop.Y)), draw.Src)
+	if crop.Size > AvatarSize {
+		square = boxScale(square, AvatarSize, AvatarSize)
+	}
+
+	var buf bytes.Buffer
+	avatar := &Avatar{MimeType: "image/jpeg", Extension: ".jpg"}
+	if processed.MimeType != "image/jpeg" && hasTransparency(square) {
+		avatar.MimeType, avatar.Extension = "image/png", ".png"
+		err = png.Encode(&buf, square)
+	} else {
+		err = jpeg.Encode(&buf, square, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	avatar.Data = buf.Bytes()
+	return avatar, nil
+}
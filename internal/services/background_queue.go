@@ -0,0 +1,71 @@
+package services
+
+import (
+	"log"
+	"sync"
+)
+
+// BackgroundQueue runs slow jobs such as data exports on a fixed pool of workers
+// so request handlers can return straight away.
+type BackgroundQueue struct {
+	mu     sync.RWMutex
+	jobs   chan func()
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewBackgroundQueue(workers, capacity int) *BackgroundQueue {
+	q := &BackgroundQueue{jobs: make(chan func(), capacity)}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.run()
+	}
+	return q
+}
+
+// Enqueue schedules a job without blocking. It reports false when the queue is full or closed.
+func (q *BackgroundQueue) Enqueue(job func()) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return false
+	}
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting jobs and waits for the queued ones to finish
+func (q *BackgroundQueue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.jobs)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+func (q *BackgroundQueue) run() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		q.runJob(job)
+	}
+}
+
+// runJob keeps a panicking job from taking the worker down with it
+func (q *BackgroundQueue) runJob(job func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Printf("background job panicked: %v", err)
+		}
+	}()
+	job()
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// BucketCache keeps values for the time bucket they were computed in.
+// Once the clock moves into the next bucket every entry is considered stale,
+// so all callers see results recomputed at the same boundaries.
+type BucketCache struct {
+	mu      sync.Mutex
+	bucket  time.Duration
+	entries map[string]bucketEntry
+}
+
+type bucketEntry struct {
+	bucket time.Time
+	value  any
+}
+
+func NewBucketCache(bucket time.Duration) *BucketCache {
+	return &BucketCache{
+		bucket:  bucket,
+		entries: make(map[string]bucketEntry),
+	}
+}
+
+// Get returns the value cached for key in the current bucket
+func (c *BucketCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.bucket.Equal(c.currentBucket()) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores value for key in the current bucket, dropping entries from older buckets
+func (c *BucketCache) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := c.currentBucket()
+	for k, entry := range c.entries {
+		if !entry.bucket.Equal(current) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = bucketEntry{bucket: current, value: value}
+}
+
+func (c *BucketCache) currentBucket() time.Time {
+	return time.Now().UTC().Truncate(c.bucket)
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// Achievement codes used for the profile sections Europass has no list of its own for
+const (
+	europassCertifications = "certifications"
+	europassProjects       = "projects"
+)
+
+// EuropassDocument is a Europass CV. Europass has no place for a work entry's employment type,
+// so work imports back as full-time unless it was volunteering, nor for the about section, which
+// is left out. The grade of an education entry is a "Grade: value" first line of its skills, the
+// level is the qualification's EQF level and is not used. Certifications and projects are
+// achievements whose description starts with "Label: value" lines for their structured fields.
+// The first telephone is the primary one.
+type EuropassDocument struct {
+	XMLName      xml.Name             `xml:"http://europass.cedefop.europa.eu/Europass SkillsPassport"`
+	Locale       string               `xml:"locale,attr"`
+	DocumentInfo EuropassDocumentInfo `xml:"DocumentInfo"`
+	LearnerInfo  EuropassLearnerInfo  `xml:"LearnerInfo"`
+}
+
+type EuropassDocumentInfo struct {
+	DocumentType string `xml:"DocumentType"`
+	CreationDate string `xml:"CreationDate,omitempty"`
+	XSDVersion   string `xml:"XSDVersion"`
+	Generator    string `xml:"Generator,omitempty"`
+}
+
+type EuropassLearnerInfo struct {
+	Identification     EuropassIdentification   `xml:"Identification"`
+	Headline           *EuropassHeadline        `xml:"Headline,omitempty"`
+	WorkExperienceList []EuropassWorkExperience `xml:"WorkExperienceList>WorkExperience"`
+	EducationList      []EuropassEducation      `xml:"EducationList>Education"`
+	Skills             *EuropassSkills          `xml:"Skills,omitempty"`
+	AchievementList    []EuropassAchievement    `xml:"AchievementList>Achievement"`
+}
+
+type EuropassIdentification struct {
+	FirstName   string              `xml:"PersonName>FirstName"`
+	Surname     string              `xml:"PersonName>Surname"`
+	ContactInfo EuropassContactInfo `xml:"ContactInfo"`
+}
+
+type EuropassContactInfo struct {
+	Municipality string              `xml:"Address>Contact>Municipality,omitempty"`
+	Email        string              `xml:"Email>Contact,omitempty"`
+	Telephones   []EuropassTelephone `xml:"TelephoneList>Telephone"`
+}
+
+type EuropassTelephone struct {
+	Contact string `xml:"Contact"`
+	Use     string `xml:"Use>Code,omitempty"` // home, work or mobile
+}
+
+type EuropassHeadline struct {
+	Type  EuropassLabel `xml:"Type"`
+	Label string        `xml:"Description>Label"`
+}
+
+// EuropassLabel is the code/label pair Europass uses for its vocabularies
+type EuropassLabel struct {
+	Code  string `xml:"Code,omitempty"`
+	Label string `xml:"Label,omitempty"`
+}
+
+type EuropassPeriod struct {
+	From    *EuropassDate `xml:"From,omitempty"`
+	To      *EuropassDate `xml:"To,omitempty"`
+	Current bool          `xml:"Current,omitempty"`
+}
+
+// EuropassDate uses the XML Schema gYear, gMonth and gDay forms, e.g. year="2020" month="--03" day="---15"
+type EuropassDate struct {
+	Year  string `xml:"year,attr"`
+	Month string `xml:"month,attr,omitempty"`
+	Day   string `xml:"day,attr,omitempty"`
+}
+
+type EuropassWorkExperience struct {
+	Period     EuropassPeriod   `xml:"Period"`
+	Position   EuropassLabel    `xml:"Position"`
+	Activities string           `xml:"Activities,omitempty"`
+	Employer   EuropassEmployer `xml:"Employer"`
+}
+
+type EuropassEmployer struct {
+	Name         string         `xml:"Name"`
+	Municipality string         `xml:"ContactInfo>Address>Contact>Municipality,omitempty"`
+	Sector       *EuropassLabel `xml:"Sector,omitempty"`
+}
+
+type EuropassEducation struct {
+	Period       EuropassPeriod `xml:"Period"`
+	Title        string         `xml:"Title"`
+	Skills       string         `xml:"Skills,omitempty"`
+	Organisation string         `xml:"Organisation>Name"`
+	Level        *EuropassLabel `xml:"Level,omitempty"`
+	Field        *EuropassLabel `xml:"Field,omitempty"`
+}
+
+type EuropassSkills struct {
+	Other string `xml:"Other>Description,omitempty"` // one skill per line
+}
+
+type EuropassAchievement struct {
+	Title       EuropassLabel `xml:"Title"`
+	Description string        `xml:"Description"`
+}
+
+// ProfileToEuropass serializes a profile as a Europass CV. MapEuropass maps the result back to the same entries.
+func ProfileToEuropass(profile *models.UserProfile) EuropassDocument {
+	doc := EuropassDocument{
+		Locale: "en",
+		DocumentInfo: EuropassDocumentInfo{
+			DocumentType: "ECV",
+			CreationDate: time.Now().UTC().Format(time.RFC3339),
+			XSDVersion:   "V3.4",
+			Generator:    "Job-Hunter",
+		},
+	}
+
+	info := &doc.LearnerInfo
+	info.Identification.FirstName, info.Identification.Surname = splitFullName(profile.User.FullName)
+	info.Identification.ContactInfo.Email = profile.User.Email
+	info.Identification.ContactInfo.Municipality = deref(profile.User.Location)
+	// Europass has no primary mark, the primary number goes first
+	if primary := primaryPhone(profile.PhoneNumbers); primary != nil {
+		telephones := []EuropassTelephone{europassTelephone(*primary)}
+		for _, phone := range profile.PhoneNumbers {
+			if phone.ID != primary.ID {
+				telephones = append(telephones, europassTelephone(phone))
+			}
+		}
+		info.Identification.ContactInfo.Telephones = telephones
+	}
+	if title := deref(profile.User.Title); title != "" {
+		info.Headline = &EuropassHeadline{
+			Type:  EuropassLabel{Code: "position", Label: "Desired position"},
+			Label: title,
+		}
+	}
+
+	for _, e := range profile.Experience {
+		work := EuropassWorkExperience{
+			Period:     europassPeriod(e.StartDate, e.EndDate, e.IsCurrent),
+			Position:   EuropassLabel{Label: e.PositionTitle},
+			Activities: deref(e.Description),
+			Employer: EuropassEmployer{
+				Name:         e.CompanyName,
+				Municipality: deref(e.Location),
+			},
+		}
+		if e.EmploymentType == "volunteer" {
+			work.Employer.Sector = &EuropassLabel{Label: "Volunteering"}
+		}
+		info.WorkExperienceList = append(info.WorkExperienceList, work)
+	}
+
+	for _, e := range profile.Education {
+		education := EuropassEducation{
+			Period:       europassPeriod(e.StartDate, e.EndDate, e.IsCurrent),
+			Title:        e.Degree,
+			Skills:       deref(e.Description),
+			Organisation: e.InstitutionName,
+		}
+		if grade := deref(e.GradeGPA); grade != "" {
+			education.Skills = strings.TrimSuffix(europassGrade+grade+"\n"+education.Skills, "\n")
+		}
+		if field := deref(e.FieldOfStudy); field != "" {
+			education.Field = &EuropassLabel{Label: field}
+		}
+		info.EducationList = append(info.EducationList, education)
+	}
+
+	if len(profile.Skills) > 0 {
+		names := make([]string, len(profile.Skills))
+		for i, skill := range profile.Skills {
+			names[i] = skill.Name
+		}
+		info.Skills = &EuropassSkills{Other: strings.Join(names, "\n")}
+	}
+
+	for _, c := range profile.Certifications {
+		info.AchievementList = append(info.AchievementList, EuropassAchievement{
+			Title: EuropassLabel{Code: europassCertifications, Label: "Certifications"},
+			Description: achievementDescription(c.CertificationName, deref(c.Description), [][2]string{
+				{"Issuer", c.IssuingOrganization},
+				{"Issued", isoDate(c.IssueDate)},
+				{"Expires", isoDate(c.ExpirationDate)},
+				{"Credential ID", deref(c.CredentialID)},
+				{"Credential URL", deref(c.CredentialURL)},
+			}),
+		})
+	}
+
+	for _, p := range profile.Projects {
+		ongoing := ""
+		if p.IsOngoing {
+			ongoing = "yes"
+		}
+		info.AchievementList = append(info.AchievementList, EuropassAchievement{
+			Title: EuropassLabel{Code: europassProjects, Label: "Projects"},
+			Description: achievementDescription(p.ProjectName, deref(p.Description), [][2]string{
+				{"From", isoDate(p.StartDate)},
+				{"To", isoDate(p.EndDate)},
+				{"Ongoing", ongoing},
+				{"URL", deref(p.ProjectURL)},
+			}),
+		})
+	}
+
+	return doc
+}
+
+// MapEuropass turns a Europass CV into profile entries. Entries the profile cannot hold are
+// skipped with a warning, achievements other than certifications and projects are ignored.
+// The email address is not imported, it is the account's sign-in.
+func MapEuropass(doc EuropassDocument) (dto.ProfileImport, []string) {
+	var data dto.ProfileImport
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	identification := doc.LearnerInfo.Identification
+	data.Basics.FullName = optional(joinNonEmpty(" ", identification.FirstName, identification.Surname))
+	data.Basics.Location = optional(identification.ContactInfo.Municipality)
+	if doc.LearnerInfo.Headline != nil {
+		data.Basics.Title = optional(doc.LearnerInfo.Headline.Label)
+	}
+
+	phones := make([]JSONResumePhone, len(identification.ContactInfo.Telephones))
+	for i, telephone := range identification.ContactInfo.Telephones {
+		phones[i] = JSONResumePhone{Number: telephone.Contact, Type: telephone.Use, IsPrimary: i == 0}
+	}
+	data.PhoneNumbers = mapPhoneNumbers(phones, warn)
+
+	for i, w := range doc.LearnerInfo.WorkExperienceList {
+		if strings.TrimSpace(w.Employer.Name) == "" || strings.TrimSpace(w.Position.Label) == "" {
+			warn("work experience %d: skipped, employer name and position are required", i+1)
+			continue
+		}
+		employmentType := "full-time"
+		if w.Employer.Sector != nil && strings.EqualFold(w.Employer.Sector.Label, "Volunteering") {
+			employmentType = "volunteer"
+		}
+		data.Experience = append(data.Experience, dto.CreateExperienceRequest{
+			CompanyName:    strings.TrimSpace(w.Employer.Name),
+			PositionTitle:  strings.TrimSpace(w.Position.Label),
+			EmploymentType: employmentType,
+			StartDate:      w.Period.From.date(),
+			EndDate:        w.Period.To.date(),
+			IsCurrent:      w.Period.Current,
+			Location:       w.Employer.Municipality,
+			Description:    w.Activities,
+		})
+	}
+
+	for i, e := range doc.LearnerInfo.EducationList {
+		if strings.TrimSpace(e.Organisation) == "" || strings.TrimSpace(e.Title) == "" {
+			warn("education %d: skipped, organisation name and title are required", i+1)
+			continue
+		}
+		education := dto.CreateEducationRequest{
+			InstitutionName: strings.TrimSpace(e.Organisation),
+			Degree:          strings.TrimSpace(e.Title),
+			StartDate:       e.Period.From.date(),
+			EndDate:         e.Period.To.date(),
+			IsCurrent:       e.Period.Current,
+			Description:     e.Skills,
+		}
+		if first, rest, _ := strings.Cut(e.Skills, "\n"); strings.HasPrefix(first, europassGrade) {
+			education.GradeGPA = strings.TrimSpace(strings.TrimPrefix(first, europassGrade))
+			education.Description = rest
+		}
+		if e.Field != nil {
+			education.FieldOfStudy = e.Field.Label
+		}
+		data.Education = append(data.Education, education)
+	}
+
+	for i, a := range doc.LearnerInfo.AchievementList {
+		name, fields, description := parseAchievementDescription(a.Description)
+		switch a.Title.Code {
+		case europassCertifications:
+			if name == "" || fields["Issuer"] == "" {
+				warn("achievement %d: skipped, a certification needs a name and an Issuer line", i+1)
+				continue
+			}
+			data.Certifications = append(data.Certifications, dto.CreateCertificationRequest{
+				CertificationName:   name,
+				IssuingOrganization: fields["Issuer"],
+				IssueDate:           europassFieldDate(warn, i, fields, "Issued"),
+				ExpirationDate:      europassFieldDate(warn, i, fields, "Expires"),
+				CredentialID:        fields["Credential ID"],
+				CredentialURL:       fields["Credential URL"],
+				Description:         description,
+			})
+		case europassProjects:
+			if name == "" {
+				warn("achievement %d: skipped, a project needs a name", i+1)
+				continue
+			}
+			data.Projects = append(data.Projects, dto.CreateProjectRequest{
+				ProjectName: name,
+				Description: description,
+				StartDate:   europassFieldDate(warn, i, fields, "From"),
+				EndDate:     europassFieldDate(warn, i, fields, "To"),
+				IsOngoing:   strings.EqualFold(fields["Ongoing"], "yes"),
+				ProjectURL:  fields["URL"],
+			})
+		}
+	}
+
+	if doc.LearnerInfo.Skills != nil {
+		seen := map[string]bool{}
+		for _, name := range strings.Split(doc.LearnerInfo.Skills.Other, "\n") {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			data.Skills = append(data.Skills, name)
+		}
+	}
+
+	return data, warnings
+}
+
+// europassGrade starts the line of an education entry's skills that holds the grade
+const europassGrade = "Grade: "
+
+func europassTelephone(phone models.UserPhoneNumber) EuropassTelephone {
+	use := phone.PhoneType
+	if use == "other" {
+		use = ""
+	}
+	return EuropassTelephone{Contact: phone.PhoneNumber, Use: use}
+}
+
+// splitFullName puts the last word of a name in the surname, Europass wants the two apart
+func splitFullName(fullName string) (string, string) {
+	fullName = strings.TrimSpace(fullName)
+	i := strings.LastIndex(fullName, " ")
+	if i < 0 {
+		return fullName, ""
+	}
+	return strings.TrimSpace(fullName[:i]), fullName[i+1:]
+}
+
+func europassPeriod(start, end *time.Time, current bool) EuropassPeriod {
+	return EuropassPeriod{From: newEuropassDate(start), To: newEuropassDate(end), Current: current}
+}
+
+func newEuropassDate(t *time.Time) *EuropassDate {
+	if t == nil {
+		return nil
+	}
+	return &EuropassDate{Year: t.Format("2006"), Month: t.Format("--01"), Day: t.Format("---02")}
+}
+
+// date returns the date as YYYY-MM-DD, filling in the first month or day when they are left out
+func (d *EuropassDate) date() string {
+	if d == nil || d.Year == "" {
+		return ""
+	}
+	month := strings.TrimPrefix(d.Month, "--")
+	if month == "" {
+		month = "01"
+	}
+	day := strings.TrimPrefix(d.Day, "---")
+	if day == "" {
+		day = "01"
+	}
+	value, ok := normalizeResumeDate(d.Year + "-" + month + "-" + day)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// achievementDescription writes the name, the non-empty fields as "Label: value" lines,
+// then a blank line and the free text description
+func achievementDescription(name, description string, fields [][2]string) string {
+	lines := []string{name}
+	for _, field := range fields {
+		if field[1] != "" {
+			lines = append(lines, field[0]+": "+field[1])
+		}
+	}
+	text := strings.Join(lines, "\n")
+	if description != "" {
+		text += "\n\n" + description
+	}
+	return text
+}
+
+func parseAchievementDescription(text string) (string, map[string]string, string) {
+	header, description, _ := strings.Cut(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n")
+	lines := strings.Split(header, "\n")
+	fields := map[string]string{}
+	for _, line := range lines[1:] {
+		if label, value, ok := strings.Cut(line, ": "); ok {
+			fields[strings.TrimSpace(label)] = strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(lines[0]), fields, description
+}
+
+func europassFieldDate(warn func(string, ...any), i int, fields map[string]string, label string) string {
+	value, ok := normalizeResumeDate(fields[label])
+	if !ok {
+		warn("achievement %d: ignored %s %q, expected YYYY, YYYY-MM or YYYY-MM-DD", i+1, label, fields[label])
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// JobEventRecorder persists a batch of job tracking events
+type JobEventRecorder interface {
+	RecordJobEvents(events []models.JobEvent) error
+}
+
+// JobEventTracker buffers job tracking events in memory and writes them in batches
+// from a single background goroutine, so request handlers never wait on the database.
+type JobEventTracker struct {
+	recorder      JobEventRecorder
+	events        chan models.JobEvent
+	batchSize     int
+	flushInterval time.Duration
+	quit          chan struct{}
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+func NewJobEventTracker(recorder JobEventRecorder, batchSize int, flushInterval time.Duration) *JobEventTracker {
+	t := &JobEventTracker{
+		recorder:      recorder,
+		events:        make(chan models.JobEvent, batchSize*10),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		quit:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Track queues an event without blocking. Events are dropped when the buffer is full.
+func (t *JobEventTracker) Track(event models.JobEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	select {
+	case t.events <- event:
+	default:
+		log.Printf("job event buffer full, dropping %s event for job %s", event.EventType, event.JobID)
+	}
+}
+
+// Close flushes any buffered events and stops the background writer
+func (t *JobEventTracker) Close() {
+	t.closeOnce.Do(func() {
+		close(t.quit)
+		<-t.done
+	})
+}
+
+func (t *JobEventTracker) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.JobEvent, 0, t.batchSize)
+	for {
+		select {
+		case event := <-t.events:
+			batch = append(batch, event)
+			if len(batch) >= t.batchSize {
+				batch = t.flush(batch)
+			}
+		case <-ticker.C:
+			batch = t.flush(batch)
+		case <-t.quit:
+			// Drain whatever is still queued before exiting
+			for {
+				select {
+				case event := <-t.events:
+					batch = append(batch, event)
+				default:
+					t.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *JobEventTracker) flush(batch []models.JobEvent) []models.JobEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := t.recorder.RecordJobEvents(batch); err != nil {
+		log.Printf("failed to record job events from a batch of %d: %v", len(batch), err)
+	}
+	return batch[:0]
+}
+
+// botUserAgentMarkers are lower-case substrings that identify crawlers and scripted clients
+var botUserAgentMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "crawl", "mediapartners", "facebookexternalhit",
+	"headless", "phantomjs", "lighthouse", "pingdom", "uptime",
+	"curl", "wget", "python-requests", "python-urllib", "go-http-client", "java/", "okhttp",
+	"libwww", "httpclient", "scrapy", "postman",
+}
+
+// IsBotUserAgent reports whether a user agent looks like a crawler or script rather than a browser
+func IsBotUserAgent(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, marker := range botUserAgentMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
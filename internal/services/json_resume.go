@@ -0,0 +1,491 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// JSONResume is the part of a JSON Resume document (https://jsonresume.org/schema) the profile can hold.
+// The schema allows additional properties; fields marked as extensions carry what the schema has no
+// place for, so a profile exported here imports back unchanged. Other tools ignore them.
+type JSONResume struct {
+	Basics       JSONResumeBasics        `json:"basics"`
+	Work         []JSONResumeWork        `json:"work"`
+	Volunteer    []JSONResumeVolunteer   `json:"volunteer"`
+	Education    []JSONResumeEducation   `json:"education"`
+	Certificates []JSONResumeCertificate `json:"certificates"`
+	Projects     []JSONResumeProject     `json:"projects"`
+	Skills       []JSONResumeSkill       `json:"skills"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+
+	Phones []JSONResumePhone `json:"phones,omitempty"` // extension, every number where phone only holds one
+}
+
+type JSONResumePhone struct {
+	Number    string `json:"number"`
+	Type      string `json:"type,omitempty"` // mobile, home, work or other
+	IsPrimary bool   `json:"isPrimary,omitempty"`
+}
+
+type JSONResumeLocation struct {
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position"`
+	Location   string   `json:"location,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+
+	EmploymentType string `json:"employmentType,omitempty"` // extension
+	IsCurrent      *bool  `json:"isCurrent,omitempty"`      // extension
+}
+
+type JSONResumeVolunteer struct {
+	Organization string   `json:"organization"`
+	Position     string   `json:"position"`
+	URL          string   `json:"url,omitempty"`
+	StartDate    string   `json:"startDate,omitempty"`
+	EndDate      string   `json:"endDate,omitempty"`
+	Summary      string   `json:"summary,omitempty"`
+	Highlights   []string `json:"highlights,omitempty"`
+
+	Location  string `json:"location,omitempty"`  // extension
+	IsCurrent *bool  `json:"isCurrent,omitempty"` // extension
+}
+
+type JSONResumeEducation struct {
+	Institution string   `json:"institution"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+
+	Description string `json:"description,omitempty"` // extension
+	IsCurrent   *bool  `json:"isCurrent,omitempty"`   // extension
+}
+
+type JSONResumeCertificate struct {
+	Name   string `json:"name"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer"`
+	URL    string `json:"url,omitempty"`
+
+	ExpirationDate string `json:"expirationDate,omitempty"` // extension
+	CredentialID   string `json:"credentialId,omitempty"`   // extension
+	Description    string `json:"description,omitempty"`    // extension
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+
+	IsOngoing *bool `json:"isOngoing,omitempty"` // extension
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// MapJSONResume turns a JSON Resume document into profile entries. Entries the profile
+// cannot hold, such as work without a company name, are skipped with a warning. The email
+// address is not imported, it is the account's sign-in.
+func MapJSONResume(doc JSONResume) (dto.ProfileImport, []string) {
+	var data dto.ProfileImport
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	data.Basics = dto.UpdateProfileRequest{
+		FullName:     optional(doc.Basics.Name),
+		Title:        optional(doc.Basics.Label),
+		AboutSection: optional(doc.Basics.Summary),
+	}
+	if l := doc.Basics.Location; l != nil {
+		location := l.Address
+		if location == "" {
+			location = joinNonEmpty(", ", l.City, l.Region, l.CountryCode)
+		}
+		data.Basics.Location = optional(location)
+	}
+
+	phones := doc.Basics.Phones
+	if len(phones) == 0 && strings.TrimSpace(doc.Basics.Phone) != "" {
+		phones = []JSONResumePhone{{Number: doc.Basics.Phone, Type: "mobile", IsPrimary: true}}
+	}
+	data.PhoneNumbers = mapPhoneNumbers(phones, warn)
+	date := func(section string, i int, field, value string) string {
+		d, ok := normalizeResumeDate(value)
+		if !ok {
+			warn("%s %d: ignored %s %q, expected YYYY, YYYY-MM or YYYY-MM-DD", section, i+1, field, value)
+		}
+		return d
+	}
+
+	for i, w := range doc.Work {
+		if strings.TrimSpace(w.Name) == "" || strings.TrimSpace(w.Position) == "" {
+			warn("work %d: skipped, company name and position are required", i+1)
+			continue
+		}
+		start, end := date("work", i, "startDate", w.StartDate), date("work", i, "endDate", w.EndDate)
+		employmentType := w.EmploymentType
+		if !workEmploymentTypes[employmentType] {
+			if employmentType != "" {
+				warn("work %d: unknown employmentType %q, using full-time", i+1, employmentType)
+			}
+			employmentType = "full-time"
+		}
+		data.Experience = append(data.Experience, dto.CreateExperienceRequest{
+			CompanyName:    strings.TrimSpace(w.Name),
+			PositionTitle:  strings.TrimSpace(w.Position),
+			EmploymentType: employmentType,
+			StartDate:      start,
+			EndDate:        end,
+			IsCurrent:      resumeCurrent(w.IsCurrent, start, end),
+			Location:       w.Location,
+			Description:    resumeDescription(w.Summary, w.Highlights),
+		})
+	}
+
+	for i, v := range doc.Volunteer {
+		if strings.TrimSpace(v.Organization) == "" || strings.TrimSpace(v.Position) == "" {
+			warn("volunteer %d: skipped, organization and position are required", i+1)
+			continue
+		}
+		start, end := date("volunteer", i, "startDate", v.StartDate), date("volunteer", i, "endDate", v.EndDate)
+		data.Experience = append(data.Experience, dto.CreateExperienceRequest{
+			CompanyName:    strings.TrimSpace(v.Organization),
+			PositionTitle:  strings.TrimSpace(v.Position),
+			EmploymentType: "volunteer",
+			StartDate:      start,
+			EndDate:        end,
+			IsCurrent:      resumeCurrent(v.IsCurrent, start, end),
+			Location:       v.Location,
+			Description:    resumeDescription(v.Summary, v.Highlights),
+		})
+	}
+
+	for i, e := range doc.Education {
+		if strings.TrimSpace(e.Institution) == "" || strings.TrimSpace(e.StudyType) == "" {
+			warn("education %d: skipped, institution and studyType are required", i+1)
+			continue
+		}
+		start, end := date("education", i, "startDate", e.StartDate), date("education", i, "endDate", e.EndDate)
+		description := e.Description
+		if description == "" && len(e.Courses) > 0 {
+			description = "Courses: " + strings.Join(e.Courses, ", ")
+		}
+		data.Education = append(data.Education, dto.CreateEducationRequest{
+			InstitutionName: strings.TrimSpace(e.Institution),
+			Degree:          strings.TrimSpace(e.StudyType),
+			FieldOfStudy:    e.Area,
+			StartDate:       start,
+			EndDate:         end,
+			IsCurrent:       resumeCurrent(e.IsCurrent, start, end),
+			GradeGPA:        e.Score,
+			Description:     description,
+		})
+	}
+
+	for i, c := range doc.Certificates {
+		if strings.TrimSpace(c.Name) == "" || strings.TrimSpace(c.Issuer) == "" {
+			warn("certificates %d: skipped, name and issuer are required", i+1)
+			continue
+		}
+		data.Certifications = append(data.Certifications, dto.CreateCertificationRequest{
+			CertificationName:   strings.TrimSpace(c.Name),
+			IssuingOrganization: strings.TrimSpace(c.Issuer),
+			IssueDate:           date("certificates", i, "date", c.Date),
+			ExpirationDate:      date("certificates", i, "expirationDate", c.ExpirationDate),
+			CredentialID:        c.CredentialID,
+			CredentialURL:       c.URL,
+			Description:         c.Description,
+		})
+	}
+
+	for i, p := range doc.Projects {
+		if strings.TrimSpace(p.Name) == "" {
+			warn("projects %d: skipped, name is required", i+1)
+			continue
+		}
+		start, end := date("projects", i, "startDate", p.StartDate), date("projects", i, "endDate", p.EndDate)
+		data.Projects = append(data.Projects, dto.CreateProjectRequest{
+			ProjectName: strings.TrimSpace(p.Name),
+			Description: resumeDescription(p.Description, p.Highlights),
+			StartDate:   start,
+			EndDate:     end,
+			IsOngoing:   resumeCurrent(p.IsOngoing, start, end),
+			ProjectURL:  p.URL,
+		})
+	}
+
+	// A skill's keywords are skills in their own right, e.g. "Web Development": ["HTML", "CSS"]
+	seen := map[string]bool{}
+	addSkill := func(name string) {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			return
+		}
+		seen[strings.ToLower(name)] = true
+		data.Skills = append(data.Skills, name)
+	}
+	for _, s := range doc.Skills {
+		addSkill(s.Name)
+		for _, keyword := range s.Keywords {
+			addSkill(keyword)
+		}
+	}
+
+	return data, warnings
+}
+
+// ProfileToJSONResume serializes a profile as a JSON Resume document. MapJSONResume maps the
+// result back to the same entries.
+func ProfileToJSONResume(profile *models.UserProfile) JSONResume {
+	doc := JSONResume{
+		Basics: JSONResumeBasics{
+			Name:    profile.User.FullName,
+			Label:   deref(profile.User.Title),
+			Email:   profile.User.Email,
+			Summary: deref(profile.User.AboutSection),
+		},
+		Work:         []JSONResumeWork{},
+		Volunteer:    []JSONResumeVolunteer{},
+		Education:    []JSONResumeEducation{},
+		Certificates: []JSONResumeCertificate{},
+		Projects:     []JSONResumeProject{},
+		Skills:       []JSONResumeSkill{},
+	}
+	if location := deref(profile.User.Location); location != "" {
+		doc.Basics.Location = &JSONResumeLocation{Address: location}
+	}
+	if phone := primaryPhone(profile.PhoneNumbers); phone != nil {
+		doc.Basics.Phone = phone.PhoneNumber
+	}
+	for _, phone := range profile.PhoneNumbers {
+		doc.Basics.Phones = append(doc.Basics.Phones, JSONResumePhone{
+			Number:    phone.PhoneNumber,
+			Type:      phone.PhoneType,
+			IsPrimary: phone.IsPrimary,
+		})
+	}
+
+	for _, e := range profile.Experience {
+		isCurrent := e.IsCurrent
+		if e.EmploymentType == "volunteer" {
+			doc.Volunteer = append(doc.Volunteer, JSONResumeVolunteer{
+				Organization: e.CompanyName,
+				Position:     e.PositionTitle,
+				StartDate:    isoDate(e.StartDate),
+				EndDate:      isoDate(e.EndDate),
+				Summary:      deref(e.Description),
+				Location:     deref(e.Location),
+				IsCurrent:    &isCurrent,
+			})
+			continue
+		}
+		doc.Work = append(doc.Work, JSONResumeWork{
+			Name:           e.CompanyName,
+			Position:       e.PositionTitle,
+			Location:       deref(e.Location),
+			StartDate:      isoDate(e.StartDate),
+			EndDate:        isoDate(e.EndDate),
+			Summary:        deref(e.Description),
+			EmploymentType: e.EmploymentType,
+			IsCurrent:      &isCurrent,
+		})
+	}
+
+	for _, e := range profile.Education {
+		isCurrent := e.IsCurrent
+		doc.Education = append(doc.Education, JSONResumeEducation{
+			Institution: e.InstitutionName,
+			Area:        deref(e.FieldOfStudy),
+			StudyType:   e.Degree,
+			StartDate:   isoDate(e.StartDate),
+			EndDate:     isoDate(e.EndDate),
+			Score:       deref(e.GradeGPA),
+			Description: deref(e.Description),
+			IsCurrent:   &isCurrent,
+		})
+	}
+
+	for _, c := range profile.Certifications {
+		doc.Certificates = append(doc.Certificates, JSONResumeCertificate{
+			Name:           c.CertificationName,
+			Date:           isoDate(c.IssueDate),
+			Issuer:         c.IssuingOrganization,
+			URL:            deref(c.CredentialURL),
+			ExpirationDate: isoDate(c.ExpirationDate),
+			CredentialID:   deref(c.CredentialID),
+			Description:    deref(c.Description),
+		})
+	}
+
+	for _, p := range profile.Projects {
+		isOngoing := p.IsOngoing
+		doc.Projects = append(doc.Projects, JSONResumeProject{
+			Name:        p.ProjectName,
+			Description: deref(p.Description),
+			StartDate:   isoDate(p.StartDate),
+			EndDate:     isoDate(p.EndDate),
+			URL:         deref(p.ProjectURL),
+			IsOngoing:   &isOngoing,
+		})
+	}
+
+	for _, skill := range profile.Skills {
+		doc.Skills = append(doc.Skills, JSONResumeSkill{Name: skill.Name, Level: deref(skill.Proficiency)})
+	}
+
+	return doc
+}
+
+// primaryPhone is the phone number marked primary, or the first one
+func primaryPhone(phones []models.UserPhoneNumber) *models.UserPhoneNumber {
+	for i := range phones {
+		if phones[i].IsPrimary {
+			return &phones[i]
+		}
+	}
+	if len(phones) > 0 {
+		return &phones[0]
+	}
+	return nil
+}
+
+// phoneTypes are the types a phone number can have
+var phoneTypes = map[string]bool{"mobile": true, "home": true, "work": true, "other": true}
+
+// mapPhoneNumbers turns imported numbers into phone number requests. Unknown types become
+// other, and only the first number marked primary stays primary, or the first number when
+// none is.
+func mapPhoneNumbers(phones []JSONResumePhone, warn func(string, ...any)) []dto.CreatePhoneNumberRequest {
+	var requests []dto.CreatePhoneNumberRequest
+	primary := -1
+	for i, phone := range phones {
+		number := strings.TrimSpace(phone.Number)
+		if number == "" {
+			warn("phone %d: skipped, number is required", i+1)
+			continue
+		}
+		phoneType := strings.ToLower(phone.Type)
+		if !phoneTypes[phoneType] {
+			if phoneType != "" {
+				warn("phone %d: unknown type %q, using other", i+1, phone.Type)
+			}
+			phoneType = "other"
+		}
+		if phone.IsPrimary && primary < 0 {
+			primary = len(requests)
+		}
+		requests = append(requests, dto.CreatePhoneNumberRequest{PhoneNumber: number, PhoneType: phoneType})
+	}
+	if primary < 0 && len(requests) > 0 {
+		primary = 0
+	}
+	if primary >= 0 {
+		requests[primary].IsPrimary = true
+	}
+	return requests
+}
+
+// optional is nil for a blank value, so an import leaves the field alone
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+func isoDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// workEmploymentTypes are the employment types a work entry can have, volunteering has its own section
+var workEmploymentTypes = map[string]bool{
+	"full-time": true, "part-time": true, "contract": true, "internship": true, "freelance": true,
+}
+
+// resumeCurrent uses the explicit flag when the document has one, otherwise an entry that
+// started and has not ended is taken to be current
+func resumeCurrent(flag *bool, start, end string) bool {
+	if flag != nil {
+		return *flag
+	}
+	return start != "" && end == ""
+}
+
+// normalizeResumeDate accepts the ISO 8601 dates JSON Resume allows and returns YYYY-MM-DD,
+// filling in the first month or day when they are left out
+func normalizeResumeDate(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", true
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// resumeDescription joins a summary and its highlights into one description with a bullet per highlight
+func resumeDescription(summary string, highlights []string) string {
+	lines := []string{}
+	if s := strings.TrimSpace(summary); s != "" {
+		lines = append(lines, s)
+	}
+	for _, h := range highlights {
+		if h = strings.TrimSpace(h); h != "" {
+			lines = append(lines, "• "+h)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -10,8 +10,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrAccountInactive is returned for tokens of accounts scheduled for deletion or purged
+var ErrAccountInactive = errors.New("account is scheduled for deletion")
+
+// AccountStatusChecker tells whether an account may still use the tokens issued to it
+type AccountStatusChecker interface {
+	IsAccountActive(userID uuid.UUID) (bool, error)
+}
+
 type JWTService struct {
 	secretKey []byte
+	accounts  AccountStatusChecker
 }
 
 type Claims struct {
@@ -21,10 +30,11 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func NewJWTService() *JWTService {
+func NewJWTService(accounts AccountStatusChecker) *JWTService {
 	secret := env.GetEnv("JWT_SECRET", "your-secret-key")
 	return &JWTService{
 		secretKey: []byte(secret),
+		accounts:  accounts,
 	}
 }
 
@@ -52,9 +62,18 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
 	}
 
-	return nil, errors.New("invalid token")
+	// Tokens are not revoked when deletion is requested, they stop working here instead
+	active, err := j.accounts.IsAccountActive(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrAccountInactive
+	}
+	return claims, nil
 }
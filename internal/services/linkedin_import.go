@@ -0,0 +1,202 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+)
+
+// ErrInvalidLinkedInArchive is returned when an upload is not a LinkedIn data export
+var ErrInvalidLinkedInArchive = errors.New("not a LinkedIn data export: expected a ZIP with Positions.csv, Education.csv, Skills.csv, Certifications.csv or Projects.csv")
+
+// maxLinkedInCSVSize bounds how much of each CSV is read, so a crafted archive cannot expand without limit
+const maxLinkedInCSVSize = 5 << 20 // 5MB
+
+// linkedInCSV is one CSV of the archive as rows keyed by column name
+type linkedInCSV []map[string]string
+
+// MapLinkedInArchive reads the CSV files of a LinkedIn data export ZIP and turns them into
+// profile entries. Files the profile has no use for are ignored.
+func MapLinkedInArchive(r io.ReaderAt, size int64) (dto.ProfileImport, []string, error) {
+	var data dto.ProfileImport
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return data, nil, ErrInvalidLinkedInArchive
+	}
+
+	files := map[string]linkedInCSV{}
+	for _, file := range archive.File {
+		name := strings.ToLower(path.Base(file.Name))
+		switch name {
+		case "positions.csv", "education.csv", "skills.csv", "certifications.csv", "projects.csv":
+		default:
+			continue
+		}
+		rows, err := readLinkedInCSV(file)
+		if err != nil {
+			warn("%s: skipped, %v", path.Base(file.Name), err)
+			continue
+		}
+		files[name] = rows
+	}
+	if len(files) == 0 {
+		return data, nil, ErrInvalidLinkedInArchive
+	}
+
+	date := func(file string, i int, field, value string) string {
+		d, ok := normalizeLinkedInDate(value)
+		if !ok {
+			warn("%s row %d: ignored %s %q", file, i+1, field, value)
+		}
+		return d
+	}
+
+	for i, row := range files["positions.csv"] {
+		if row["Company Name"] == "" || row["Title"] == "" {
+			warn("Positions.csv row %d: skipped, company name and title are required", i+1)
+			continue
+		}
+		start, end := date("Positions.csv", i, "Started On", row["Started On"]), date("Positions.csv", i, "Finished On", row["Finished On"])
+		data.Experience = append(data.Experience, dto.CreateExperienceRequest{
+			CompanyName:    row["Company Name"],
+			PositionTitle:  row["Title"],
+			EmploymentType: "full-time",
+			StartDate:      start,
+			EndDate:        end,
+			IsCurrent:      start != "" && end == "",
+			Location:       row["Location"],
+			Description:    row["Description"],
+		})
+	}
+
+	for i, row := range files["education.csv"] {
+		if row["School Name"] == "" || row["Degree Name"] == "" {
+			warn("Education.csv row %d: skipped, school name and degree name are required", i+1)
+			continue
+		}
+		start, end := date("Education.csv", i, "Start Date", row["Start Date"]), date("Education.csv", i, "End Date", row["End Date"])
+		description := row["Notes"]
+		if activities := row["Activities"]; activities != "" {
+			if description != "" {
+				description += "\n"
+			}
+			description += "Activities: " + activities
+		}
+		data.Education = append(data.Education, dto.CreateEducationRequest{
+			InstitutionName: row["School Name"],
+			Degree:          row["Degree Name"],
+			StartDate:       start,
+			EndDate:         end,
+			Description:     description,
+		})
+	}
+
+	for i, row := range files["certifications.csv"] {
+		if row["Name"] == "" || row["Authority"] == "" {
+			warn("Certifications.csv row %d: skipped, name and authority are required", i+1)
+			continue
+		}
+		data.Certifications = append(data.Certifications, dto.CreateCertificationRequest{
+			CertificationName:   row["Name"],
+			IssuingOrganization: row["Authority"],
+			IssueDate:           date("Certifications.csv", i, "Started On", row["Started On"]),
+			ExpirationDate:      date("Certifications.csv", i, "Finished On", row["Finished On"]),
+			CredentialID:        row["License Number"],
+			CredentialURL:       row["Url"],
+		})
+	}
+
+	for i, row := range files["projects.csv"] {
+		if row["Title"] == "" {
+			warn("Projects.csv row %d: skipped, title is required", i+1)
+			continue
+		}
+		start, end := date("Projects.csv", i, "Started On", row["Started On"]), date("Projects.csv", i, "Finished On", row["Finished On"])
+		data.Projects = append(data.Projects, dto.CreateProjectRequest{
+			ProjectName: row["Title"],
+			Description: row["Description"],
+			StartDate:   start,
+			EndDate:     end,
+			IsOngoing:   start != "" && end == "",
+			ProjectURL:  row["Url"],
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, row := range files["skills.csv"] {
+		name := row["Name"]
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		data.Skills = append(data.Skills, name)
+	}
+
+	return data, warnings, nil
+}
+
+// readLinkedInCSV reads a CSV file of the archive. Some exports start with a few lines of notes
+// before the header, so the header is the first row with more than one column, or the lone
+// Name column of Skills.csv.
+func readLinkedInCSV(file *zip.File) (linkedInCSV, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(io.LimitReader(f, maxLinkedInCSVSize))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+
+	var header []string
+	rows := linkedInCSV{}
+	for _, record := range records {
+		if header == nil {
+			if len(record) > 1 || (len(record) == 1 && strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff")) == "Name") {
+				header = record
+				header[0] = strings.TrimPrefix(header[0], "\ufeff")
+			}
+			continue
+		}
+		row := map[string]string{}
+		for i, column := range header {
+			if i < len(record) {
+				row[strings.TrimSpace(column)] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// normalizeLinkedInDate accepts the dates LinkedIn exports, such as "Jan 2020" or "2020",
+// and returns YYYY-MM-DD, filling in the first month or day when they are left out
+func normalizeLinkedInDate(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", true
+	}
+	for _, layout := range []string{"Jan 2006", "January 2006", "Jan 2, 2006", "1/2/06", "01/2006", "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
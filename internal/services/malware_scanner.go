@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ScanVerdict is what a scanner found in a file. Signature names the malware when Infected is set.
+type ScanVerdict struct {
+	Infected  bool
+	Signature string
+}
+
+// MalwareScanner checks the content of an uploaded file. An error means the file could not be
+// checked, not that it is infected.
+type MalwareScanner interface {
+	Scan(r io.Reader) (ScanVerdict, error)
+}
+
+// NoopScanner passes every file, for development setups without a virus scanner
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(r io.Reader) (ScanVerdict, error) {
+	return ScanVerdict{}, nil
+}
+
+// clamdChunkSize must stay below clamd's StreamMaxLength, which is checked per chunk as well
+const clamdChunkSize = 64 << 10
+
+// ClamdScanner streams files to a clamd daemon over TCP with the INSTREAM command, see
+// https://docs.clamav.net/manual/Usage/Scanning.html#instream. Files larger than clamd's
+// StreamMaxLength (25MB by default) come back as an error, so raise it to at least the
+// largest media upload.
+type ClamdScanner struct {
+	addr    string
+	timeout time.Duration
+}
+
+// NewClamdScanner scans with the clamd listening at addr (host:port). The timeout applies to
+// each write and to waiting for the verdict, not to the whole scan.
+func NewClamdScanner(addr string, timeout time.Duration) *ClamdScanner {
+	return &ClamdScanner{addr: addr, timeout: timeout}
+}
+
+// Ping checks that clamd is reachable and answering
+func (s *ClamdScanner) Ping() error {
+	reply, err := s.command("zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected clamd reply %q", reply)
+	}
+	return nil
+}
+
+func (s *ClamdScanner) Scan(r io.Reader) (ScanVerdict, error) {
+	reply, err := s.command("zINSTREAM\x00", r)
+	if err != nil {
+		return ScanVerdict{}, err
+	}
+
+	// Replies look like "stream: OK", "stream: Eicar-Signature FOUND" or "... ERROR"
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return ScanVerdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return ScanVerdict{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	case strings.HasSuffix(result, "ERROR"):
+		return ScanVerdict{}, fmt.Errorf("clamd: %s", strings.TrimSpace(strings.TrimSuffix(result, "ERROR")))
+	default:
+		return ScanVerdict{}, fmt.Errorf("unexpected clamd reply %q", reply)
+	}
+}
+
+// command sends one null-terminated command on a new connection, followed by body as
+// length-prefixed chunks when there is one, and reads the null-terminated reply
+func (s *ClamdScanner) command(cmd string, body io.Reader) (string, error) {
+	conn, err := net.DialTimeout("tcp", s.addr, s.timeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(s.timeout))
+	if _, err := io.WriteString(conn, cmd); err != nil {
+		return "", fmt.Errorf("failed to send clamd command: %w", err)
+	}
+
+	if body != nil {
+		if err := s.stream(conn, body); err != nil {
+			// clamd hangs up once a stream is over its limit, its reply says why
+			var netErr *net.OpError
+			if errors.As(err, &netErr) {
+				if reply, replyErr := readClamdReply(conn, s.timeout); replyErr == nil && reply != "" {
+					return reply, nil
+				}
+			}
+			return "", err
+		}
+	}
+	return readClamdReply(conn, s.timeout)
+}
+
+func (s *ClamdScanner) stream(conn net.Conn, body io.Reader) error {
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, err := io.ReadFull(body, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			conn.SetDeadline(time.Now().Add(s.timeout))
+			if _, werr := conn.Write(buf[:4+n]); werr != nil {
+				return fmt.Errorf("failed to stream file to clamd: %w", werr)
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	// A zero length chunk ends the stream
+	conn.SetDeadline(time.Now().Add(s.timeout))
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+	return nil
+}
+
+func readClamdReply(conn net.Conn, timeout time.Duration) (string, error) {
+	conn.SetDeadline(time.Now().Add(timeout))
+	reply, err := bufio.NewReader(io.LimitReader(conn, 4<<10)).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return strings.TrimSpace(strings.TrimSuffix(reply, "\x00")), nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd speaks the zPING and zINSTREAM commands of clamd. reply gets the streamed file
+// and returns what clamd would answer, without the terminating null byte.
+type fakeClamd struct {
+	listener net.Listener
+	reply    func(file []byte) string
+	received chan []byte
+}
+
+func newFakeClamd(t *testing.T, reply func(file []byte) string) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeClamd{listener: listener, reply: reply, received: make(chan []byte, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve(t)
+	return f
+}
+
+func (f *fakeClamd) serve(t *testing.T) {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(t, conn)
+	}
+}
+
+func (f *fakeClamd) handle(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	cmd, err := r.ReadString(0)
+	if err != nil {
+		t.Errorf("fake clamd: read command: %v", err)
+		return
+	}
+
+	switch cmd {
+	case "zPING\x00":
+		io.WriteString(conn, f.reply(nil)+"\x00")
+	case "zINSTREAM\x00":
+		var file bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				t.Errorf("fake clamd: read chunk size: %v", err)
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if size > clamdChunkSize {
+				t.Errorf("fake clamd: chunk of %d bytes, want at most %d", size, clamdChunkSize)
+			}
+			if _, err := io.CopyN(&file, r, int64(size)); err != nil {
+				t.Errorf("fake clamd: read chunk: %v", err)
+				return
+			}
+		}
+		f.received <- file.Bytes()
+		io.WriteString(conn, f.reply(file.Bytes())+"\x00")
+	default:
+		t.Errorf("fake clamd: unexpected command %q", cmd)
+	}
+}
+
+func TestClamdScannerScan(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789abcdef"), clamdChunkSize/8+3)
+
+	tests := []struct {
+		name    string
+		file    []byte
+		reply   string
+		want    ScanVerdict
+		wantErr string
+	}{
+		{name: "clean", file: []byte("hello"), reply: "stream: OK"},
+		{name: "clean in several chunks", file: large, reply: "stream: OK"},
+		{name: "empty file", file: nil, reply: "stream: OK"},
+		{
+			name:  "infected",
+			file:  []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`),
+			reply: "stream: Eicar-Test-Signature FOUND",
+			want:  ScanVerdict{Infected: true, Signature: "Eicar-Test-Signature"},
+		},
+		{name: "size limit", file: []byte("hello"), reply: "INSTREAM size limit exceeded. ERROR", wantErr: "clamd: INSTREAM size limit exceeded."},
+		{name: "scan error", file: []byte("hello"), reply: "stream: Can't allocate memory ERROR", wantErr: "clamd: Can't allocate memory"},
+		{name: "unexpected reply", file: []byte("hello"), reply: "UNKNOWN COMMAND", wantErr: "unexpected clamd reply"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamd := newFakeClamd(t, func([]byte) string { return tt.reply })
+			scanner := NewClamdScanner(clamd.listener.Addr().String(), 5*time.Second)
+
+			verdict, err := scanner.Scan(bytes.NewReader(tt.file))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Scan() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if verdict != tt.want {
+				t.Errorf("Scan() = %+v, want %+v", verdict, tt.want)
+			}
+			if received := <-clamd.received; !bytes.Equal(received, tt.file) {
+				t.Errorf("clamd got %d bytes, want the %d bytes of the file", len(received), len(tt.file))
+			}
+		})
+	}
+}
+
+func TestClamdScannerPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr bool
+	}{
+		{"pong", "PONG", false},
+		{"unexpected reply", "PANG", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clamd := newFakeClamd(t, func([]byte) string { return tt.reply })
+			err := NewClamdScanner(clamd.listener.Addr().String(), 5*time.Second).Ping()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClamdScannerUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	scanner := NewClamdScanner(addr, time.Second)
+	if _, err := scanner.Scan(strings.NewReader("hello")); err == nil {
+		t.Error("Scan() without clamd succeeded, want an error")
+	}
+	if err := scanner.Ping(); err == nil {
+		t.Error("Ping() without clamd succeeded, want an error")
+	}
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"testing"
+)
+
+// tiffWithOrientation is a TIFF structure whose first IFD holds a single orientation entry
+func tiffWithOrientation(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	return tiff
+}
+
+func encodeTestJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 255 / w), uint8(y * 255 / h), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withJFIF puts a JFIF header right after the start of image marker
+func withJFIF(data []byte) []byte {
+	app0 := []byte{0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00, 0x01, 0x01, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00}
+	return append(append(append([]byte{}, data[:2]...), app0...), data[2:]...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		tiff []byte
+		want int
+	}{
+		{"big endian", tiffWithOrientation(binary.BigEndian, 6), 6},
+		{"little endian", tiffWithOrientation(binary.LittleEndian, 8), 8},
+		{"out of range", tiffWithOrientation(binary.BigEndian, 9), 1},
+		{"zero", tiffWithOrientation(binary.LittleEndian, 0), 1},
+		{"truncated entry", tiffWithOrientation(binary.BigEndian, 6)[:20], 1},
+		{"unknown byte order", append([]byte("XX"), tiffWithOrientation(binary.BigEndian, 6)[2:]...), 1},
+		{"too short", []byte("MM"), 1},
+		{"empty", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.tiff); got != tt.want {
+				t.Errorf("exifOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// The source is 3x2:
+	//   a b c
+	//   d e f
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i, c := range "abcdef" {
+		src.Set(i%3, i/3, color.RGBA{uint8(c), 0, 0, 255})
+	}
+
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{1, []string{"abc", "def"}},
+		{2, []string{"cba", "fed"}},
+		{3, []string{"fed", "cba"}},
+		{4, []string{"def", "abc"}},
+		{5, []string{"ad", "be", "cf"}},
+		{6, []string{"da", "eb", "fc"}},
+		{7, []string{"fc", "eb", "da"}},
+		{8, []string{"cf", "be", "ad"}},
+		{9, []string{"abc", "def"}},
+	}
+	for _, tt := range tests {
+		dst := applyOrientation(src, tt.orientation)
+		var got []string
+		for y := dst.Bounds().Min.Y; y < dst.Bounds().Max.Y; y++ {
+			row := ""
+			for x := dst.Bounds().Min.X; x < dst.Bounds().Max.X; x++ {
+				r, _, _, _ := dst.At(x, y).RGBA()
+				row += string(rune(r >> 8))
+			}
+			got = append(got, row)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("orientation %d: got %q, want %q", tt.orientation, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("orientation %d: got %q, want %q", tt.orientation, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestJPEGOrientationMetadata(t *testing.T) {
+	plain := encodeTestJPEG(t, 8, 4)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"without JFIF", plain},
+		{"with JFIF", withJFIF(plain)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != 1 {
+				t.Fatalf("jpegOrientation() before = %d, want 1", got)
+			}
+
+			tagged := withJPEGOrientation(tt.data, 6)
+			if got := jpegOrientation(tagged); got != 6 {
+				t.Errorf("jpegOrientation() = %d, want 6", got)
+			}
+			if _, err := jpeg.Decode(bytes.NewReader(tagged)); err != nil {
+				t.Errorf("tagged image no longer decodes: %v", err)
+			}
+			if bytes.HasPrefix(tt.data[2:], []byte{0xFF, 0xE0}) && !bytes.HasPrefix(tagged[2:], []byte{0xFF, 0xE0}) {
+				t.Errorf("JFIF header is no longer the first segment")
+			}
+
+			stripped, err := stripJPEGMetadata(tagged)
+			if err != nil {
+				t.Fatalf("stripJPEGMetadata() error = %v", err)
+			}
+			if !bytes.Equal(stripped, tt.data) {
+				t.Errorf("stripJPEGMetadata() did not give back the untagged image")
+			}
+		})
+	}
+}
+
+func TestProcessImageOrientation(t *testing.T) {
+	process := func(t *testing.T, data []byte) (*ProcessedMedia, []byte) {
+		t.Helper()
+		processed, err := ProcessMedia(bytes.NewReader(data), int64(len(data)), "image")
+		if err != nil {
+			t.Fatalf("ProcessMedia() error = %v", err)
+		}
+		body, err := io.ReadAll(processed.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+		return processed, body
+	}
+
+	t.Run("rotated pixels", func(t *testing.T) {
+		_, body := process(t, withJPEGOrientation(encodeTestJPEG(t, 8, 4), 6))
+		if got := jpegOrientation(body); got != 1 {
+			t.Errorf("orientation tag = %d, want it gone", got)
+		}
+		config, err := jpeg.DecodeConfig(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if config.Width != 4 || config.Height != 8 {
+			t.Errorf("size = %dx%d, want 4x8", config.Width, config.Height)
+		}
+	})
+
+	t.Run("too large to rotate", func(t *testing.T) {
+		data := withJPEGOrientation(encodeTestJPEG(t, 8, 4), 6)
+		// Claim 8000x6000 in the frame header, only the header is read at that size
+		sof := bytes.Index(data, []byte{0xFF, 0xC0})
+		if sof < 0 {
+			t.Fatal("no baseline frame header")
+		}
+		binary.BigEndian.PutUint16(data[sof+5:], 6000)
+		binary.BigEndian.PutUint16(data[sof+7:], 8000)
+
+		processed, body := process(t, data)
+		if got := jpegOrientation(body); got != 6 {
+			t.Errorf("orientation tag = %d, want 6 kept", got)
+		}
+		if len(processed.Thumbnails) != 0 {
+			t.Errorf("got %d thumbnails, want none", len(processed.Thumbnails))
+		}
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		data := encodeTestJPEG(t, 8, 4)
+		sof := bytes.Index(data, []byte{0xFF, 0xC0})
+		data[sof+9] = 0 // no color components
+		if _, err := ProcessMedia(bytes.NewReader(data), int64(len(data)), "image"); err == nil {
+			t.Error("ProcessMedia() accepted an image that does not decode")
+		}
+	})
+}
+
+func TestProcessImageWebP(t *testing.T) {
+	data, err := os.ReadFile("testdata/gopher.webp")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	sizes := ThumbnailSizes
+	defer func() { ThumbnailSizes = sizes }()
+	ThumbnailSizes = append(ThumbnailSizes[:0:0], sizes[len(sizes)-1])
+	ThumbnailSizes[0].MaxSide = 16
+
+	processed, err := ProcessMedia(bytes.NewReader(data), int64(len(data)), "image")
+	if err != nil {
+		t.Fatalf("ProcessMedia() error = %v", err)
+	}
+	if processed.MimeType != "image/webp" {
+		t.Errorf("mime type = %q, want image/webp", processed.MimeType)
+	}
+	if len(processed.Thumbnails) != 1 || processed.Thumbnails[0].Width > 16 || processed.Thumbnails[0].Height > 16 {
+		t.Errorf("thumbnails = %+v, want one of at most 16x16", processed.Thumbnails)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrMediaTypeNotAllowed is returned when the content of an upload is not one of the allowed types
+	ErrMediaTypeNotAllowed = errors.New("file type not allowed")
+	// ErrMediaTooLarge is returned when an upload is over the size limit of its media type
+	ErrMediaTooLarge = errors.New("file too large")
+)
+
+// MediaPolicy is what may be uploaded as one media type: a size limit and the allowed
+// MIME types with the file extension each is stored under
+type MediaPolicy struct {
+	MaxSize   int64
+	MimeTypes map[string]string
+}
+
+// MediaPolicies are keyed by user_media.media_type
+var MediaPolicies = map[string]MediaPolicy{
+	"image": {
+		MaxSize: 10 << 20,
+		MimeTypes: map[string]string{
+			"image/jpeg": ".jpg",
+			"image/png":  ".png",
+			"image/gif":  ".gif",
+			"image/webp": ".webp",
+		},
+	},
+	"video": {
+		MaxSize: 100 << 20,
+		MimeTypes: map[string]string{
+			"video/mp4":       ".mp4",
+			"video/webm":      ".webm",
+			"video/quicktime": ".mov",
+		},
+	},
+	"document": {
+		MaxSize: 20 << 20,
+		MimeTypes: map[string]string{
+			"application/pdf":    ".pdf",
+			"text/plain":         ".txt",
+			"application/msword": ".doc",
+			"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+			"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+			"application/vnd.oasis.opendocument.text":                                   ".odt",
+		},
+	},
+}
+
+// MaxMediaSize is the largest upload any media type allows
+var MaxMediaSize = func() int64 {
+	var largest int64
+	for _, policy := range MediaPolicies {
+		largest = max(largest, policy.MaxSize)
+	}
+	return largest
+}()
+
+// ProcessedMedia is an upload that passed the checks, ready to be stored
+type ProcessedMedia struct {
+	MediaType  string
+	MimeType   string
+	Extension  string
+	Size       int64
+	Body       io.Reader
+	Thumbnails []Thumbnail
+}
+
+// ProcessMedia works out what an upload is from its content, never from the file name or the
+// client's content type, and checks it against the policy of its media type. When mediaType is
+// empty it follows from the content. Images are stripped of metadata, turned upright and get
+// thumbnails; other files are passed through unchanged.
+func ProcessMedia(file io.ReaderAt, size int64, mediaType string) (*ProcessedMedia, error) {
+	head := make([]byte, 3072)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	mimeType, _, _ := strings.Cut(mimetype.Detect(head[:n]).String(), ";")
+
+	detected := ""
+	for name, policy := range MediaPolicies {
+		if _, ok := policy.MimeTypes[mimeType]; ok {
+			detected = name
+		}
+	}
+	if detected == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMediaTypeNotAllowed, mimeType)
+	}
+	if mediaType != "" && mediaType != detected {
+		return nil, fmt.Errorf("%w: %s is not allowed as %s", ErrMediaTypeNotAllowed, mimeType, mediaType)
+	}
+
+	policy := MediaPolicies[detected]
+	if size > policy.MaxSize {
+		return nil, fmt.Errorf("%w: %s files can be at most %dMB", ErrMediaTooLarge, detected, policy.MaxSize>>20)
+	}
+
+	processed := &ProcessedMedia{
+		MediaType: detected,
+		MimeType:  mimeType,
+		Extension: policy.MimeTypes[mimeType],
+		Size:      size,
+		Body:      io.NewSectionReader(file, 0, size),
+	}
+	if detected == "image" {
+		if err := processImage(processed, io.NewSectionReader(file, 0, size)); err != nil {
+			return nil, err
+		}
+	}
+	return processed, nil
+}
+
+func processImage(processed *ProcessedMedia, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	orientation := 1
+	switch processed.MimeType {
+	case "image/jpeg":
+		orientation = jpegOrientation(data)
+		data, err = stripJPEGMetadata(data)
+	case "image/png":
+		data, err = stripPNGMetadata(data)
+	case "image/webp":
+		data, err = stripWebPMetadata(data)
+	case "image/gif":
+		data, err = stripGIFMetadata(data)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s could not be read", ErrMediaTypeNotAllowed, processed.MimeType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %s could not be decoded", ErrMediaTypeNotAllowed, processed.MimeType)
+	}
+	// Too large to rotate in memory, so the orientation goes back in as the only EXIF tag
+	if config.Width*config.Height > maxThumbnailPixels {
+		if orientation > 1 {
+			data = withJPEGOrientation(data, orientation)
+		}
+		processed.Body, processed.Size = bytes.NewReader(data), int64(len(data))
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %s could not be decoded", ErrMediaTypeNotAllowed, processed.MimeType)
+	}
+
+	// The orientation tag went with the rest of the EXIF data, so rotate the pixels instead
+	if orientation > 1 {
+		img = applyOrientation(img, orientation)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 92}); err != nil {
+			return fmt.Errorf("failed to encode image: %w", err)
+		}
+		data = buf.Bytes()
+	}
+	processed.Body, processed.Size = bytes.NewReader(data), int64(len(data))
+
+	processed.Thumbnails, err = makeThumbnails(img, processed.MimeType != "image/jpeg" && hasTransparency(img))
+	if err != nil {
+		return fmt.Errorf("failed to make thumbnails: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// MediaScanStore hands out uploaded files waiting for a malware scan and records the outcome
+type MediaScanStore interface {
+	GetMediaToScan(limit int) ([]models.MediaScanJob, error)
+	RecordMediaScan(job models.MediaScanJob, status, result string) error
+}
+
+// MediaScanWorker scans uploaded files in the background. Uploads wake it up right away;
+// the interval picks up retries and anything a wake-up missed.
+type MediaScanWorker struct {
+	store     MediaScanStore
+	storage   FileStorage
+	scanner   MalwareScanner
+	interval  time.Duration
+	batchSize int
+	wake      chan struct{}
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewMediaScanWorker(store MediaScanStore, storage FileStorage, scanner MalwareScanner, interval time.Duration) *MediaScanWorker {
+	w := &MediaScanWorker{
+		store:     store,
+		storage:   storage,
+		scanner:   scanner,
+		interval:  interval,
+		batchSize: 20,
+		wake:      make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Wake starts a scan without waiting for the next interval. It never blocks.
+func (w *MediaScanWorker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the worker, waiting for a scan in progress to finish
+func (w *MediaScanWorker) Close() {
+	w.closeOnce.Do(func() {
+		close(w.quit)
+		<-w.done
+	})
+}
+
+func (w *MediaScanWorker) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.scanPending()
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// scanPending works through the queue a batch at a time until it is empty
+func (w *MediaScanWorker) scanPending() {
+	for {
+		jobs, err := w.store.GetMediaToScan(w.batchSize)
+		if err != nil {
+			log.Printf("failed to get media to scan: %v", err)
+			return
+		}
+
+		for _, job := range jobs {
+			select {
+			case <-w.quit:
+				return
+			default:
+			}
+			// Unrecorded files would only come back in the next batch, wait for the next round
+			if !w.scan(job) {
+				return
+			}
+		}
+		if len(jobs) < w.batchSize {
+			return
+		}
+	}
+}
+
+// scan scans one file and reports whether its outcome was recorded
+func (w *MediaScanWorker) scan(job models.MediaScanJob) bool {
+	status, result := models.MediaScanClean, ""
+
+	verdict, err := w.scanFile(job.FilePath)
+	switch {
+	case err != nil:
+		status, result = models.MediaScanError, err.Error()
+		log.Printf("failed to scan media %s: %v", job.MediaID, err)
+	case verdict.Infected:
+		status, result = models.MediaScanQuarantined, verdict.Signature
+		log.Printf("quarantined media %s of user %s: %s", job.MediaID, job.UserID, verdict.Signature)
+	}
+
+	if err := w.store.RecordMediaScan(job, status, result); err != nil {
+		log.Printf("failed to record scan of media %s: %v", job.MediaID, err)
+		return false
+	}
+	return true
+}
+
+func (w *MediaScanWorker) scanFile(key string) (ScanVerdict, error) {
+	file, err := w.storage.Open(key)
+	if err != nil {
+		if errors.Is(err, ErrFileNotFound) {
+			return ScanVerdict{}, errors.New("file is missing from storage")
+		}
+		return ScanVerdict{}, err
+	}
+	defer file.Close()
+
+	return w.scanner.Scan(file)
+}
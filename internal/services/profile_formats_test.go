@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/dto"
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+	"github.com/google/uuid"
+)
+
+func strPtr(s string) *string { return &s }
+
+func datePtr(value string) *time.Time {
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func testProfile() *models.UserProfile {
+	return &models.UserProfile{
+		User: models.User{
+			FullName:     "Jane Q Doe",
+			Email:        "jane@example.com",
+			Location:     strPtr("Cairo"),
+			Title:        strPtr("Backend Engineer"),
+			AboutSection: strPtr("Builds APIs."),
+		},
+		PhoneNumbers: []models.UserPhoneNumber{
+			{ID: uuid.New(), PhoneNumber: "+20 100 000 0000", PhoneType: "work"},
+			{ID: uuid.New(), PhoneNumber: "+20 111 111 1111", PhoneType: "mobile", IsPrimary: true},
+			{ID: uuid.New(), PhoneNumber: "+20 122 222 2222", PhoneType: "other"},
+		},
+		Education: []models.UserEducation{{
+			InstitutionName: "Cairo University",
+			Degree:          "BSc",
+			FieldOfStudy:    strPtr("Computer Science"),
+			StartDate:       datePtr("2015-09-01"),
+			EndDate:         datePtr("2019-06-30"),
+			GradeGPA:        strPtr("3.8"),
+			Description:     strPtr("Thesis on compilers"),
+		}},
+	}
+}
+
+// wantImport is what both formats must give back for testProfile
+func wantImport(aboutSection *string) (dto.UpdateProfileRequest, []dto.CreatePhoneNumberRequest, []dto.CreateEducationRequest) {
+	basics := dto.UpdateProfileRequest{
+		FullName:     strPtr("Jane Q Doe"),
+		Location:     strPtr("Cairo"),
+		Title:        strPtr("Backend Engineer"),
+		AboutSection: aboutSection,
+	}
+	phones := []dto.CreatePhoneNumberRequest{
+		{PhoneNumber: "+20 100 000 0000", PhoneType: "work"},
+		{PhoneNumber: "+20 111 111 1111", PhoneType: "mobile", IsPrimary: true},
+		{PhoneNumber: "+20 122 222 2222", PhoneType: "other"},
+	}
+	education := []dto.CreateEducationRequest{{
+		InstitutionName: "Cairo University",
+		Degree:          "BSc",
+		FieldOfStudy:    "Computer Science",
+		StartDate:       "2015-09-01",
+		EndDate:         "2019-06-30",
+		GradeGPA:        "3.8",
+		Description:     "Thesis on compilers",
+	}}
+	return basics, phones, education
+}
+
+func TestJSONResumeRoundTrip(t *testing.T) {
+	encoded, err := json.Marshal(ProfileToJSONResume(testProfile()))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc JSONResume
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	data, warnings := MapJSONResume(doc)
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	basics, phones, education := wantImport(strPtr("Builds APIs."))
+	if !reflect.DeepEqual(data.Basics, basics) {
+		t.Errorf("basics = %+v, want %+v", data.Basics, basics)
+	}
+	if !reflect.DeepEqual(data.PhoneNumbers, phones) {
+		t.Errorf("phone numbers = %+v, want %+v", data.PhoneNumbers, phones)
+	}
+	if !reflect.DeepEqual(data.Education, education) {
+		t.Errorf("education = %+v, want %+v", data.Education, education)
+	}
+}
+
+func TestEuropassRoundTrip(t *testing.T) {
+	encoded, err := xml.Marshal(ProfileToEuropass(testProfile()))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc EuropassDocument
+	if err := xml.Unmarshal(encoded, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	data, warnings := MapEuropass(doc)
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	// Europass has no about section, and lists the primary number first
+	basics, phones, education := wantImport(nil)
+	phones[0], phones[1] = phones[1], phones[0]
+	if !reflect.DeepEqual(data.Basics, basics) {
+		t.Errorf("basics = %+v, want %+v", data.Basics, basics)
+	}
+	if !reflect.DeepEqual(data.PhoneNumbers, phones) {
+		t.Errorf("phone numbers = %+v, want %+v", data.PhoneNumbers, phones)
+	}
+	if !reflect.DeepEqual(data.Education, education) {
+		t.Errorf("education = %+v, want %+v", data.Education, education)
+	}
+	for _, e := range doc.LearnerInfo.EducationList {
+		if e.Level != nil {
+			t.Errorf("education level = %+v, want none, the grade is not an EQF level", e.Level)
+		}
+	}
+}
+
+func TestJSONResumeSinglePhone(t *testing.T) {
+	var doc JSONResume
+	doc.Basics.Phone = "555-0100"
+	doc.Basics.Location = &JSONResumeLocation{City: "Giza", CountryCode: "EG"}
+
+	data, _ := MapJSONResume(doc)
+	want := []dto.CreatePhoneNumberRequest{{PhoneNumber: "555-0100", PhoneType: "mobile", IsPrimary: true}}
+	if !reflect.DeepEqual(data.PhoneNumbers, want) {
+		t.Errorf("phone numbers = %+v, want %+v", data.PhoneNumbers, want)
+	}
+	if data.Basics.Location == nil || *data.Basics.Location != "Giza, EG" {
+		t.Errorf("location = %v, want Giza, EG", data.Basics.Location)
+	}
+	if data.Basics.FullName != nil {
+		t.Errorf("full name = %q, want it left alone", *data.Basics.FullName)
+	}
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Andrew-Ayman123/Job-Hunter/internal/models"
+)
+
+// Résumé output formats
+const (
+	ResumeFormatHTML     = "html"
+	ResumeFormatMarkdown = "markdown"
+	ResumeFormatPDF      = "pdf"
+)
+
+var (
+	ErrUnknownResumeTemplate = errors.New("unknown resume template")
+	ErrUnknownResumeFormat   = errors.New("unknown resume format")
+)
+
+// resumeTemplate decides which sections a résumé shows, in what order, and how it looks
+type resumeTemplate struct {
+	sections     []string
+	descriptions bool // include entry descriptions, compact résumés leave them out
+	css          string
+	pdf          pdfStyle
+}
+
+// resumeTemplates are the selectable templates, the first in ResumeTemplateNames is the default
+var resumeTemplates = map[string]resumeTemplate{
+	"classic": {
+		sections:     []string{"experience", "education", "projects", "certifications", "skills"},
+		descriptions: true,
+		css: `body{font-family:Georgia,'Times New Roman',serif;color:#222;max-width:800px;margin:40px auto;line-height:1.45}
+h1{margin:0;font-size:2em}h2{border-bottom:1px solid #222;font-size:1.1em;text-transform:uppercase;letter-spacing:.05em;margin-top:1.6em}
+.headline{font-style:italic}.contact{color:#555}.entry{margin-bottom:.9em}.meta{color:#555}.period{float:right;color:#555}`,
+		pdf: pdfStyle{fontSize: 10.5, accent: [3]float64{0, 0, 0}, ruleUnderHeadings: true, spacing: 1.0},
+	},
+	"modern": {
+		sections:     []string{"skills", "experience", "projects", "education", "certifications"},
+		descriptions: true,
+		css: `body{font-family:'Helvetica Neue',Arial,sans-serif;color:#1f2933;max-width:820px;margin:40px auto;line-height:1.5}
+h1{margin:0;font-size:2.2em;color:#1d4ed8}h2{color:#1d4ed8;font-size:1.05em;text-transform:uppercase;letter-spacing:.08em;margin-top:1.8em}
+.headline{font-size:1.15em}.contact{color:#52606d}.entry{margin-bottom:1em}.meta{color:#52606d}.period{float:right;color:#52606d}
+.skills{display:flex;flex-wrap:wrap;gap:6px;padding:0;list-style:none}.skills li{background:#e0e7ff;border-radius:4px;padding:2px 8px}`,
+		pdf: pdfStyle{fontSize: 10.5, accent: [3]float64{0.114, 0.306, 0.847}, spacing: 1.1},
+	},
+	"compact": {
+		sections:     []string{"experience", "education", "skills", "certifications", "projects"},
+		descriptions: false,
+		css: `body{font-family:Arial,sans-serif;font-size:13px;color:#222;max-width:760px;margin:24px auto;line-height:1.3}
+h1{margin:0;font-size:1.6em}h2{font-size:1em;text-transform:uppercase;margin:1em 0 .3em}
+.contact{color:#555}.entry{margin-bottom:.35em}.meta{color:#555}.period{float:right;color:#555}`,
+		pdf: pdfStyle{fontSize: 9, accent: [3]float64{0.2, 0.2, 0.2}, ruleUnderHeadings: true, spacing: 0.85},
+	},
+}
+
+// ResumeTemplateNames lists the available templates, the first is the default
+var ResumeTemplateNames = []string{"classic", "modern", "compact"}
+
+// resumeDocument is a profile laid out as a résumé, independent of the output format
+type resumeDocument struct {
+	Name     string
+	Headline string
+	Contact  []string
+	Summary  string
+	Sections []resumeSection
+}
+
+type resumeSection struct {
+	Title   string
+	Entries []resumeEntry
+	Items   []string // plain lists such as skills
+}
+
+type resumeEntry struct {
+	Title    string
+	Subtitle string
+	Period   string
+	Body     string
+	Link     string
+}
+
+// RenderResume writes the profile as a résumé in the given template and format
+func RenderResume(w io.Writer, profile *models.UserProfile, templateName, format string) error {
+	if templateName == "" {
+		templateName = ResumeTemplateNames[0]
+	}
+	tmpl, ok := resumeTemplates[templateName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownResumeTemplate, templateName)
+	}
+
+	doc := buildResume(profile, tmpl)
+	switch format {
+	case ResumeFormatHTML:
+		return renderResumeHTML(w, doc, tmpl)
+	case ResumeFormatMarkdown:
+		return renderResumeMarkdown(w, doc)
+	case ResumeFormatPDF:
+		return renderResumePDF(w, doc, tmpl.pdf)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownResumeFormat, format)
+	}
+}
+
+// ResumeContentType returns the content type and file extension of a résumé format
+func ResumeContentType(format string) (string, string) {
+	switch format {
+	case ResumeFormatHTML:
+		return "text/html; charset=utf-8", ".html"
+	case ResumeFormatMarkdown:
+		return "text/markdown; charset=utf-8", ".md"
+	default:
+		return "application/pdf", ".pdf"
+	}
+}
+
+func buildResume(profile *models.UserProfile, tmpl resumeTemplate) resumeDocument {
+	user := profile.User
+	doc := resumeDocument{
+		Name:     user.FullName,
+		Headline: deref(user.Title),
+		Summary:  deref(user.AboutSection),
+	}
+
+	if user.Email != "" {
+		doc.Contact = append(doc.Contact, user.Email)
+	}
+	for _, phone := range profile.PhoneNumbers {
+		if phone.IsPrimary || len(profile.PhoneNumbers) == 1 {
+			doc.Contact = append(doc.Contact, phone.PhoneNumber)
+			break
+		}
+	}
+	if user.Location != nil && *user.Location != "" {
+		doc.Contact = append(doc.Contact, *user.Location)
+	}
+
+	body := func(description *string) string {
+		if !tmpl.descriptions {
+			return ""
+		}
+		return strings.TrimSpace(deref(description))
+	}
+
+	for _, name := range tmpl.sections {
+		var section resumeSection
+		switch name {
+		case "experience":
+			section.Title = "Experience"
+			for _, e := range profile.Experience {
+				subtitle := e.CompanyName
+				if e.Location != nil && *e.Location != "" {
+					subtitle += ", " + *e.Location
+				}
+				section.Entries = append(section.Entries, resumeEntry{
+					Title:    e.PositionTitle,
+					Subtitle: subtitle,
+					Period:   resumePeriod(e.StartDate, e.EndDate, e.IsCurrent),
+					Body:     body(e.Description),
+				})
+			}
+		case "education":
+			section.Title = "Education"
+			for _, e := range profile.Education {
+				title := e.Degree
+				if e.FieldOfStudy != nil && *e.FieldOfStudy != "" {
+					title += " in " + *e.FieldOfStudy
+				}
+				subtitle := e.InstitutionName
+				if e.GradeGPA != nil && *e.GradeGPA != "" {
+					subtitle += " (" + *e.GradeGPA + ")"
+				}
+				section.Entries = append(section.Entries, resumeEntry{
+					Title:    title,
+					Subtitle: subtitle,
+					Period:   resumePeriod(e.StartDate, e.EndDate, e.IsCurrent),
+					Body:     body(e.Description),
+				})
+			}
+		case "projects":
+			section.Title = "Projects"
+			for _, p := range profile.Projects {
+				section.Entries = append(section.Entries, resumeEntry{
+					Title:  p.ProjectName,
+					Period: resumePeriod(p.StartDate, p.EndDate, p.IsOngoing),
+					Body:   body(p.Description),
+					Link:   deref(p.ProjectURL),
+				})
+			}
+		case "certifications":
+			section.Title = "Certifications"
+			for _, c := range profile.Certifications {
+				section.Entries = append(section.Entries, resumeEntry{
+					Title:    c.CertificationName,
+					Subtitle: c.IssuingOrganization,
+					Period:   resumeDate(c.IssueDate),
+					Body:     body(c.Description),
+					Link:     deref(c.CredentialURL),
+				})
+			}
+		case "skills":
+			section.Title = "Skills"
+			for _, s := range profile.Skills {
+				section.Items = append(section.Items, s.Name)
+			}
+		}
+		if len(section.Entries) > 0 || len(section.Items) > 0 {
+			doc.Sections = append(doc.Sections, section)
+		}
+	}
+
+	return doc
+}
+
+func resumeDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("Jan 2006")
+}
+
+func resumePeriod(start, end *time.Time, current bool) string {
+	from, to := resumeDate(start), resumeDate(end)
+	if current {
+		to = "Present"
+	}
+	switch {
+	case from == "":
+		return to
+	case to == "" || to == from:
+		return from
+	default:
+		return from + " – " + to
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+var resumeHTML = template.Must(template.New("resume").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Doc.Name}}</title>
+<style>p{white-space:pre-line}{{.CSS}}</style>
+</head>
+<body>
+<header>
+<h1>{{.Doc.Name}}</h1>
+{{- if .Doc.Headline}}
+<div class="headline">{{.Doc.Headline}}</div>
+{{- end}}
+{{- if .Doc.Contact}}
+<div class="contact">{{range $i, $c := .Doc.Contact}}{{if $i}} · {{end}}{{$c}}{{end}}</div>
+{{- end}}
+</header>
+{{- if .Doc.Summary}}
+<section><h2>Summary</h2><p>{{.Doc.Summary}}</p></section>
+{{- end}}
+{{- range .Doc.Sections}}
+<section>
+<h2>{{.Title}}</h2>
+{{- if .Items}}
+<ul class="skills">{{range .Items}}<li>{{.}}</li>{{end}}</ul>
+{{- end}}
+{{- range .Entries}}
+<div class="entry">
+{{- if .Period}}<span class="period">{{.Period}}</span>{{end}}
+<strong>{{if .Link}}<a href="{{.Link}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</strong>
+{{- if .Subtitle}}<div class="meta">{{.Subtitle}}</div>{{end}}
+{{- if .Body}}<p>{{.Body}}</p>{{end}}
+</div>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+func renderResumeHTML(w io.Writer, doc resumeDocument, tmpl resumeTemplate) error {
+	return resumeHTML.Execute(w, struct {
+		Doc resumeDocument
+		CSS template.CSS
+	}{doc, template.CSS(tmpl.css)})
+}
+
+// markdownEscaper escapes the characters that would otherwise be read as Markdown syntax
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`#`, `\#`, `<`, `\<`, `>`, `\>`, `|`, `\|`,
+)
+
+func renderResumeMarkdown(w io.Writer, doc resumeDocument) error {
+	var b bytes.Buffer
+	md := markdownEscaper.Replace
+
+	fmt.Fprintf(&b, "# %s\n\n", md(doc.Name))
+	if doc.Headline != "" {
+		fmt.Fprintf(&b, "**%s**\n\n", md(doc.Headline))
+	}
+	if len(doc.Contact) > 0 {
+		contact := make([]string, len(doc.Contact))
+		for i, c := range doc.Contact {
+			contact[i] = md(c)
+		}
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(contact, " · "))
+	}
+	if doc.Summary != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", md(doc.Summary))
+	}
+
+	for _, section := range doc.Sections {
+		fmt.Fprintf(&b, "## %s\n\n", md(section.Title))
+		if len(section.Items) > 0 {
+			items := make([]string, len(section.Items))
+			for i, item := range section.Items {
+				items[i] = md(item)
+			}
+			fmt.Fprintf(&b, "%s\n\n", strings.Join(items, ", "))
+		}
+		for _, entry := range section.Entries {
+			title := md(entry.Title)
+			if entry.Link != "" {
+				title = fmt.Sprintf("[%s](<%s>)", title, entry.Link)
+			}
+			fmt.Fprintf(&b, "### %s\n\n", title)
+
+			var meta []string
+			if entry.Subtitle != "" {
+				meta = append(meta, md(entry.Subtitle))
+			}
+			if entry.Period != "" {
+				meta = append(meta, md(entry.Period))
+			}
+			if len(meta) > 0 {
+				fmt.Fprintf(&b, "*%s*\n\n", strings.Join(meta, " | "))
+			}
+			if entry.Body != "" {
+				fmt.Fprintf(&b, "%s\n\n", md(entry.Body))
+			}
+		}
+	}
+
+	_, err := w.Write(bytes.TrimRight(b.Bytes(), "\n"))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A small PDF writer for résumés. It only uses the standard Helvetica fonts every PDF
+// reader ships with, so nothing has to be embedded and no external tools are needed.
+
+const (
+	pdfPageWidth  = 595.28 // A4
+	pdfPageHeight = 841.89
+	pdfMargin     = 50.0
+)
+
+// pdfStyle is how a résumé template looks on paper
+type pdfStyle struct {
+	fontSize          float64
+	accent            [3]float64
+	ruleUnderHeadings bool
+	spacing           float64 // line spacing multiplier
+}
+
+type pdfFont string
+
+const (
+	pdfRegular pdfFont = "F1"
+	pdfBold    pdfFont = "F2"
+	pdfItalic  pdfFont = "F3"
+)
+
+var pdfFontNames = map[pdfFont]string{
+	pdfRegular: "Helvetica",
+	pdfBold:    "Helvetica-Bold",
+	pdfItalic:  "Helvetica-Oblique",
+}
+
+var (
+	pdfText  = [3]float64{0.13, 0.13, 0.13}
+	pdfMuted = [3]float64{0.4, 0.4, 0.4}
+)
+
+// Glyph widths of characters 32 to 126 in thousandths of the font size, from the standard font metrics.
+// Helvetica-Oblique shares the Helvetica widths.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// winAnsiSpecials are the characters WinAnsiEncoding places in 128-159
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsi encodes text for the standard fonts, characters they cannot show become '?'
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 32 && r < 127, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiSpecials[r]; ok {
+				out = append(out, b)
+			} else if r >= 32 {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func pdfTextWidth(font pdfFont, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if font == pdfBold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, b := range winAnsi(s) {
+		if b >= 32 && b < 127 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfWrap breaks text into lines no wider than width, keeping the text's own line breaks
+func pdfWrap(font pdfFont, size, width float64, text string) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := ""
+		for _, word := range words {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if pdfTextWidth(font, size, candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// A word wider than the whole line is broken wherever it has to be
+			runes := []rune(word)
+			for pdfTextWidth(font, size, string(runes)) > width {
+				cut := len(runes) - 1
+				for cut > 1 && pdfTextWidth(font, size, string(runes[:cut])) > width {
+					cut--
+				}
+				lines = append(lines, string(runes[:cut]))
+				runes = runes[cut:]
+			}
+			line = string(runes)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfDocument lays out text top to bottom, starting new pages as they fill up
+type pdfDocument struct {
+	style pdfStyle
+	title string
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func (d *pdfDocument) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// reserve moves to a new page unless height fits on the current one
+func (d *pdfDocument) reserve(height float64) {
+	if d.page == nil || d.y-height < pdfMargin {
+		d.newPage()
+	}
+}
+
+func (d *pdfDocument) lineHeight(size float64) float64 {
+	return size * 1.35 * d.style.spacing
+}
+
+func (d *pdfDocument) text(x, y float64, font pdfFont, size float64, color [3]float64, s string) {
+	fmt.Fprintf(d.page, "BT /%s %.2f Tf %.3f %.3f %.3f rg %.2f %.2f Td (%s) Tj ET\n",
+		font, size, color[0], color[1], color[2], x, y, pdfEscape(winAnsi(s)))
+}
+
+// paragraph writes wrapped text at the current position
+func (d *pdfDocument) paragraph(font pdfFont, size float64, color [3]float64, s string) {
+	width := pdfPageWidth - 2*pdfMargin
+	for _, line := range pdfWrap(font, size, width, s) {
+		lh := d.lineHeight(size)
+		d.reserve(lh)
+		d.y -= lh
+		d.text(pdfMargin, d.y+size*0.3, font, size, color, line)
+	}
+}
+
+func (d *pdfDocument) rule(color [3]float64) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f RG 0.6 w %.2f %.2f m %.2f %.2f l S\n",
+		color[0], color[1], color[2], pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+}
+
+func (d *pdfDocument) space(points float64) {
+	d.y -= points * d.style.spacing
+}
+
+// pdfEscape escapes a string for a PDF literal, writing non-ASCII bytes as octal escapes
+func pdfEscape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&sb, "\\%03o", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func renderResumePDF(w io.Writer, doc resumeDocument, style pdfStyle) error {
+	d := &pdfDocument{style: style, title: doc.Name}
+	size := style.fontSize
+	width := pdfPageWidth - 2*pdfMargin
+
+	d.newPage()
+	d.paragraph(pdfBold, size*2.2, style.accent, doc.Name)
+	if doc.Headline != "" {
+		d.paragraph(pdfRegular, size*1.2, pdfText, doc.Headline)
+	}
+	if len(doc.Contact) > 0 {
+		d.paragraph(pdfRegular, size, pdfMuted, strings.Join(doc.Contact, "  ·  "))
+	}
+
+	heading := func(title string) {
+		// Keep a heading together with at least the first line under it
+		d.reserve(size*2 + d.lineHeight(size*1.15) + d.lineHeight(size)*2)
+		d.space(size * 1.2)
+		d.paragraph(pdfBold, size*1.15, style.accent, strings.ToUpper(title))
+		if style.ruleUnderHeadings {
+			d.rule(style.accent)
+		}
+		d.space(size * 0.4)
+	}
+
+	if doc.Summary != "" {
+		heading("Summary")
+		d.paragraph(pdfRegular, size, pdfText, doc.Summary)
+	}
+
+	for _, section := range doc.Sections {
+		heading(section.Title)
+
+		if len(section.Items) > 0 {
+			d.paragraph(pdfRegular, size, pdfText, strings.Join(section.Items, ", "))
+		}
+
+		for i, entry := range section.Entries {
+			if i > 0 {
+				d.space(size * 0.5)
+			}
+
+			// Title on the left with the period right-aligned on its first line
+			periodWidth := 0.0
+			if entry.Period != "" {
+				periodWidth = pdfTextWidth(pdfRegular, size, entry.Period) + 12
+			}
+			for j, line := range pdfWrap(pdfBold, size, width-periodWidth, entry.Title) {
+				lh := d.lineHeight(size)
+				d.reserve(lh)
+				d.y -= lh
+				d.text(pdfMargin, d.y+size*0.3, pdfBold, size, pdfText, line)
+				if j == 0 && entry.Period != "" {
+					d.text(pdfPageWidth-pdfMargin-periodWidth+12, d.y+size*0.3, pdfRegular, size, pdfMuted, entry.Period)
+				}
+			}
+
+			if entry.Subtitle != "" {
+				d.paragraph(pdfItalic, size, pdfMuted, entry.Subtitle)
+			}
+			if entry.Link != "" {
+				d.paragraph(pdfRegular, size*0.9, style.accent, entry.Link)
+			}
+			if entry.Body != "" {
+				d.space(size * 0.2)
+				d.paragraph(pdfRegular, size, pdfText, entry.Body)
+			}
+		}
+	}
+
+	return d.write(w)
+}
+
+// write serializes the document: catalog, page tree, fonts, then each page with its content
+func (d *pdfDocument) write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPageObject = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, font := range []pdfFont{pdfRegular, pdfBold, pdfItalic} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", pdfFontNames[font]))
+	}
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPageObject+2*i+1,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	object(fmt.Sprintf("<< /Title (%s) /Producer (Job Hunter) >>", pdfEscape(winAnsi(d.title))))
+	info := len(offsets)
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, info, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
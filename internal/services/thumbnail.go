@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// ThumbnailSizes are the thumbnails made for every image, by the length of the longest side.
+// Sizes not smaller than the image itself are skipped.
+var ThumbnailSizes = []struct {
+	Name    string
+	MaxSide int
+}{
+	{"large", 1024},
+	{"medium", 480},
+	{"small", 160},
+}
+
+// maxThumbnailPixels keeps decoding within reasonable memory, larger images get no thumbnails
+const maxThumbnailPixels = 40_000_000
+
+// Thumbnail is an encoded, scaled down copy of an image
+type Thumbnail struct {
+	Size      string
+	Width     int
+	Height    int
+	MimeType  string
+	Extension string
+	Data      []byte
+}
+
+// makeThumbnails scales the image down to each of ThumbnailSizes, largest first, each one
+// from the previous. Images with transparency are encoded as PNG, everything else as JPEG.
+func makeThumbnails(src image.Image, transparent bool) ([]Thumbnail, error) {
+	b := src.Bounds()
+	current := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(current, current.Bounds(), src, b.Min, draw.Src)
+
+	thumbnails := []Thumbnail{}
+	for _, size := range ThumbnailSizes {
+		w, h := current.Bounds().Dx(), current.Bounds().Dy()
+		longest := max(w, h)
+		if longest <= size.MaxSide {
+			continue
+		}
+		dw, dh := max(1, w*size.MaxSide/longest), max(1, h*size.MaxSide/longest)
+		current = boxScale(current, dw, dh)
+
+		var buf bytes.Buffer
+		thumbnail := Thumbnail{Size: size.Name, Width: dw, Height: dh}
+		if transparent {
+			thumbnail.MimeType, thumbnail.Extension = "image/png", ".png"
+			if err := png.Encode(&buf, current); err != nil {
+				return nil, err
+			}
+		} else {
+			thumbnail.MimeType, thumbnail.Extension = "image/jpeg", ".jpg"
+			if err := jpeg.Encode(&buf, current, &jpeg.Options{Quality: 85}); err != nil {
+				return nil, err
+			}
+		}
+		thumbnail.Data = buf.Bytes()
+		thumbnails = append(thumbnails, thumbnail)
+	}
+	return thumbnails, nil
+}
+
+// boxScale shrinks an image by averaging the source pixels that fall into each target pixel.
+// Premultiplied RGBA averages correctly across transparent edges.
+func boxScale(src *image.RGBA, dw, dh int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// hasTransparency reports whether any pixel of the image is not fully opaque
+func hasTransparency(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return !o.Opaque()
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSignerVerify(t *testing.T) {
+	signer := NewURLSigner("test-secret")
+	const path = "/api/v1/data-exports/123/download"
+
+	signedQuery := func(t *testing.T, link string) url.Values {
+		t.Helper()
+		u, err := url.Parse(link)
+		if err != nil {
+			t.Fatalf("signed link %q does not parse: %v", link, err)
+		}
+		return u.Query()
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		query func(t *testing.T) url.Values
+		want  error
+	}{
+		{
+			name: "valid link",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				return signedQuery(t, signer.Sign(path, time.Now().Add(time.Hour)))
+			},
+		},
+		{
+			name: "expired link",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				return signedQuery(t, signer.Sign(path, time.Now().Add(-time.Minute)))
+			},
+			want: ErrLinkExpired,
+		},
+		{
+			name: "signed for another path",
+			path: "/api/v1/data-exports/456/download",
+			query: func(t *testing.T) url.Values {
+				return signedQuery(t, signer.Sign(path, time.Now().Add(time.Hour)))
+			},
+			want: ErrInvalidSignature,
+		},
+		{
+			name: "expiry pushed back",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signedQuery(t, signer.Sign(path, time.Now().Add(time.Hour)))
+				query.Set("expires", "99999999999")
+				return query
+			},
+			want: ErrInvalidSignature,
+		},
+		{
+			name: "signed with another secret",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				return signedQuery(t, NewURLSigner("other-secret").Sign(path, time.Now().Add(time.Hour)))
+			},
+			want: ErrInvalidSignature,
+		},
+		{
+			name: "missing signature",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signedQuery(t, signer.Sign(path, time.Now().Add(time.Hour)))
+				query.Del("signature")
+				return query
+			},
+			want: ErrInvalidSignature,
+		},
+		{
+			name:  "no parameters",
+			path:  path,
+			query: func(t *testing.T) url.Values { return url.Values{} },
+			want:  ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signer.Verify(tt.path, tt.query(t))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLSignerSignKeepsPath(t *testing.T) {
+	const path = "/api/v1/media/abc"
+	link := NewURLSigner("test-secret").Sign(path, time.Unix(1700000000, 0))
+	if !strings.HasPrefix(link, path+"?") {
+		t.Fatalf("Sign() = %q, want it to start with %q", link, path+"?")
+	}
+	if !strings.Contains(link, "expires=1700000000") {
+		t.Errorf("Sign() = %q, want the expiry in unix seconds", link)
+	}
+}
@@ -0,0 +1,58 @@
+-- +goose Up
+
+-- Extend the application pipeline with intermediate hiring stages
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
+ALTER TABLE applications ADD CONSTRAINT applications_status_check
+    CHECK (status IN ('pending', 'screening', 'interview', 'offer', 'accepted', 'rejected', 'withdrawn'));
+
+-- Every status an application has been in, used for funnel and time-to-hire analytics
+CREATE TABLE application_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT now() -- NULL for backfilled transitions
+);
+
+CREATE INDEX idx_application_status_history_application ON application_status_history (application_id, changed_at);
+CREATE INDEX idx_applications_applied_at ON applications (applied_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_application_status_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO application_status_history (application_id, from_status, to_status, changed_at)
+        VALUES (NEW.id, NULL, NEW.status, COALESCE(NEW.applied_at, now()));
+    ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO application_status_history (application_id, from_status, to_status)
+        VALUES (NEW.id, OLD.status, NEW.status);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER applications_status_history
+    AFTER INSERT OR UPDATE OF status ON applications
+    FOR EACH ROW EXECUTE FUNCTION record_application_status_change();
+
+-- Backfill history for applications created before this migration. When they moved past
+-- pending is not known, so that transition is left undated and stays out of the time in stage
+-- and time to hire figures while still counting towards the stages reached.
+INSERT INTO application_status_history (application_id, from_status, to_status, changed_at)
+SELECT id, NULL, 'pending', applied_at FROM applications;
+
+INSERT INTO application_status_history (application_id, from_status, to_status, changed_at)
+SELECT id, 'pending', status, NULL FROM applications WHERE status <> 'pending';
+
+-- +goose Down
+DROP TRIGGER IF EXISTS applications_status_history ON applications;
+DROP FUNCTION IF EXISTS record_application_status_change();
+DROP TABLE IF EXISTS application_status_history;
+DROP INDEX IF EXISTS idx_applications_applied_at;
+
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
+UPDATE applications SET status = 'pending' WHERE status IN ('screening', 'interview', 'offer');
+UPDATE applications SET status = 'rejected' WHERE status = 'withdrawn';
+ALTER TABLE applications ADD CONSTRAINT applications_status_check
+    CHECK (status IN ('pending', 'accepted', 'rejected'));
@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- One row per viewer per job per day, so repeat views on the same day are not double counted
+CREATE TABLE job_views (
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    viewer_key TEXT NOT NULL,
+    view_date DATE NOT NULL,
+    first_viewed_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (job_id, viewer_key, view_date)
+);
+
+CREATE INDEX idx_job_views_job_date ON job_views (job_id, view_date);
+
+-- Apply funnel events: the applicant opened the application form, or submitted it
+CREATE TABLE job_apply_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type TEXT NOT NULL CHECK (event_type IN ('apply_start', 'apply_complete')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_job_apply_events_job_created ON job_apply_events (job_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS job_apply_events;
+DROP TABLE IF EXISTS job_views;
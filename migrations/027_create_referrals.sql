@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- Referral links a company member shares for one of the company's jobs
+CREATE TABLE referral_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code TEXT NOT NULL UNIQUE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    UNIQUE (job_id, referrer_id)
+);
+
+-- Applications that arrived through a referral link
+ALTER TABLE applications ADD COLUMN referral_link_id UUID REFERENCES referral_links(id) ON DELETE SET NULL;
+CREATE INDEX idx_applications_referral_link ON applications (referral_link_id);
+
+-- Referral links followed by an applicant who signed up before applying,
+-- so the application can still be attributed once they do apply
+CREATE TABLE pending_referrals (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referral_link_id UUID NOT NULL REFERENCES referral_links(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (user_id, referral_link_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS pending_referrals;
+DROP INDEX IF EXISTS idx_applications_referral_link;
+ALTER TABLE applications DROP COLUMN IF EXISTS referral_link_id;
+DROP TABLE IF EXISTS referral_links;
@@ -0,0 +1,37 @@
+-- +goose Up
+
+-- Jobs go through review before they are visible to applicants.
+-- Jobs that already exist were live, so they start out published.
+ALTER TABLE jobs
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending_review', 'changes_requested', 'rejected', 'published')),
+    ADD COLUMN published_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT now();
+
+UPDATE jobs SET published_at = created_at WHERE status = 'published';
+
+CREATE INDEX idx_jobs_status ON jobs (status, created_at);
+
+-- Trusted companies publish without review
+ALTER TABLE companies ADD COLUMN is_trusted BOOLEAN NOT NULL DEFAULT false;
+
+-- Every moderation decision ever taken on a job
+CREATE TABLE job_moderation_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    admin_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('approve', 'reject', 'request_changes')),
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_job_moderation_decisions_job ON job_moderation_decisions (job_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS job_moderation_decisions;
+ALTER TABLE companies DROP COLUMN IF EXISTS is_trusted;
+DROP INDEX IF EXISTS idx_jobs_status;
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS status;
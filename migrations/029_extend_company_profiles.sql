@@ -0,0 +1,37 @@
+-- +goose Up
+
+-- Public company profile details
+ALTER TABLE companies
+    ADD COLUMN website TEXT,
+    ADD COLUMN logo_path TEXT,
+    ADD COLUMN logo_mime_type TEXT,
+    ADD COLUMN industry TEXT,
+    ADD COLUMN size_band TEXT CHECK (size_band IN ('1-10', '11-50', '51-200', '201-500', '501-1000', '1001-5000', '5001+')),
+    ADD COLUMN headquarters TEXT,
+    ADD COLUMN social_links JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE TABLE company_offices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    city TEXT NOT NULL,
+    country TEXT,
+    address TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_company_offices_company ON company_offices (company_id);
+
+-- Recruiters who own their company's profile and may edit it
+ALTER TABLE recruiters ADD COLUMN is_company_owner BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE recruiters DROP COLUMN IF EXISTS is_company_owner;
+DROP TABLE IF EXISTS company_offices;
+ALTER TABLE companies
+    DROP COLUMN IF EXISTS social_links,
+    DROP COLUMN IF EXISTS headquarters,
+    DROP COLUMN IF EXISTS size_band,
+    DROP COLUMN IF EXISTS industry,
+    DROP COLUMN IF EXISTS logo_mime_type,
+    DROP COLUMN IF EXISTS logo_path,
+    DROP COLUMN IF EXISTS website;
@@ -0,0 +1,84 @@
+-- +goose Up
+
+-- Users following a company to hear about its jobs and news
+CREATE TABLE company_followers (
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (company_id, user_id)
+);
+
+CREATE INDEX idx_company_followers_user ON company_followers (user_id);
+
+-- Short news posts on a company's feed
+CREATE TABLE company_updates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_company_updates_company ON company_updates (company_id, created_at DESC);
+
+-- In-app notifications
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('job_published', 'company_update')),
+    company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+    job_id UUID REFERENCES jobs(id) ON DELETE CASCADE,
+    company_update_id UUID REFERENCES company_updates(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
+
+-- Followers hear about a job the first time it goes live, however it got published
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_followers_of_job() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.published_at IS NOT NULL AND (TG_OP = 'INSERT' OR OLD.published_at IS NULL) THEN
+        INSERT INTO notifications (user_id, type, company_id, job_id, message)
+        SELECT f.user_id, 'job_published', c.id, NEW.id, c.name || ' posted a new job: ' || NEW.title
+        FROM recruiters r
+        INNER JOIN companies c ON c.id = r.company_id
+        INNER JOIN company_followers f ON f.company_id = c.id
+        WHERE r.user_id = NEW.recruiter_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER jobs_notify_followers
+    AFTER INSERT OR UPDATE OF published_at ON jobs
+    FOR EACH ROW EXECUTE FUNCTION notify_followers_of_job();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_followers_of_company_update() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO notifications (user_id, type, company_id, company_update_id, message)
+    SELECT f.user_id, 'company_update', c.id, NEW.id, c.name || ' posted an update'
+    FROM companies c
+    INNER JOIN company_followers f ON f.company_id = c.id
+    WHERE c.id = NEW.company_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER company_updates_notify_followers
+    AFTER INSERT ON company_updates
+    FOR EACH ROW EXECUTE FUNCTION notify_followers_of_company_update();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS company_updates_notify_followers ON company_updates;
+DROP FUNCTION IF EXISTS notify_followers_of_company_update();
+DROP TRIGGER IF EXISTS jobs_notify_followers ON jobs;
+DROP FUNCTION IF EXISTS notify_followers_of_job();
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS company_updates;
+DROP TABLE IF EXISTS company_followers;
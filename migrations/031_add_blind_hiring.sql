@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Blind hiring hides who the applicant is from reviewers until the
+-- application has reached the reveal stage of the pipeline
+ALTER TABLE jobs
+    ADD COLUMN blind_hiring BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN blind_until_status TEXT NOT NULL DEFAULT 'interview'
+        CHECK (blind_until_status IN ('screening', 'interview', 'offer', 'accepted'));
+
+-- +goose Down
+ALTER TABLE jobs
+    DROP COLUMN IF EXISTS blind_until_status,
+    DROP COLUMN IF EXISTS blind_hiring;
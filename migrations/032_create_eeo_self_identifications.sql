@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Voluntary equal-opportunity self-identification given when applying.
+-- Kept apart from applications so nothing that serves recruiters reads it;
+-- it is only ever reported in aggregate to admins.
+CREATE TABLE eeo_self_identifications (
+    application_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    gender TEXT CHECK (gender IN ('female', 'male', 'non_binary', 'other', 'decline')),
+    ethnicity TEXT CHECK (ethnicity IN (
+        'hispanic_or_latino', 'white', 'black_or_african_american', 'asian',
+        'native_hawaiian_or_pacific_islander', 'american_indian_or_alaska_native',
+        'two_or_more', 'decline'
+    )),
+    veteran_status TEXT CHECK (veteran_status IN ('protected_veteran', 'not_a_veteran', 'decline')),
+    disability_status TEXT CHECK (disability_status IN ('yes', 'no', 'decline')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS eeo_self_identifications;
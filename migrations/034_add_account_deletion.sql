@@ -0,0 +1,40 @@
+-- +goose Up
+
+-- Account deletion requests. Login is refused from the moment deletion is requested,
+-- the account is purged once deletion_scheduled_for has passed
+ALTER TABLE users
+    ADD COLUMN deletion_requested_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deletion_scheduled_for TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_deletion_scheduled ON users (deletion_scheduled_for)
+    WHERE deletion_scheduled_for IS NOT NULL AND deleted_at IS NULL;
+
+-- Applications of purged accounts are kept for the company's reporting but no longer identify the applicant
+ALTER TABLE applications ADD COLUMN anonymized_at TIMESTAMP WITH TIME ZONE;
+
+-- Deleting a user row must never take the companies' hiring records with it
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_applicant_id_fkey;
+ALTER TABLE applications ADD CONSTRAINT applications_applicant_id_fkey
+    FOREIGN KEY (applicant_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_recruiter_id_fkey;
+ALTER TABLE jobs ADD CONSTRAINT jobs_recruiter_id_fkey
+    FOREIGN KEY (recruiter_id) REFERENCES recruiters(user_id) ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_recruiter_id_fkey;
+ALTER TABLE jobs ADD CONSTRAINT jobs_recruiter_id_fkey
+    FOREIGN KEY (recruiter_id) REFERENCES recruiters(user_id) ON DELETE CASCADE;
+
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_applicant_id_fkey;
+ALTER TABLE applications ADD CONSTRAINT applications_applicant_id_fkey
+    FOREIGN KEY (applicant_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE applications DROP COLUMN IF EXISTS anonymized_at;
+
+DROP INDEX IF EXISTS idx_users_deletion_scheduled;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS deletion_scheduled_for,
+    DROP COLUMN IF EXISTS deletion_requested_at;
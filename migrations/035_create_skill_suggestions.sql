@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Skills users brought in (for example from an imported résumé) that are not in the skills
+-- table yet. Admins approve a name once and every user who suggested it gets the skill.
+CREATE TABLE skill_suggestions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_skill_suggestions_user_name ON skill_suggestions (user_id, lower(name));
+CREATE INDEX idx_skill_suggestions_pending ON skill_suggestions (lower(name)) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS skill_suggestions;
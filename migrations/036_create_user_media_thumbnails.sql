@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- Scaled down copies of uploaded images, one per size
+CREATE TABLE user_media_thumbnails (
+    media_id UUID NOT NULL REFERENCES user_media(id) ON DELETE CASCADE,
+    size TEXT NOT NULL CHECK (size IN ('small', 'medium', 'large')),
+    file_path TEXT NOT NULL,
+    mime_type TEXT NOT NULL,
+    file_size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    PRIMARY KEY (media_id, size)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_media_thumbnails;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Uploaded files are scanned for malware before anyone but the owner sees them.
+-- Files uploaded before scanning existed are queued like new ones.
+ALTER TABLE user_media
+    ADD COLUMN scan_status TEXT NOT NULL DEFAULT 'pending' CHECK (scan_status IN ('pending', 'clean', 'quarantined', 'error')),
+    ADD COLUMN scan_result TEXT,
+    ADD COLUMN scan_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE;
+
+-- Almost every file ends up clean, the scanner and the admin view only look at the rest
+CREATE INDEX idx_user_media_scan_status ON user_media (scan_status, created_at) WHERE scan_status <> 'clean';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_media_scan_status;
+ALTER TABLE user_media
+    DROP COLUMN IF EXISTS scan_status,
+    DROP COLUMN IF EXISTS scan_result,
+    DROP COLUMN IF EXISTS scan_attempts,
+    DROP COLUMN IF EXISTS scanned_at;
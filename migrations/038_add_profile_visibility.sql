@@ -0,0 +1,35 @@
+-- +goose Up
+
+-- A user can publish their profile at /profiles/{profile_slug}
+ALTER TABLE users
+    ADD COLUMN profile_slug TEXT UNIQUE,
+    ADD COLUMN profile_published BOOLEAN NOT NULL DEFAULT false;
+
+-- Who sees each section of a profile. Sections without a row use the default:
+-- phone numbers are for recruiters, everything else is public.
+CREATE TABLE profile_section_visibility (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section TEXT NOT NULL CHECK (section IN ('phone_numbers', 'education', 'experience', 'certifications', 'projects', 'skills')),
+    visibility TEXT NOT NULL CHECK (visibility IN ('public', 'recruiters', 'private')),
+    PRIMARY KEY (user_id, section)
+);
+
+-- Single entries can override their section, NULL follows it
+ALTER TABLE user_phone_numbers ADD COLUMN visibility TEXT CHECK (visibility IN ('public', 'recruiters', 'private'));
+ALTER TABLE user_education ADD COLUMN visibility TEXT CHECK (visibility IN ('public', 'recruiters', 'private'));
+ALTER TABLE user_experience ADD COLUMN visibility TEXT CHECK (visibility IN ('public', 'recruiters', 'private'));
+ALTER TABLE user_certifications ADD COLUMN visibility TEXT CHECK (visibility IN ('public', 'recruiters', 'private'));
+ALTER TABLE user_projects ADD COLUMN visibility TEXT CHECK (visibility IN ('public', 'recruiters', 'private'));
+ALTER TABLE user_skills ADD COLUMN visibility TEXT CHECK (visibility IN ('public', 'recruiters', 'private'));
+
+-- +goose Down
+ALTER TABLE user_skills DROP COLUMN IF EXISTS visibility;
+ALTER TABLE user_projects DROP COLUMN IF EXISTS visibility;
+ALTER TABLE user_certifications DROP COLUMN IF EXISTS visibility;
+ALTER TABLE user_experience DROP COLUMN IF EXISTS visibility;
+ALTER TABLE user_education DROP COLUMN IF EXISTS visibility;
+ALTER TABLE user_phone_numbers DROP COLUMN IF EXISTS visibility;
+DROP TABLE IF EXISTS profile_section_visibility;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS profile_slug,
+    DROP COLUMN IF EXISTS profile_published;
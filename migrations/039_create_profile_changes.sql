@@ -0,0 +1,82 @@
+-- +goose Up
+
+-- Every change to a profile entry with the row before and after it, so users can see their
+-- history, restore deleted entries and look at their profile as it was at any point in time
+CREATE TABLE profile_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section TEXT NOT NULL CHECK (section IN ('phone_numbers', 'education', 'experience', 'certifications', 'projects', 'skills')),
+    entry_id TEXT NOT NULL, -- the entry's id, or skill_id for skills
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    before JSONB,
+    after JSONB,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_profile_changes_user ON profile_changes (user_id, changed_at DESC);
+CREATE INDEX idx_profile_changes_entry ON profile_changes (user_id, section, entry_id, changed_at DESC);
+
+-- Logged by trigger so imports and any other writer are covered too. Updates that only touch
+-- updated_at are not worth an entry.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_profile_change() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB := CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE to_jsonb(OLD) END;
+    new_row JSONB := CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE to_jsonb(NEW) END;
+    entry JSONB := COALESCE(new_row, old_row);
+BEGIN
+    IF TG_OP = 'UPDATE' AND old_row - 'updated_at' = new_row - 'updated_at' THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO profile_changes (user_id, section, entry_id, action, before, after)
+    VALUES (
+        (entry->>'user_id')::uuid,
+        TG_ARGV[0],
+        COALESCE(entry->>'id', entry->>'skill_id'),
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        old_row,
+        new_row
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER user_phone_numbers_changes AFTER INSERT OR UPDATE OR DELETE ON user_phone_numbers
+    FOR EACH ROW EXECUTE FUNCTION record_profile_change('phone_numbers');
+CREATE TRIGGER user_education_changes AFTER INSERT OR UPDATE OR DELETE ON user_education
+    FOR EACH ROW EXECUTE FUNCTION record_profile_change('education');
+CREATE TRIGGER user_experience_changes AFTER INSERT OR UPDATE OR DELETE ON user_experience
+    FOR EACH ROW EXECUTE FUNCTION record_profile_change('experience');
+CREATE TRIGGER user_certifications_changes AFTER INSERT OR UPDATE OR DELETE ON user_certifications
+    FOR EACH ROW EXECUTE FUNCTION record_profile_change('certifications');
+CREATE TRIGGER user_projects_changes AFTER INSERT OR UPDATE OR DELETE ON user_projects
+    FOR EACH ROW EXECUTE FUNCTION record_profile_change('projects');
+CREATE TRIGGER user_skills_changes AFTER INSERT OR UPDATE OR DELETE ON user_skills
+    FOR EACH ROW EXECUTE FUNCTION record_profile_change('skills');
+
+-- Backfill a create for entries that existed before this migration. Skills have no creation
+-- time, so they only show up in views of the profile from now on.
+INSERT INTO profile_changes (user_id, section, entry_id, action, after, changed_at)
+SELECT user_id, 'phone_numbers', id::text, 'create', to_jsonb(t), COALESCE(created_at, now()) FROM user_phone_numbers t;
+INSERT INTO profile_changes (user_id, section, entry_id, action, after, changed_at)
+SELECT user_id, 'education', id::text, 'create', to_jsonb(t), COALESCE(created_at, now()) FROM user_education t;
+INSERT INTO profile_changes (user_id, section, entry_id, action, after, changed_at)
+SELECT user_id, 'experience', id::text, 'create', to_jsonb(t), COALESCE(created_at, now()) FROM user_experience t;
+INSERT INTO profile_changes (user_id, section, entry_id, action, after, changed_at)
+SELECT user_id, 'certifications', id::text, 'create', to_jsonb(t), COALESCE(created_at, now()) FROM user_certifications t;
+INSERT INTO profile_changes (user_id, section, entry_id, action, after, changed_at)
+SELECT user_id, 'projects', id::text, 'create', to_jsonb(t), COALESCE(created_at, now()) FROM user_projects t;
+INSERT INTO profile_changes (user_id, section, entry_id, action, after)
+SELECT user_id, 'skills', skill_id::text, 'create', to_jsonb(t) FROM user_skills t;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS user_skills_changes ON user_skills;
+DROP TRIGGER IF EXISTS user_projects_changes ON user_projects;
+DROP TRIGGER IF EXISTS user_certifications_changes ON user_certifications;
+DROP TRIGGER IF EXISTS user_experience_changes ON user_experience;
+DROP TRIGGER IF EXISTS user_education_changes ON user_education;
+DROP TRIGGER IF EXISTS user_phone_numbers_changes ON user_phone_numbers;
+DROP FUNCTION IF EXISTS record_profile_change();
+DROP TABLE IF EXISTS profile_changes;
@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- The applicant's profile as recruiters saw it when they applied
+CREATE TABLE application_profile_snapshots (
+    application_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    profile JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+-- Copies of the files attached to the snapshot, kept even if the applicant deletes the originals
+CREATE TABLE application_snapshot_media (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    media_id UUID NOT NULL, -- the original file, the row may be gone
+    file_path TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    media_type TEXT NOT NULL,
+    mime_type TEXT,
+    scan_status TEXT NOT NULL, -- at snapshot time, the original's status wins while it exists
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    PRIMARY KEY (application_id, media_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS application_snapshot_media;
+DROP TABLE IF EXISTS application_profile_snapshots;
@@ -0,0 +1,116 @@
+-- +goose Up
+
+CREATE TABLE skill_categories (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+-- A skill belongs to at most one category and can sit under a broader skill, e.g. React under JavaScript
+ALTER TABLE skills ADD COLUMN category_id INT REFERENCES skill_categories(id) ON DELETE SET NULL;
+ALTER TABLE skills ADD COLUMN parent_id INT REFERENCES skills(id) ON DELETE SET NULL CHECK (parent_id <> id);
+
+-- Other names a skill goes by. Search, imports and adding skills by name resolve them to the skill.
+CREATE TABLE skill_aliases (
+    alias TEXT NOT NULL,
+    skill_id INT NOT NULL REFERENCES skills(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_skill_aliases_alias ON skill_aliases (lower(alias));
+CREATE INDEX idx_skill_aliases_skill_id ON skill_aliases (skill_id);
+CREATE INDEX idx_skills_parent_id ON skills (parent_id);
+
+INSERT INTO skill_categories (name) VALUES
+    ('Programming Languages'),
+    ('Frameworks & Libraries'),
+    ('Web Development'),
+    ('Databases'),
+    ('Cloud & DevOps'),
+    ('Data & AI'),
+    ('Security'),
+    ('Design'),
+    ('Management'),
+    ('Business');
+
+UPDATE skills s SET category_id = c.id
+FROM (VALUES
+    ('JavaScript', 'Programming Languages'),
+    ('Python', 'Programming Languages'),
+    ('Java', 'Programming Languages'),
+    ('C#', 'Programming Languages'),
+    ('Go', 'Programming Languages'),
+    ('Ruby', 'Programming Languages'),
+    ('SQL', 'Databases'),
+    ('HTML', 'Web Development'),
+    ('CSS', 'Web Development'),
+    ('React', 'Frameworks & Libraries'),
+    ('Vue.js', 'Frameworks & Libraries'),
+    ('Node.js', 'Frameworks & Libraries'),
+    ('Django', 'Frameworks & Libraries'),
+    ('Flask', 'Frameworks & Libraries'),
+    ('Spring Boot', 'Frameworks & Libraries'),
+    ('Project Management', 'Management'),
+    ('Agile Methodology', 'Management'),
+    ('UI/UX Design', 'Design'),
+    ('Marketing', 'Business'),
+    ('Sales', 'Business'),
+    ('DevOps', 'Cloud & DevOps'),
+    ('Cloud Computing', 'Cloud & DevOps'),
+    ('AWS', 'Cloud & DevOps'),
+    ('Azure', 'Cloud & DevOps'),
+    ('Machine Learning', 'Data & AI'),
+    ('Data Analysis', 'Data & AI'),
+    ('Cybersecurity', 'Security')
+) AS v(skill, category)
+JOIN skill_categories c ON c.name = v.category
+WHERE s.name = v.skill;
+
+UPDATE skills s SET parent_id = p.id
+FROM (VALUES
+    ('React', 'JavaScript'),
+    ('Vue.js', 'JavaScript'),
+    ('Node.js', 'JavaScript'),
+    ('Django', 'Python'),
+    ('Flask', 'Python'),
+    ('Spring Boot', 'Java'),
+    ('AWS', 'Cloud Computing'),
+    ('Azure', 'Cloud Computing')
+) AS v(skill, parent)
+JOIN skills p ON p.name = v.parent
+WHERE s.name = v.skill;
+
+INSERT INTO skill_aliases (alias, skill_id)
+SELECT v.alias, s.id
+FROM (VALUES
+    ('JS', 'JavaScript'),
+    ('ECMAScript', 'JavaScript'),
+    ('Py', 'Python'),
+    ('C Sharp', 'C#'),
+    ('CSharp', 'C#'),
+    ('Golang', 'Go'),
+    ('HTML5', 'HTML'),
+    ('CSS3', 'CSS'),
+    ('ReactJS', 'React'),
+    ('React.js', 'React'),
+    ('Vue', 'Vue.js'),
+    ('VueJS', 'Vue.js'),
+    ('Node', 'Node.js'),
+    ('NodeJS', 'Node.js'),
+    ('Spring', 'Spring Boot'),
+    ('Agile', 'Agile Methodology'),
+    ('Scrum', 'Agile Methodology'),
+    ('UX Design', 'UI/UX Design'),
+    ('UI Design', 'UI/UX Design'),
+    ('Amazon Web Services', 'AWS'),
+    ('Microsoft Azure', 'Azure'),
+    ('ML', 'Machine Learning'),
+    ('Data Analytics', 'Data Analysis'),
+    ('Information Security', 'Cybersecurity'),
+    ('InfoSec', 'Cybersecurity')
+) AS v(alias, skill)
+JOIN skills s ON s.name = v.skill;
+
+-- +goose Down
+DROP TABLE IF EXISTS skill_aliases;
+ALTER TABLE skills DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE skills DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS skill_categories;
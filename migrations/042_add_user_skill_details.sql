@@ -0,0 +1,39 @@
+-- +goose Up
+
+-- How well and how long a user has used a skill, and the experience and project entries they
+-- used it in. The entry IDs are kept on the row so the profile history carries them too.
+ALTER TABLE user_skills
+    ADD COLUMN proficiency TEXT CHECK (proficiency IN ('beginner', 'intermediate', 'advanced', 'expert')),
+    ADD COLUMN years_used SMALLINT CHECK (years_used BETWEEN 0 AND 70),
+    ADD COLUMN last_used_year SMALLINT CHECK (last_used_year BETWEEN 1950 AND 2100),
+    ADD COLUMN experience_ids UUID[] DEFAULT '{}',
+    ADD COLUMN project_ids UUID[] DEFAULT '{}';
+
+-- Deleting an experience or project entry takes it off the skills it was linked to
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION unlink_skill_entry() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE user_skills
+    SET experience_ids = array_remove(experience_ids, OLD.id),
+        project_ids = array_remove(project_ids, OLD.id)
+    WHERE user_id = OLD.user_id AND (OLD.id = ANY(experience_ids) OR OLD.id = ANY(project_ids));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER user_experience_unlink_skills AFTER DELETE ON user_experience
+    FOR EACH ROW EXECUTE FUNCTION unlink_skill_entry();
+CREATE TRIGGER user_projects_unlink_skills AFTER DELETE ON user_projects
+    FOR EACH ROW EXECUTE FUNCTION unlink_skill_entry();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS user_projects_unlink_skills ON user_projects;
+DROP TRIGGER IF EXISTS user_experience_unlink_skills ON user_experience;
+DROP FUNCTION IF EXISTS unlink_skill_entry();
+ALTER TABLE user_skills
+    DROP COLUMN IF EXISTS project_ids,
+    DROP COLUMN IF EXISTS experience_ids,
+    DROP COLUMN IF EXISTS last_used_year,
+    DROP COLUMN IF EXISTS years_used,
+    DROP COLUMN IF EXISTS proficiency;
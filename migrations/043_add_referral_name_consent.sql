@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Whether the applicant agreed to let the referrer see their name before the application
+-- reaches the job's reveal stage
+ALTER TABLE applications ADD COLUMN share_name_with_referrer BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE applications DROP COLUMN IF EXISTS share_name_with_referrer;
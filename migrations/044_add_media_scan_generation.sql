@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- Every rescan starts a new generation, so a scan that was still running when an admin asked
+-- for another one cannot overwrite the newer outcome
+ALTER TABLE user_media
+    ADD COLUMN scan_generation INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE user_media
+    DROP COLUMN IF EXISTS scan_generation;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- The profile picture gets a visibility like the sections of a profile. It has no entries,
+-- so only the section setting applies; without one the picture stays public.
+ALTER TABLE profile_section_visibility
+    DROP CONSTRAINT profile_section_visibility_section_check,
+    ADD CONSTRAINT profile_section_visibility_section_check
+        CHECK (section IN ('phone_numbers', 'education', 'experience', 'certifications', 'projects', 'skills', 'profile_picture'));
+
+-- +goose Down
+DELETE FROM profile_section_visibility WHERE section = 'profile_picture';
+ALTER TABLE profile_section_visibility
+    DROP CONSTRAINT profile_section_visibility_section_check,
+    ADD CONSTRAINT profile_section_visibility_section_check
+        CHECK (section IN ('phone_numbers', 'education', 'experience', 'certifications', 'projects', 'skills'));
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- Copies of files submitted with applications are scanned on their own, like uploads: their
+-- status no longer follows the original, which may be deleted or rescanned in the meantime.
+ALTER TABLE application_snapshot_media
+    ADD COLUMN scan_result TEXT,
+    ADD COLUMN scan_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN scan_generation INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT application_snapshot_media_scan_status_check
+        CHECK (scan_status IN ('pending', 'clean', 'quarantined', 'error'));
+
+-- Copies taken so far keep the status they were shown with, their original's while it exists
+UPDATE application_snapshot_media m
+SET scan_status = um.scan_status, scanned_at = um.scanned_at
+FROM user_media um
+WHERE um.id = m.media_id;
+
+CREATE INDEX idx_application_snapshot_media_scan_status ON application_snapshot_media (scan_status, created_at) WHERE scan_status <> 'clean';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_application_snapshot_media_scan_status;
+ALTER TABLE application_snapshot_media
+    DROP CONSTRAINT IF EXISTS application_snapshot_media_scan_status_check,
+    DROP COLUMN IF EXISTS scan_result,
+    DROP COLUMN IF EXISTS scan_attempts,
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scan_generation;
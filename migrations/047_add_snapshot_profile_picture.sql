@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- A copy of the profile picture submitted with an application, and the key of the picture it
+-- was copied from, which tells whether the applicant has changed their picture since.
+-- Snapshots taken before stay without one and keep showing the current picture.
+ALTER TABLE application_profile_snapshots
+    ADD COLUMN picture_path TEXT,
+    ADD COLUMN submitted_picture TEXT;
+
+-- +goose Down
+ALTER TABLE application_profile_snapshots
+    DROP COLUMN IF EXISTS submitted_picture,
+    DROP COLUMN IF EXISTS picture_path;